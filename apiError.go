@@ -0,0 +1,191 @@
+package clcv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Error classes of APIError, for use with errors.Is().
+var (
+	// ErrNotFound matches API errors with status 404 (Not Found)
+	ErrNotFound = errors.New("resource not found")
+
+	// ErrConflict matches API errors with status 409 (Conflict)
+	ErrConflict = errors.New("resource conflict")
+
+	// ErrValidation matches API errors caused by an invalid request (400/422, or field-level messages)
+	ErrValidation = errors.New("request validation failed")
+
+	// ErrRateLimited matches API errors with status 429 (Too Many Requests)
+	ErrRateLimited = errors.New("request rate limit exceeded")
+)
+
+// APIError is returned when a CLC API request fails with a non-success HTTP status.
+type APIError struct {
+	// HTTP status code of the response
+	StatusCode int
+
+	// HTTP verb and URL of the failed request
+	Verb, URL string
+
+	// Raw response body (may be empty)
+	Body []byte
+
+	// Error message extracted from @Body, if any
+	Message string
+
+	// Field-level errors, keyed by field name (the key "" refers to the request as a whole).
+	// E.g. {"body.networkId":["The network vlan_1249_10.81.149 is not valid."]}
+	ModelState map[string][]string
+
+	// Validation messages returned by the SBS API
+	ValidationMessages []string
+}
+
+func (e *APIError) Error() string {
+	var msg = e.Message
+
+	if len(e.ModelState) > 0 {
+		if enc, err := json.Marshal(e.ModelState); err == nil {
+			if msg != "" {
+				msg += " "
+			}
+			msg += string(enc)
+		}
+	}
+	if len(e.ValidationMessages) > 0 {
+		msg += fmt.Sprintf(" Details: %q", strings.Join(e.ValidationMessages, ", "))
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("%s (status: %d)", msg, e.StatusCode)
+}
+
+// Is maps @e onto the error classes %ErrNotFound, %ErrConflict, %ErrValidation and %ErrRateLimited.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity ||
+			len(e.ModelState) > 0 || len(e.ValidationMessages) > 0
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// IsNotFound returns true if @err is an APIError caused by a missing resource.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict returns true if @err is an APIError caused by a conflicting resource state.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsValidation returns true if @err is an APIError caused by invalid request parameters.
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsRateLimited returns true if @err is an APIError caused by API throttling.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// newAPIError parses the error response @body of a failed @verb @url request.
+func newAPIError(verb, url string, res *http.Response, body []byte) *APIError {
+	var apiErr = &APIError{
+		StatusCode: res.StatusCode,
+		Verb:       verb,
+		URL:        url,
+		Body:       body,
+	}
+
+	if len(body) == 0 {
+		return apiErr
+	}
+	apiErr.Message = string(body)
+	//
+	// So far 5 different types of response have been observed:
+	// 1) bare JSON string
+	// 2) struct { message: "string" }
+	// 3) struct { message: "string", "modelState": map[string]interface{} }
+	//    E.g.:  {"":["The server must be in Active or Archived state."]}
+	//	      "modelState":{"body.networkId":["The network vlan_1249_10.81.149 is not valid."]}
+	//	      "modelState":{"":["The server must be in Active or Archived state."]}
+	// 4) struct { error: "string" }, e.g. { "error":"Missing required parameter: serverId"}
+	// 5) struct { error: "string", validationMessages: ["string"] } - like (4), with array of messages
+	//
+	if ct, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); ct == "application/json" {
+		/* Code thanks to & inspired by clc-go-cli */
+		var payload struct {
+			Message            *string
+			ModelState         map[string]json.RawMessage
+			Error              *string
+			ValidationMessages []string
+		}
+
+		if err := json.Unmarshal(body, &payload); err != nil {
+			/* Failed to decode as struct, try string (1) next. */
+			var msg string
+			if err = json.Unmarshal(body, &msg); err == nil {
+				apiErr.Message = msg
+			}
+		} else if payload.ModelState != nil {
+			apiErr.ModelState = make(map[string][]string, len(payload.ModelState))
+			for field, msgs := range payload.ModelState {
+				apiErr.ModelState[field] = modelStateMessages(msgs)
+			}
+			apiErr.Message = ""
+			if payload.Message != nil {
+				apiErr.Message = *payload.Message
+			}
+		} else if payload.Message != nil {
+			apiErr.Message = *payload.Message
+		} else if payload.Error != nil {
+			apiErr.Message = fmt.Sprintf("Error - %s", *payload.Error)
+			apiErr.ValidationMessages = payload.ValidationMessages
+		}
+	}
+	return apiErr
+}
+
+// modelStateMessages returns the messages of a modelState entry @raw. These are normally an array of strings;
+// other values are converted to strings, so that an unexpected entry does not spoil the whole error response.
+func modelStateMessages(raw json.RawMessage) []string {
+	var msgs []interface{}
+
+	if err := json.Unmarshal(raw, &msgs); err != nil {
+		msgs = []interface{}{raw}
+	}
+	var res = make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		switch m := m.(type) {
+		case string:
+			res = append(res, m)
+		case json.RawMessage:
+			var s string
+			if json.Unmarshal(m, &s) == nil {
+				res = append(res, s)
+			} else {
+				res = append(res, string(m))
+			}
+		default:
+			if enc, err := json.Marshal(m); err == nil {
+				res = append(res, string(enc))
+			}
+		}
+	}
+	return res
+}
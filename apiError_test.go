@@ -0,0 +1,140 @@
+package clcv2
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// apiError returns the APIError of a response with @status, @contentType and @body.
+func apiError(status int, contentType, body string) *APIError {
+	var res = &http.Response{StatusCode: status, Header: make(http.Header)}
+
+	res.Header.Set("Content-Type", contentType)
+	return newAPIError("POST", "https://api.example.com/v2/servers/ABCD", res, []byte(body))
+}
+
+func TestAPIErrorBodies(t *testing.T) {
+	for _, tc := range []struct {
+		desc, contentType, body string
+
+		message    string
+		modelState map[string][]string
+		validation []string
+		errorText  string
+	}{
+		{
+			desc:      "empty body",
+			errorText: "Bad Request (status: 400)",
+		},
+		{
+			desc:        "non-JSON body",
+			contentType: "text/html",
+			body:        "<html>Service Unavailable</html>",
+			message:     "<html>Service Unavailable</html>",
+		},
+		{
+			desc:        "bare JSON string",
+			contentType: "application/json; charset=utf-8",
+			body:        `"The server was not found."`,
+			message:     "The server was not found.",
+			errorText:   "The server was not found. (status: 400)",
+		},
+		{
+			desc:        "message",
+			contentType: "application/json",
+			body:        `{"message":"The request is invalid."}`,
+			message:     "The request is invalid.",
+		},
+		{
+			desc:        "message and modelState",
+			contentType: "application/json",
+			body:        `{"message":"The request is invalid.","modelState":{"body.networkId":["The network vlan_1249_10.81.149 is not valid."]}}`,
+			message:     "The request is invalid.",
+			modelState:  map[string][]string{"body.networkId": {"The network vlan_1249_10.81.149 is not valid."}},
+			errorText:   `The request is invalid. {"body.networkId":["The network vlan_1249_10.81.149 is not valid."]} (status: 400)`,
+		},
+		{
+			desc:        "modelState without message",
+			contentType: "application/json",
+			body:        `{"modelState":{"":["The server must be in Active or Archived state."]}}`,
+			modelState:  map[string][]string{"": {"The server must be in Active or Archived state."}},
+		},
+		{
+			desc:        "modelState with values that are not string arrays",
+			contentType: "application/json",
+			body:        `{"message":"The request is invalid.","modelState":{"":"whole request","body.cpu":[17,"too many"],"body.ttl":{"min":1},"body.x":null}}`,
+			message:     "The request is invalid.",
+			modelState: map[string][]string{
+				"":         {"whole request"},
+				"body.cpu": {"17", "too many"},
+				"body.ttl": {`{"min":1}`},
+				"body.x":   {},
+			},
+		},
+		{
+			desc:        "error",
+			contentType: "application/json",
+			body:        `{"error":"Missing required parameter: serverId"}`,
+			message:     "Error - Missing required parameter: serverId",
+		},
+		{
+			desc:        "error with validation messages",
+			contentType: "application/json",
+			body:        `{"error":"Invalid request","validationMessages":["retentionDays must be positive","paths must not be empty"]}`,
+			message:     "Error - Invalid request",
+			validation:  []string{"retentionDays must be positive", "paths must not be empty"},
+			errorText:   `Error - Invalid request Details: "retentionDays must be positive, paths must not be empty" (status: 400)`,
+		},
+	} {
+		e := apiError(http.StatusBadRequest, tc.contentType, tc.body)
+
+		if e.Message != tc.message {
+			t.Errorf("%s: expected message %q, got %q", tc.desc, tc.message, e.Message)
+		}
+		if !reflect.DeepEqual(e.ModelState, tc.modelState) {
+			t.Errorf("%s: expected modelState %v, got %v", tc.desc, tc.modelState, e.ModelState)
+		}
+		if !reflect.DeepEqual(e.ValidationMessages, tc.validation) {
+			t.Errorf("%s: expected validation messages %q, got %q", tc.desc, tc.validation, e.ValidationMessages)
+		}
+		if tc.errorText != "" && e.Error() != tc.errorText {
+			t.Errorf("%s: expected error %q, got %q", tc.desc, tc.errorText, e.Error())
+		}
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	var classes = []error{ErrNotFound, ErrConflict, ErrValidation, ErrRateLimited}
+
+	for _, tc := range []struct {
+		status int
+		body   string
+		is     error // nil if none of @classes
+	}{
+		{http.StatusNotFound, "", ErrNotFound},
+		{http.StatusConflict, "", ErrConflict},
+		{http.StatusBadRequest, "", ErrValidation},
+		{http.StatusUnprocessableEntity, "", ErrValidation},
+		{http.StatusInternalServerError, `{"modelState":{"body.name":["The name is invalid."]}}`, ErrValidation},
+		{http.StatusInternalServerError, `{"error":"Invalid","validationMessages":["bad"]}`, ErrValidation},
+		{http.StatusTooManyRequests, "", ErrRateLimited},
+		{http.StatusInternalServerError, `{"message":"Internal error"}`, nil},
+		{http.StatusUnauthorized, "", nil},
+	} {
+		// Wrapping must not hide the error class.
+		err := errors.Wrap(apiError(tc.status, "application/json", tc.body), "request failed")
+
+		for _, class := range classes {
+			if is := errors.Is(err, class); is != (class == tc.is) {
+				t.Errorf("status %d %s: errors.Is(%q) = %t", tc.status, tc.body, class, is)
+			}
+		}
+	}
+	if !IsNotFound(apiError(404, "", "")) || !IsConflict(apiError(409, "", "")) || !IsValidation(apiError(400, "", "")) ||
+		!IsRateLimited(apiError(429, "", "")) || IsNotFound(errors.New("not found")) {
+		t.Errorf("Is* helpers disagree with errors.Is")
+	}
+}
@@ -0,0 +1,252 @@
+package clcv2
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Defaults of AwaitOptions
+const (
+	awaitWorkers       = 8
+	awaitMaxInterval   = 30 * time.Second
+	awaitMaxPollErrors = 3
+)
+
+// AwaitOptions control how AwaitAll polls its jobs.
+type AwaitOptions struct {
+	// Maximum number of concurrent polls (defaults to 8)
+	Workers int
+
+	// Poll interval of a job after its status changed (defaults to 1s). While the status
+	// remains the same, the interval doubles up to @MaxInterval (defaults to 30s).
+	MinInterval, MaxInterval time.Duration
+
+	// Number of consecutive failed polls of a job after which it is given up on (defaults to 3).
+	// Until then, a failed poll is retried like a poll that returned an unchanged status.
+	MaxPollErrors int
+
+	// If set, called for each status change of a job. Calls are sequential, in order of observation.
+	OnEvent func(JobEvent)
+}
+
+// JobEvent is a status change of one of the jobs passed to AwaitAll.
+type JobEvent struct {
+	// Index of the job in the list passed to AwaitAll
+	Index int
+
+	Job    Job
+	Status QueueStatus
+
+	// Time since AwaitAll started
+	Elapsed time.Duration
+}
+
+// JobResult is the outcome of one of the jobs passed to AwaitAll.
+type JobResult struct {
+	Job Job
+
+	// Final status (%Unknown if polling failed)
+	Status QueueStatus
+
+	// Non-nil if polling failed, or the context was canceled
+	Err error
+
+	// Time from the start of AwaitAll until the job completed
+	Elapsed time.Duration
+}
+
+// AwaitSummary summarizes the results of AwaitAll.
+type AwaitSummary struct {
+	// Per-job results, in the order of the jobs passed to AwaitAll
+	Results []JobResult
+
+	// Number of jobs that succeeded, failed, or could not be polled
+	Succeeded, Failed, Errors int
+
+	// Total time taken
+	Elapsed time.Duration
+}
+
+// String returns a one-line summary of @s.
+func (s *AwaitSummary) String() string {
+	return fmt.Sprintf("%d succeeded, %d failed, %d errors after %s",
+		s.Succeeded, s.Failed, s.Errors, s.Elapsed.Round(time.Second))
+}
+
+// Err returns nil if all jobs succeeded, and an error describing the failures otherwise.
+func (s *AwaitSummary) Err() error {
+	if s.Failed+s.Errors == 0 {
+		return nil
+	}
+	for _, r := range s.Results {
+		if r.Err != nil {
+			return errors.Errorf("%d of %d jobs did not succeed (%s: %s)", s.Failed+s.Errors, len(s.Results), r.Job.ID(), r.Err)
+		}
+	}
+	return errors.Wrapf(ErrJobFailed, "%d of %d jobs", s.Failed, len(s.Results))
+}
+
+// awaitState is the polling state of a job within AwaitAll.
+type awaitState struct {
+	status   QueueStatus
+	interval time.Duration
+	next     time.Time // time of next poll
+	queued   bool      // whether waiting for, or being polled by, a worker
+	errors   int       // number of consecutive failed polls
+	done     bool
+}
+
+// awaitPoll is the result of polling job #@index.
+type awaitPoll struct {
+	index  int
+	status QueueStatus
+	err    error
+}
+
+// AwaitAll waits until all @jobs have completed, multiplexing the polls over a bounded pool of workers.
+// Jobs whose status does not change are polled less frequently over time. Status changes are reported
+// as a single stream via @opts.OnEvent. Transient poll errors are tolerated (see AwaitOptions.MaxPollErrors).
+// If @ctx is canceled, the remaining jobs fail with the context error.
+// @opts: polling options, nil selects the defaults
+func AwaitAll(ctx context.Context, jobs []Job, opts *AwaitOptions) *AwaitSummary {
+	var (
+		o       AwaitOptions
+		start   = time.Now()
+		sum     = &AwaitSummary{Results: make([]JobResult, len(jobs))}
+		state   = make([]awaitState, len(jobs))
+		ready   []int // indices of jobs waiting for a worker
+		pending = len(jobs)
+	)
+
+	if opts != nil {
+		o = *opts
+	}
+	if o.Workers <= 0 {
+		o.Workers = awaitWorkers
+	}
+	if o.Workers > len(jobs) {
+		o.Workers = len(jobs)
+	}
+	if o.MinInterval <= 0 {
+		o.MinInterval = statusPollInterval
+	}
+	if o.MaxPollErrors <= 0 {
+		o.MaxPollErrors = awaitMaxPollErrors
+	}
+	if o.MaxInterval < o.MinInterval {
+		o.MaxInterval = awaitMaxInterval
+		if o.MaxInterval < o.MinInterval {
+			o.MaxInterval = o.MinInterval
+		}
+	}
+
+	var (
+		due  = make(chan int)
+		done = make(chan awaitPoll, o.Workers) // buffered, so that workers do not block on cancellation
+	)
+	defer close(due)
+
+	for w := 0; w < o.Workers; w++ {
+		go func() {
+			for i := range due {
+				status, err := jobs[i].Poll(ctx)
+				done <- awaitPoll{i, status, err}
+			}
+		}()
+	}
+
+	finish := func(i int, status QueueStatus, err error) {
+		state[i].done = true
+		sum.Results[i] = JobResult{Job: jobs[i], Status: status, Err: err, Elapsed: time.Since(start)}
+		switch {
+		case err != nil:
+			sum.Errors++
+		case status == Succeeded:
+			sum.Succeeded++
+		default:
+			sum.Failed++
+		}
+		pending--
+	}
+
+	for i := range state {
+		state[i] = awaitState{status: Unknown, interval: o.MinInterval, next: start}
+	}
+
+	for pending > 0 {
+		var (
+			now    = time.Now()
+			wake   time.Time
+			sendC  chan<- int
+			head   int
+			timer  *time.Timer
+			timerC <-chan time.Time
+		)
+
+		for i := range state {
+			if s := &state[i]; s.done || s.queued {
+				continue
+			} else if !s.next.After(now) {
+				ready, s.queued = append(ready, i), true
+			} else if wake.IsZero() || s.next.Before(wake) {
+				wake = s.next
+			}
+		}
+		if len(ready) > 0 {
+			sendC, head = due, ready[0]
+		}
+		if !wake.IsZero() {
+			timer = time.NewTimer(wake.Sub(now))
+			timerC = timer.C
+		}
+
+		select {
+		case sendC <- head:
+			ready = ready[1:]
+		case p := <-done:
+			var s = &state[p.index]
+
+			s.queued = false
+			if p.err != nil {
+				if s.errors++; s.errors >= o.MaxPollErrors || ctx.Err() != nil {
+					finish(p.index, Unknown, p.err)
+				} else {
+					if s.interval *= 2; s.interval > o.MaxInterval {
+						s.interval = o.MaxInterval
+					}
+					s.next = time.Now().Add(s.interval)
+				}
+				break
+			}
+			s.errors = 0
+			if p.status != s.status {
+				s.status, s.interval = p.status, o.MinInterval
+				if o.OnEvent != nil {
+					o.OnEvent(JobEvent{Index: p.index, Job: jobs[p.index], Status: p.status, Elapsed: time.Since(start)})
+				}
+			} else if s.interval *= 2; s.interval > o.MaxInterval {
+				s.interval = o.MaxInterval
+			}
+			if p.status == Succeeded || p.status == Failed {
+				finish(p.index, p.status, nil)
+			} else {
+				s.next = time.Now().Add(s.interval)
+			}
+		case <-timerC:
+		case <-ctx.Done():
+			for i := range state {
+				if !state[i].done {
+					finish(i, state[i].status, ctx.Err())
+				}
+			}
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+	sum.Elapsed = time.Since(start)
+	return sum
+}
@@ -0,0 +1,117 @@
+package clcv2
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// pollResult is one scripted result of a scriptedJob.
+type pollResult struct {
+	status QueueStatus
+	err    error
+}
+
+// scriptedJob is a Job whose polls return the scripted @results in turn, repeating the last one.
+type scriptedJob struct {
+	id string
+
+	mu      sync.Mutex
+	results []pollResult
+	polls   int
+}
+
+func (j *scriptedJob) ID() string                        { return j.id }
+func (j *scriptedJob) Status() QueueStatus               { return Unknown }
+func (j *scriptedJob) Wait(context.Context) error        { return errors.New("not implemented") }
+func (j *scriptedJob) OnProgress(func(Job, QueueStatus)) {}
+
+func (j *scriptedJob) Poll(context.Context) (QueueStatus, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	r := j.results[len(j.results)-1]
+	if j.polls < len(j.results) {
+		r = j.results[j.polls]
+	}
+	j.polls++
+	return r.status, r.err
+}
+
+var errPoll = errors.New("connection reset")
+
+func TestAwaitAll(t *testing.T) {
+	var jobs = []*scriptedJob{
+		{id: "succeeds", results: []pollResult{{NotStarted, nil}, {Executing, nil}, {Succeeded, nil}}},
+		{id: "transient", results: []pollResult{{Executing, nil}, {Unknown, errPoll}, {Unknown, errPoll}, {Executing, nil}, {Unknown, errPoll}, {Succeeded, nil}}},
+		{id: "fails", results: []pollResult{{Executing, nil}, {Failed, nil}}},
+		{id: "unreachable", results: []pollResult{{Executing, nil}, {Unknown, errPoll}}},
+	}
+	var events = make(map[string][]QueueStatus)
+	var all = make([]Job, len(jobs))
+
+	for i := range jobs {
+		all[i] = jobs[i]
+	}
+	sum := AwaitAll(context.Background(), all, &AwaitOptions{
+		Workers:       2,
+		MinInterval:   time.Millisecond,
+		MaxInterval:   4 * time.Millisecond,
+		MaxPollErrors: 3,
+		OnEvent: func(e JobEvent) {
+			if all[e.Index] != e.Job {
+				t.Errorf("event of %s has index %d", e.Job.ID(), e.Index)
+			}
+			events[e.Job.ID()] = append(events[e.Job.ID()], e.Status)
+		},
+	})
+
+	if sum.Succeeded != 2 || sum.Failed != 1 || sum.Errors != 1 {
+		t.Errorf("unexpected summary %s", sum)
+	}
+	for i, expected := range []struct {
+		status QueueStatus
+		err    error
+	}{
+		{Succeeded, nil},
+		{Succeeded, nil},
+		{Failed, nil},
+		{Unknown, errPoll},
+	} {
+		if r := sum.Results[i]; r.Job != all[i] || r.Status != expected.status || r.Err != expected.err {
+			t.Errorf("%s: expected %s/%v, got %s/%v", jobs[i].id, expected.status, expected.err, r.Status, r.Err)
+		}
+	}
+	// Polling gives up after 3 consecutive errors.
+	if n := jobs[3].polls; n != 4 {
+		t.Errorf("expected the unreachable job to be polled 4 times, got %d", n)
+	}
+	if s := events["succeeds"]; len(s) != 3 || s[0] != NotStarted || s[2] != Succeeded {
+		t.Errorf("unexpected events %v", s)
+	}
+	if err := sum.Err(); err == nil {
+		t.Errorf("expected the summary to report an error")
+	}
+}
+
+// Jobs that are still pending when the context expires fail with the context error.
+func TestAwaitAllCanceled(t *testing.T) {
+	var pending = &scriptedJob{id: "pending", results: []pollResult{{Executing, nil}}}
+	var done = &scriptedJob{id: "done", results: []pollResult{{Succeeded, nil}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	sum := AwaitAll(ctx, []Job{pending, done}, &AwaitOptions{MinInterval: time.Millisecond})
+	if sum.Succeeded != 1 || sum.Errors != 1 {
+		t.Errorf("unexpected summary %s", sum)
+	} else if r := sum.Results[0]; r.Err != context.DeadlineExceeded || r.Status != Executing {
+		t.Errorf("expected the pending job to fail with the deadline, got %s/%v", r.Status, r.Err)
+	}
+	if err := sum.Err(); err == nil {
+		t.Errorf("expected the summary to report an error")
+	}
+}
@@ -0,0 +1,244 @@
+package clcv2
+
+/*
+ * Batch operations on multiple servers, using the array-based /v2/operations endpoints.
+ */
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BatchSize is the maximum number of servers per batch request. Larger batches are split into chunks.
+var BatchSize = 50
+
+// BatchResult is the outcome of a batch operation on a single server.
+type BatchResult struct {
+	// ID of the server
+	Server string
+
+	// Queue ID of the operation on @Server (empty if @Err is set)
+	StatusID string
+
+	// Non-nil if the operation on @Server was not queued (the message names @Server)
+	Err error
+}
+
+// BatchError reports the servers of a batch operation that failed (partial failure).
+type BatchError struct {
+	// Results of the servers whose operation was not queued
+	Failed []BatchResult
+
+	// Total number of servers in the batch
+	Total int
+}
+
+// Error implements error.
+func (e *BatchError) Error() string {
+	var msgs = make([]string, 0, len(e.Failed))
+
+	for _, r := range e.Failed {
+		msgs = append(msgs, r.Err.Error())
+	}
+	return fmt.Sprintf("%d of %d servers failed: %s", len(e.Failed), e.Total, strings.Join(msgs, "; "))
+}
+
+// batchError returns a *BatchError describing the failures among @res, or nil if there are none.
+func batchError(res []BatchResult) error {
+	var e = &BatchError{Total: len(res)}
+
+	for _, r := range res {
+		if r.Err != nil {
+			e.Failed = append(e.Failed, r)
+		}
+	}
+	if len(e.Failed) == 0 {
+		return nil
+	}
+	return e
+}
+
+// batchOperation POSTs to the array-based operations endpoint @path for each chunk of @serverIds.
+// @body: returns the request body for a chunk of server IDs
+// Returns one result per server, in the order of @serverIds, and a *BatchError if any of them failed.
+func (c *Client) batchOperation(ctx context.Context, path string, serverIds []string, body func(chunk []string) interface{}) ([]BatchResult, error) {
+	var res = make([]BatchResult, len(serverIds))
+	var chunkSize = BatchSize
+
+	if chunkSize <= 0 {
+		chunkSize = len(serverIds)
+	}
+	for i := range serverIds {
+		res[i].Server = serverIds[i]
+	}
+
+	for start := 0; start < len(serverIds); start += chunkSize {
+		var end = start + chunkSize
+		var status []StatusResponse
+
+		if end > len(serverIds) {
+			end = len(serverIds)
+		}
+		if err := c.getCLCResponse(ctx, "POST", path, body(serverIds[start:end]), &status); err != nil {
+			if ctx.Err() != nil { // do not attempt the remaining chunks
+				end = len(serverIds)
+			}
+			for i := start; i < end; i++ {
+				res[i].Err = errors.Errorf("%s: %s", serverIds[i], err)
+			}
+			continue
+		}
+		matchStatusResponses(res[start:end], status)
+		for _, r := range res[start:end] {
+			c.journalSubmit("POST", path, r.Server, r.StatusID)
+		}
+	}
+	return res, batchError(res)
+}
+
+// matchStatusResponses fills in @res from the per-server @status responses of a batch request.
+func matchStatusResponses(res []BatchResult, status []StatusResponse) {
+	var byServer = make(map[string]StatusResponse, len(status))
+
+	for _, s := range status {
+		byServer[strings.ToUpper(s.Server)] = s
+	}
+	for i := range res {
+		s, ok := byServer[strings.ToUpper(res[i].Server)]
+		if !ok && len(status) == len(res) && status[i].Server == "" {
+			s, ok = status[i], true
+			s.Server = res[i].Server
+		}
+		if !ok {
+			res[i].Err = errors.Errorf("%s: no status response from server", res[i].Server)
+		} else if err := s.err(); err != nil {
+			res[i].Err = err
+		} else if link, err := extractLink(s.Links, "status"); err != nil {
+			res[i].Err = errors.Errorf("%s: %s", res[i].Server, err)
+		} else {
+			res[i].StatusID = link.Id
+		}
+	}
+}
+
+/*
+ * Power operations
+ */
+func (c *Client) serverPowerOperations(ctx context.Context, op string, serverIds []string) ([]BatchResult, error) {
+	var path = fmt.Sprintf("/v2/operations/%s/servers/%s", c.AccountAlias, op)
+
+	return c.batchOperation(ctx, path, serverIds, func(chunk []string) interface{} { return chunk })
+}
+
+// PauseServers sends the pause operation to @serverIds.
+// Returns one result per server, and a *BatchError if the operation failed for any of them.
+func (c *Client) PauseServers(serverIds []string) ([]BatchResult, error) {
+	return c.PauseServersContext(c.ctx, serverIds)
+}
+
+// PauseServersContext is like PauseServers, using @ctx for cancellation.
+func (c *Client) PauseServersContext(ctx context.Context, serverIds []string) ([]BatchResult, error) {
+	return c.serverPowerOperations(ctx, "pause", serverIds)
+}
+
+// PowerOnServers sends the power-on operation to @serverIds (see PauseServers for the results).
+func (c *Client) PowerOnServers(serverIds []string) ([]BatchResult, error) {
+	return c.PowerOnServersContext(c.ctx, serverIds)
+}
+
+// PowerOnServersContext is like PowerOnServers, using @ctx for cancellation.
+func (c *Client) PowerOnServersContext(ctx context.Context, serverIds []string) ([]BatchResult, error) {
+	return c.serverPowerOperations(ctx, "powerOn", serverIds)
+}
+
+// PowerOffServers sends the (hard) power-off operation to @serverIds (see PauseServers for the results).
+func (c *Client) PowerOffServers(serverIds []string) ([]BatchResult, error) {
+	return c.PowerOffServersContext(c.ctx, serverIds)
+}
+
+// PowerOffServersContext is like PowerOffServers, using @ctx for cancellation.
+func (c *Client) PowerOffServersContext(ctx context.Context, serverIds []string) ([]BatchResult, error) {
+	return c.serverPowerOperations(ctx, "powerOff", serverIds)
+}
+
+// ShutdownServers sends the (soft) shut-down operation to @serverIds (see PauseServers for the results).
+func (c *Client) ShutdownServers(serverIds []string) ([]BatchResult, error) {
+	return c.ShutdownServersContext(c.ctx, serverIds)
+}
+
+// ShutdownServersContext is like ShutdownServers, using @ctx for cancellation.
+func (c *Client) ShutdownServersContext(ctx context.Context, serverIds []string) ([]BatchResult, error) {
+	return c.serverPowerOperations(ctx, "shutDown", serverIds)
+}
+
+// RebootServers sends the reboot operation to @serverIds (see PauseServers for the results).
+func (c *Client) RebootServers(serverIds []string) ([]BatchResult, error) {
+	return c.RebootServersContext(c.ctx, serverIds)
+}
+
+// RebootServersContext is like RebootServers, using @ctx for cancellation.
+func (c *Client) RebootServersContext(ctx context.Context, serverIds []string) ([]BatchResult, error) {
+	return c.serverPowerOperations(ctx, "reboot", serverIds)
+}
+
+// ResetServers sends the reset operation to @serverIds (see PauseServers for the results).
+func (c *Client) ResetServers(serverIds []string) ([]BatchResult, error) {
+	return c.ResetServersContext(c.ctx, serverIds)
+}
+
+// ResetServersContext is like ResetServers, using @ctx for cancellation.
+func (c *Client) ResetServersContext(ctx context.Context, serverIds []string) ([]BatchResult, error) {
+	return c.serverPowerOperations(ctx, "reset", serverIds)
+}
+
+/*
+ * Snapshots and maintenance mode
+ */
+// CreateSnapshots creates a snapshot of each of @serverIds, keeping it for @daysToKeep (1..10) days.
+// Returns one result per server, and a *BatchError if the operation failed for any of them.
+func (c *Client) CreateSnapshots(serverIds []string, daysToKeep int) ([]BatchResult, error) {
+	return c.CreateSnapshotsContext(c.ctx, serverIds, daysToKeep)
+}
+
+// CreateSnapshotsContext is like CreateSnapshots, using @ctx for cancellation.
+func (c *Client) CreateSnapshotsContext(ctx context.Context, serverIds []string, daysToKeep int) ([]BatchResult, error) {
+	var path = fmt.Sprintf("/v2/operations/%s/servers/createSnapshot", c.AccountAlias)
+
+	return c.batchOperation(ctx, path, serverIds, func(chunk []string) interface{} {
+		return &struct {
+			ServerIds              []string `json:"serverIds"`
+			SnapshotExpirationDays int      `json:"snapshotExpirationDays"`
+		}{chunk, daysToKeep}
+	})
+}
+
+// SetMaintenanceMany enables (true) or disables (false) maintenance mode on each server in @modes.
+// Returns one result per server, sorted by server ID, and a *BatchError if the operation failed for any of them.
+func (c *Client) SetMaintenanceMany(modes map[string]bool) ([]BatchResult, error) {
+	return c.SetMaintenanceManyContext(c.ctx, modes)
+}
+
+// SetMaintenanceManyContext is like SetMaintenanceMany, using @ctx for cancellation.
+func (c *Client) SetMaintenanceManyContext(ctx context.Context, modes map[string]bool) ([]BatchResult, error) {
+	var path = fmt.Sprintf("/v2/operations/%s/servers/setMaintenance", c.AccountAlias)
+	var serverIds = make([]string, 0, len(modes))
+
+	for id := range modes {
+		serverIds = append(serverIds, id)
+	}
+	sort.Strings(serverIds)
+
+	return c.batchOperation(ctx, path, serverIds, func(chunk []string) interface{} {
+		var servers = make([]MaintenanceMode, len(chunk))
+
+		for i, id := range chunk {
+			servers[i] = MaintenanceMode{id, modes[id]}
+		}
+		return &struct {
+			Servers []MaintenanceMode `json:"servers"`
+		}{servers}
+	})
+}
@@ -0,0 +1,113 @@
+package clcv2_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/grrtrr/clcv2/clcv2test"
+	"github.com/pkg/errors"
+)
+
+// batchServers returns a fake server with the servers WEB, DB and APP in WA1, and their names.
+func batchServers(t *testing.T) (*clcv2test.FakeServer, []string) {
+	var f = clcv2test.NewFakeServer("ABCD", "WA1")
+	var names []string
+
+	for _, name := range []string{"WEB", "DB", "APP"} {
+		server, err := f.AddServer(f.RootGroup("WA1"), name)
+		if err != nil {
+			f.Close()
+			t.Fatal(err)
+		}
+		names = append(names, server)
+	}
+	return f, names
+}
+
+// setBatchSize sets clcv2.BatchSize to @n, returning a function that restores it.
+func setBatchSize(n int) func() {
+	var old = clcv2.BatchSize
+
+	clcv2.BatchSize = n
+	return func() { clcv2.BatchSize = old }
+}
+
+// Results are in the order of the request, across chunks, and failures of single servers do not affect the others.
+func TestBatchOrderAndPartialFailure(t *testing.T) {
+	f, names := batchServers(t)
+	defer f.Close()
+	defer setBatchSize(2)()
+
+	client, err := f.Client()
+	if err != nil {
+		t.Fatalf("login failed: %s", err)
+	}
+
+	var missing = "WA1ABCDNOPE01"
+	var ids = []string{names[2], missing, names[0], names[1]}
+	var batchErr *clcv2.BatchError
+
+	res, err := client.PowerOffServers(ids)
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a *BatchError, got %v", err)
+	} else if batchErr.Total != 4 || len(batchErr.Failed) != 1 || batchErr.Failed[0].Server != missing {
+		t.Errorf("unexpected batch error %+v", batchErr)
+	}
+	if len(res) != len(ids) {
+		t.Fatalf("expected %d results, got %d", len(ids), len(res))
+	}
+
+	var jobs []clcv2.Job
+	for i, r := range res {
+		if r.Server != ids[i] {
+			t.Errorf("result %d: expected server %s, got %s", i, ids[i], r.Server)
+		} else if r.Server == missing {
+			if r.Err == nil || r.StatusID != "" || !strings.Contains(r.Err.Error(), missing) {
+				t.Errorf("expected an error naming %s, got %+v", missing, r)
+			}
+		} else if r.Err != nil || r.StatusID == "" {
+			t.Errorf("%s: expected a status ID, got %+v", r.Server, r)
+		} else {
+			jobs = append(jobs, client.StatusJob(r.StatusID))
+		}
+	}
+
+	f.CompleteJobs()
+	if err := clcv2.AwaitAll(client.Context(), jobs, nil).Err(); err != nil {
+		t.Errorf("jobs did not succeed: %s", err)
+	}
+	for _, name := range names {
+		if s, _ := f.ServerState(name); s.Details.PowerState != "stopped" {
+			t.Errorf("%s: expected power state stopped, got %q", name, s.Details.PowerState)
+		}
+	}
+}
+
+// A failed chunk fails all of its servers, but not those of the other chunks.
+func TestBatchFailedChunk(t *testing.T) {
+	f, names := batchServers(t)
+	defer f.Close()
+	defer setBatchSize(2)()
+
+	client, err := f.Client(clcv2.WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("login failed: %s", err)
+	}
+
+	f.FailRequests("POST", "/v2/operations/ABCD/servers/reboot", 1, http.StatusInternalServerError, nil)
+	res, err := client.RebootServers(names)
+
+	var batchErr *clcv2.BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Failed) != 2 {
+		t.Fatalf("expected the first chunk of 2 servers to fail, got %v", err)
+	}
+	for i, r := range res {
+		if failed := i < 2; r.Server != names[i] || (r.Err != nil) != failed || (r.StatusID == "") != failed {
+			t.Errorf("result %d: unexpected %+v", i, r)
+		} else if failed && !strings.HasPrefix(r.Err.Error(), names[i]+":") {
+			t.Errorf("result %d: expected the error to name %s, got %q", i, names[i], r.Err)
+		}
+	}
+}
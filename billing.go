@@ -1,6 +1,7 @@
 package clcv2
 
 import (
+	"context"
 	"time"
 	"fmt"
 )
@@ -91,11 +92,16 @@ type InvoiceData struct {
 // @date:           Date of the invoice data (only month and year are used)..
 // @pricingAccount: Short code of the account that sends the invoice for the account alias.
 func (c *Client) GetInvoiceData(year, month int, pricingAccount string) (res InvoiceData, err error) {
+	return c.GetInvoiceDataContext(c.ctx, year, month, pricingAccount)
+}
+
+// GetInvoiceDataContext is like GetInvoiceData, using @ctx for cancellation.
+func (c *Client) GetInvoiceDataContext(ctx context.Context, year, month int, pricingAccount string) (res InvoiceData, err error) {
 	path := fmt.Sprintf("/v2/invoice/%s/%4d/%d", c.AccountAlias, year, month)
 	if pricingAccount != "" {
 		path += "?pricingAccount=" + pricingAccount
 	}
-	err = c.getCLCResponse("GET", path, nil, &res)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
@@ -0,0 +1,246 @@
+package clcv2
+
+/*
+ * Opt-in cache of GET responses.
+ */
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Directory in CLC_HOME that holds the persistent response caches
+const cacheDir = "cache"
+
+// CacheTTLs maps resource kinds to the time that their GET responses remain cached. The kind of a resource is the
+// second segment of its API path, e.g. "groups" for /v2/groups/{accountAlias}/{id}, or "networks" for
+// /v2-experimental/networks/{accountAlias}/{location}. Other kinds are not cached.
+type CacheTTLs map[string]time.Duration
+
+// DefaultCacheTTLs are the TTLs suitable for interactive use.
+var DefaultCacheTTLs = CacheTTLs{
+	"datacenters": time.Hour,
+	"groups":      time.Minute,
+	"servers":     30 * time.Second,
+	"networks":    5 * time.Minute,
+}
+
+// Mutations of one resource kind that also change the representation of other kinds.
+var cacheRelatedKinds = map[string][]string{
+	"groups":     {"servers"},                       // deleting a group deletes its servers
+	"servers":    {"groups", "networks"},            // groups list their servers, networks their IP addresses
+	"operations": {"servers", "groups", "networks"}, // power operations, and completed queue jobs
+}
+
+// ResponseCache caches GET responses of a Client (see WithCache), keyed by URL, with a TTL per resource kind.
+// Concurrent identical GETs are coalesced into a single request. Any other request invalidates the cached
+// responses of the same resource kind (and of related kinds) within the same account.
+// A cache must not be shared by clients of different users.
+type ResponseCache struct {
+	ttls CacheTTLs
+
+	// File that the cache is persisted in by Save(), "" if none
+	path string
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry // by URL
+	calls   map[string]*cacheCall // in-flight requests, by URL
+	gen     uint64                // incremented by each invalidation
+}
+
+// cacheEntry is a cached response body.
+type cacheEntry struct {
+	Body    json.RawMessage `json:"body"`
+	Expires time.Time       `json:"expires"`
+}
+
+// cacheCall is an in-flight GET request, shared by all callers requesting the same URL.
+type cacheCall struct {
+	done chan struct{}
+	body json.RawMessage
+	err  error
+
+	// Whether @err is due to the context of the fetching caller being done
+	abandoned bool
+}
+
+// NewResponseCache returns an in-memory cache that uses @ttls.
+func NewResponseCache(ttls CacheTTLs) *ResponseCache {
+	return &ResponseCache{
+		ttls:    ttls,
+		entries: make(map[string]cacheEntry),
+		calls:   make(map[string]*cacheCall),
+	}
+}
+
+// OpenResponseCache returns the cache of @user at the API endpoint @apiURL, persisted in CLC_HOME.
+// Entries that have not expired yet are loaded from a previous Save().
+func OpenResponseCache(user, apiURL string, ttls CacheTTLs) (*ResponseCache, error) {
+	return LoadResponseCache(path.Join(GetClcHome(), cacheDir, tokenKey(user, apiURL)+".json"), ttls)
+}
+
+// LoadResponseCache returns a cache that uses @ttls, and that is persisted in the file @path.
+func LoadResponseCache(path string, ttls CacheTTLs) (*ResponseCache, error) {
+	var rc = NewResponseCache(ttls)
+	var saved map[string]cacheEntry
+
+	rc.path = path
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return rc, nil
+	} else if err != nil {
+		return nil, errors.Errorf("failed to load response cache: %s", err)
+	} else if err = json.Unmarshal(content, &saved); err != nil {
+		// The cache is expendable: start over if it is corrupted.
+		return rc, nil
+	}
+	for u, e := range saved {
+		if time.Now().Before(e.Expires) {
+			rc.entries[u] = e
+		}
+	}
+	return rc, nil
+}
+
+// Save persists the unexpired entries of @rc, if it was loaded from a file.
+func (rc *ResponseCache) Save() error {
+	var current = make(map[string]cacheEntry)
+
+	if rc.path == "" {
+		return nil
+	}
+
+	rc.mu.Lock()
+	for u, e := range rc.entries {
+		if time.Now().Before(e.Expires) {
+			current[u] = e
+		}
+	}
+	rc.mu.Unlock()
+
+	enc, err := json.Marshal(current)
+	if err != nil {
+		return errors.Errorf("failed to serialize response cache: %s", err)
+	}
+	return writeFileAtomic(rc.path, enc, 0600)
+}
+
+// Clear removes all entries from @rc.
+func (rc *ResponseCache) Clear() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.entries = make(map[string]cacheEntry)
+	rc.gen++
+}
+
+// Invalidate removes the entries that a mutation of the resource at @rawURL may have made stale.
+func (rc *ResponseCache) Invalidate(rawURL string) {
+	var kind, account = cacheResource(rawURL)
+	var stale = map[string]bool{kind: true}
+
+	for _, k := range cacheRelatedKinds[kind] {
+		stale[k] = true
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for u := range rc.entries {
+		if k, a := cacheResource(u); stale[k] && strings.EqualFold(a, account) {
+			delete(rc.entries, u)
+		}
+	}
+	rc.gen++
+}
+
+// get fills in @resModel from the cached response of @rawURL. If there is none, it obtains the response via @fetch,
+// or waits for a concurrent caller doing the same. Should that caller give up (its context is done), the response
+// is fetched again for the callers that are still waiting.
+func (rc *ResponseCache) get(ctx context.Context, rawURL string, resModel interface{}, fetch func(*json.RawMessage) error) error {
+	var kind, _ = cacheResource(rawURL)
+	var ttl = rc.ttls[kind]
+
+	if ttl <= 0 {
+		return fetch(nil)
+	}
+
+	for {
+		rc.mu.Lock()
+		if e, ok := rc.entries[rawURL]; ok && time.Now().Before(e.Expires) {
+			rc.mu.Unlock()
+			return json.Unmarshal(e.Body, resModel)
+		}
+		call, ok := rc.calls[rawURL]
+		if !ok {
+			break
+		}
+		rc.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if call.err == nil {
+			return json.Unmarshal(call.body, resModel)
+		} else if !call.abandoned || ctx.Err() != nil {
+			return call.err
+		}
+	}
+
+	var call = &cacheCall{done: make(chan struct{})}
+	var gen = rc.gen
+
+	rc.calls[rawURL] = call
+	rc.mu.Unlock()
+
+	call.err = fetch(&call.body)
+	call.abandoned = call.err != nil && ctx.Err() != nil
+
+	rc.mu.Lock()
+	delete(rc.calls, rawURL)
+	// Do not cache responses that may predate an invalidation during the request.
+	if call.err == nil && gen == rc.gen {
+		rc.entries[rawURL] = cacheEntry{Body: call.body, Expires: time.Now().Add(ttl)}
+	}
+	rc.mu.Unlock()
+	close(call.done)
+
+	if call.err != nil {
+		return call.err
+	}
+	return json.Unmarshal(call.body, resModel)
+}
+
+// cacheResource returns the resource kind and account alias of the API URL @rawURL.
+func cacheResource(rawURL string) (kind, account string) {
+	var p = rawURL
+
+	if u, err := url.Parse(rawURL); err == nil {
+		p = u.Path
+	}
+	switch seg := strings.Split(strings.Trim(p, "/"), "/"); len(seg) {
+	case 0, 1:
+		return "", ""
+	case 2:
+		return seg[1], ""
+	default:
+		return seg[1], seg[2]
+	}
+}
+
+// invalidateCache invalidates the cached responses that a mutation of @rawURL may affect.
+func (c *Client) invalidateCache(rawURL string) {
+	if c.cache != nil {
+		c.cache.Invalidate(rawURL)
+	}
+}
@@ -0,0 +1,53 @@
+package clcv2
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// A caller waiting for the response fetched by a concurrent caller does not fail because the latter gave up.
+func TestCacheAbandonedFetch(t *testing.T) {
+	const url = "https://api.example.com/v2/servers/ABCD/WA1ABCDWEB01"
+	var rc = NewResponseCache(CacheTTLs{"servers": time.Minute})
+	var started = make(chan struct{})
+	var leaderErr = make(chan error, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		leaderErr <- rc.get(ctx, url, new(Server), func(*json.RawMessage) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+	<-started
+
+	// Give up only once the second caller is waiting for the first one.
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	var srv Server
+	var fetches int
+	err := rc.get(context.Background(), url, &srv, func(raw *json.RawMessage) error {
+		fetches++
+		*raw = json.RawMessage(`{"name":"WA1ABCDWEB01"}`)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected the waiting caller to fetch the response itself, got %s", err)
+	} else if srv.Name != "WA1ABCDWEB01" || fetches != 1 {
+		t.Errorf("unexpected response %q after %d fetches", srv.Name, fetches)
+	}
+	if err := <-leaderErr; err != context.Canceled {
+		t.Errorf("expected the first caller to be canceled, got %v", err)
+	}
+
+	// The response fetched on behalf of the waiting caller is cached.
+	if err := rc.get(context.Background(), url, &srv, func(*json.RawMessage) error {
+		t.Errorf("response was not cached")
+		return nil
+	}); err != nil {
+		t.Errorf("cached get failed: %s", err)
+	}
+}
@@ -13,16 +13,22 @@ import (
 
 // Global (commandline flag) variables
 var (
+	g_profile      string              /* Configuration profile to use */
 	g_user, g_pass string              /* Command-line username/password */
 	g_acct         string              /* Account Alias to use instead of the default */
+	g_helper       string              /* Credential helper command */
+	g_nonInteract  bool                /* Do not prompt for missing credentials */
 	g_debug        bool                /* Command-line debug flag */
 	g_timeout      = 180 * time.Second /* Client default timeout */
 )
 
 func init() {
+	flag.StringVar(&g_profile, "profile", os.Getenv("CLC_PROFILE"), "CLC configuration profile to use")
 	flag.StringVar(&g_user, "username", os.Getenv("CLC_USER"), "CLC Login Username")
 	flag.StringVar(&g_pass, "password", os.Getenv("CLC_PASSWORD"), "CLC Login Password")
 	flag.StringVar(&g_acct, "a", os.Getenv("CLC_ACCOUNT"), "CLC Account Alias to use (instead of default)")
+	flag.StringVar(&g_helper, "credential-helper", os.Getenv("CLC_CREDENTIAL_HELPER"), "Command that supplies missing credentials")
+	flag.BoolVar(&g_nonInteract, "non-interactive", os.Getenv("CLC_NONINTERACTIVE") != "", "Fail instead of prompting for missing credentials")
 	flag.BoolVar(&g_debug, "d", false, "Produce debug output")
 	/*
 	 * Caveat: keep the timeout value high, at least a few minutes.
@@ -38,9 +44,13 @@ func NewCLIClient() (*clcv2.CLIClient, error) {
 	clcv2.ClientTimeout = g_timeout
 
 	return clcv2.NewCLIClient(&clcv2.ClientConfig{
+		Profile:  g_profile,
 		Username: g_user,
 		Password: g_pass,
 		Account:  g_acct,
 		Location: os.Getenv("CLC_LOCATION"),
+
+		CredentialHelper: g_helper,
+		NonInteractive:   g_nonInteract,
 	})
 }
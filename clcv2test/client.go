@@ -0,0 +1,38 @@
+package clcv2test
+
+import (
+	"io"
+
+	"github.com/grrtrr/clcv2"
+)
+
+// Credentials used by replay clients. Since passwords are redacted in the recording,
+// any value will match the recorded login request.
+const (
+	ReplayUser     = "replay"
+	ReplayPassword = "replay"
+)
+
+// NewReplayClient returns a client that is served from the interactions recorded in @fixture.
+// The recording must start with the login request. Further options may be passed via @opts.
+func NewReplayClient(fixture string, opts ...clcv2.Option) (*clcv2.Client, *Replayer, error) {
+	rp, err := LoadReplayer(fixture)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := clcv2.NewClientWithOptions(append([]clcv2.Option{
+		clcv2.WithCredentials(ReplayUser, ReplayPassword),
+		clcv2.WithTransport(rp),
+	}, opts...)...)
+	return client, rp, err
+}
+
+// NewRecordingClient returns a client logging in as @user/@pass against the live API, which
+// records all interactions (including the login) to @w. Further options may be passed via @opts.
+func NewRecordingClient(w io.Writer, user, pass string, opts ...clcv2.Option) (*clcv2.Client, error) {
+	return clcv2.NewClientWithOptions(append([]clcv2.Option{
+		clcv2.WithCredentials(user, pass),
+		clcv2.WithTransport(NewRecorder(w, nil)),
+	}, opts...)...)
+}
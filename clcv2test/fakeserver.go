@@ -0,0 +1,556 @@
+package clcv2test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/pkg/errors"
+)
+
+// FakeServer is an in-memory stand-in for the CLC v2 API (api.ctl.io), based on httptest.
+// It implements login, data centres and group trees, server CRUD and power operations,
+// the operations queue, networks and public IPs. Queued operations take effect when their
+// queue status reaches %clcv2.Succeeded: each status query advances a job one step from
+// %clcv2.NotStarted via %clcv2.Executing to %clcv2.Succeeded.
+//
+// Point a client at it via Client(), or via clcv2.WithEndpoints(f.Endpoints()).
+type FakeServer struct {
+	*httptest.Server
+
+	// Account alias and default location returned by the login request
+	Account, Location string
+
+	// Credentials accepted by the login request. Any password is accepted if @Password is empty.
+	Username, Password string
+
+	mu       sync.Mutex
+	seq      int                      // sequence number to generate unique IDs
+	tokens   map[string]bool          // valid bearer tokens
+	dcs      map[string]*fakeDC       // by upper-case location alias
+	groups   map[string]*fakeGroup    // by group ID
+	servers  map[string]*fakeServer   // by upper-case server name
+	networks map[string]*fakeNetwork  // by network ID
+	jobs     map[string]*fakeJob      // by queue ID
+	jobIDs   []string                 // queue IDs of @jobs in creation order
+	jobFails int                      // number of subsequently queued operations to fail
+	drops    map[string]int           // "VERB /path" -> number of responses to drop
+	failures map[string][]fakeFailure // "VERB /path" -> responses to return instead of processing the request
+	requests map[string]int           // "VERB /path" -> number of requests received
+}
+
+// fakeFailure is an error response injected via FailRequests.
+type fakeFailure struct {
+	status int
+	header http.Header
+}
+
+// fakeDC is a data centre with its root hardware group.
+type fakeDC struct {
+	clcv2.DataCenter
+	root string // ID of the root group
+}
+
+// fakeGroup is a hardware group; the Groups field of the embedded clcv2.Group is built on demand.
+type fakeGroup struct {
+	clcv2.Group
+	parent   string   // ID of the parent group, empty for root groups
+	children []string // IDs of the child groups
+	servers  []string // names of the servers in this group
+}
+
+// fakeServer is a server, along with the secrets and public IPs that the API does not reveal via GET.
+type fakeServer struct {
+	clcv2.Server
+	password  string
+	network   string                           // ID of the primary network
+	snapshot  string                           // ID of the current snapshot, if any
+	publicIPs map[string]clcv2.PublicIPAddress // public IP -> settings
+}
+
+// fakeNetwork is a network, along with its IP address allocations.
+type fakeNetwork struct {
+	clcv2.Network
+	location string
+	claimed  map[string]string // IP -> server name
+}
+
+// fakeJob is a queued operation whose @done function runs once it succeeds.
+type fakeJob struct {
+	status clcv2.QueueStatus
+	done   func()
+	fail   bool // whether the operation ends in %clcv2.Failed, without running @done
+
+	// Only set for claim-network operations
+	network string
+}
+
+// NewFakeServer starts a fake API server for @account, whose default data centre @location
+// contains a root group with 'Archive' and 'Templates' groups, a template server, and a network.
+// The server must be closed via Close() after use.
+func NewFakeServer(account, location string) *FakeServer {
+	var f = &FakeServer{
+		Account:  strings.ToUpper(account),
+		Location: strings.ToUpper(location),
+		Username: "fake",
+		Password: "fake",
+		tokens:   make(map[string]bool),
+		dcs:      make(map[string]*fakeDC),
+		groups:   make(map[string]*fakeGroup),
+		servers:  make(map[string]*fakeServer),
+		networks: make(map[string]*fakeNetwork),
+		jobs:     make(map[string]*fakeJob),
+		drops:    make(map[string]int),
+		failures: make(map[string][]fakeFailure),
+		requests: make(map[string]int),
+	}
+
+	f.AddDatacenter(f.Location, fmt.Sprintf("%s - Fake Data Centre", f.Location))
+	f.AddNetwork(f.Location, "10.81.149.0/24")
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return f
+}
+
+// Endpoints returns the endpoint configuration to point a client at @f.
+func (f *FakeServer) Endpoints() clcv2.Endpoints {
+	return clcv2.Endpoints{API: f.URL}
+}
+
+// Client returns a client that is logged in to @f. Further options may be passed via @opts.
+func (f *FakeServer) Client(opts ...clcv2.Option) (*clcv2.Client, error) {
+	return clcv2.NewClientWithOptions(append([]clcv2.Option{
+		clcv2.WithCredentials(f.Username, f.Password),
+		clcv2.WithEndpoints(f.Endpoints()),
+	}, opts...)...)
+}
+
+// ExpireTokens invalidates all bearer tokens handed out so far, forcing clients to log in again.
+func (f *FakeServer) ExpireTokens() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens = make(map[string]bool)
+}
+
+// DropResponses makes @f process the next @n @verb requests on @path (e.g. "/v2/servers/ABCD"), but close
+// the connection instead of responding - as if the request timed out after the server committed it.
+func (f *FakeServer) DropResponses(verb, path string, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.drops[strings.ToUpper(verb+" "+path)] = n
+}
+
+// FailRequests makes @f respond to the next @n @verb requests on @path with @status and the headers @hdr
+// (which may be nil), without processing them - e.g. 429 with a Retry-After header.
+func (f *FakeServer) FailRequests(verb, path string, n, status int, hdr http.Header) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var key = strings.ToUpper(verb + " " + path)
+	for i := 0; i < n; i++ {
+		f.failures[key] = append(f.failures[key], fakeFailure{status: status, header: hdr})
+	}
+}
+
+// Requests returns the number of @verb requests on @path that @f has received, including retries.
+func (f *FakeServer) Requests(verb, path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[strings.ToUpper(verb+" "+path)]
+}
+
+// FailJobs makes the next @n queued operations end in %clcv2.Failed, without taking effect.
+func (f *FakeServer) FailJobs(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.jobFails = n
+}
+
+// CompleteJobs runs all pending queue operations to completion.
+func (f *FakeServer) CompleteJobs() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, id := range f.jobIDs {
+		for j := f.jobs[id]; j.status != clcv2.Succeeded && j.status != clcv2.Failed; {
+			f.advance(j)
+		}
+	}
+}
+
+// AddDatacenter adds data centre @location, with the given descriptive @name, to @f.
+// Returns the ID of its root group, which contains an 'Archive' and a 'Templates' group.
+func (f *FakeServer) AddDatacenter(location, name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	location = strings.ToUpper(location)
+	if dc, ok := f.dcs[location]; ok {
+		return dc.root
+	}
+
+	root := f.newGroup("", location, location+" Hardware", "default")
+	f.newGroup(root.Id, location, "Archive", "archive")
+	templates := f.newGroup(root.Id, location, "Templates", "templates")
+
+	f.dcs[location] = &fakeDC{
+		DataCenter: clcv2.DataCenter{
+			Id:   strings.ToLower(location),
+			Name: name,
+			Links: []clcv2.Link{{
+				Rel:  "self",
+				Href: fmt.Sprintf("/v2/datacenters/%s/%s", f.Account, location),
+			}},
+		},
+		root: root.Id,
+	}
+
+	tmpl := f.newServer(templates.Id, "UBUNTU-16-64-TEMPLATE", nil)
+	tmpl.IsTemplate = true
+	tmpl.Description = "Ubuntu 16 | 64-bit"
+	tmpl.Status = "active"
+	tmpl.Details.PowerState = "stopped"
+	return root.Id
+}
+
+// AddGroup adds a group called @name underneath @parentID, and returns its ID.
+func (f *FakeServer) AddGroup(parentID, name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parent, ok := f.groups[parentID]
+	if !ok {
+		return "", errors.Errorf("parent group %q does not exist", parentID)
+	}
+	return f.newGroup(parentID, parent.LocationId, name, "default").Id, nil
+}
+
+// AddServer adds an active, started server to group @groupID and returns its name.
+// The @name follows the CreateServerReq convention: it is expanded to <location><account><name><nn>.
+func (f *FakeServer) AddServer(groupID, name string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.groups[groupID]; !ok {
+		return "", errors.Errorf("group %q does not exist", groupID)
+	}
+	location := f.groups[groupID].LocationId
+	s := f.newServer(groupID, f.serverName(location, name), f.locationNetwork(location))
+	s.Status = "active"
+	s.Details.PowerState = "started"
+	return s.Name, nil
+}
+
+// AddNetwork adds a network with the given @cidr to data centre @location, and returns its ID.
+func (f *FakeServer) AddNetwork(location, cidr string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.newNetwork(strings.ToUpper(location), cidr).Id
+}
+
+// ServerState returns a copy of the current state of server @name.
+func (f *FakeServer) ServerState(name string) (clcv2.Server, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if s, ok := f.servers[strings.ToUpper(name)]; ok {
+		return s.Server, true
+	}
+	return clcv2.Server{}, false
+}
+
+// RootGroup returns the ID of the root group of data centre @location.
+func (f *FakeServer) RootGroup(location string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if dc, ok := f.dcs[strings.ToUpper(location)]; ok {
+		return dc.root
+	}
+	return ""
+}
+
+/*
+ * Internal state handling - all of the following assume that @f.mu is held.
+ */
+
+// nextID returns a new hexadecimal ID (32 characters, like CLC group and network IDs).
+func (f *FakeServer) nextID() string {
+	f.seq++
+	return fmt.Sprintf("%032x", f.seq)
+}
+
+// changeInfo returns a ChangeInfo for a resource created now.
+func (f *FakeServer) changeInfo() clcv2.ChangeInfo {
+	var now = time.Now().UTC().Truncate(time.Second)
+
+	return clcv2.ChangeInfo{CreatedBy: f.Username, CreatedDate: now, ModifiedBy: f.Username, ModifiedDate: now}
+}
+
+func (f *FakeServer) newGroup(parent, location, name, typ string) *fakeGroup {
+	var g = &fakeGroup{parent: parent}
+
+	g.Id = f.nextID()
+	g.Name = name
+	g.LocationId = strings.ToUpper(location)
+	g.Type = typ
+	g.Status = "active"
+	g.ChangeInfo = f.changeInfo()
+	g.Links = []clcv2.Link{{
+		Rel:   "self",
+		Href:  fmt.Sprintf("/v2/groups/%s/%s", f.Account, g.Id),
+		Verbs: []string{"GET", "PATCH", "DELETE"},
+	}}
+
+	f.groups[g.Id] = g
+	if p, ok := f.groups[parent]; ok {
+		p.children = append(p.children, g.Id)
+	}
+	return g
+}
+
+// serverName expands @name according to the CLC naming convention.
+func (f *FakeServer) serverName(location, name string) string {
+	var prefix = strings.ToUpper(location + f.Account + name)
+
+	for i := 1; ; i++ {
+		if candidate := fmt.Sprintf("%s%02d", prefix, i); f.servers[candidate] == nil {
+			return candidate
+		}
+	}
+}
+
+// newServer adds a server called @name to @groupID, with default resources and an IP address
+// on network @n (if non-nil). The server is 'underConstruction' and 'stopped'.
+func (f *FakeServer) newServer(groupID, name string, n *fakeNetwork) *fakeServer {
+	var g = f.groups[groupID]
+	var s = &fakeServer{password: "fake-Passw0rd", publicIPs: make(map[string]clcv2.PublicIPAddress)}
+
+	s.Id = strings.ToLower(name)
+	s.Name = strings.ToUpper(name)
+	s.GroupId = groupID
+	s.LocationId = g.LocationId
+	s.OsType = "Ubuntu 16 64-bit"
+	s.Status = "underConstruction"
+	s.Type = "standard"
+	s.StorageType = "standard"
+	s.ChangeInfo = f.changeInfo()
+	s.Details.Cpu = 1
+	s.Details.MemoryMb = 2048
+	s.Details.PowerState = "stopped"
+	s.Details.Hostname = strings.ToLower(name)
+	f.setDisks(s, []clcv2.ServerAdditionalDisk{
+		{Id: "0:0", SizeGB: 1},
+		{Id: "0:1", SizeGB: 2},
+		{Id: "0:2", SizeGB: 14},
+	})
+	s.Links = []clcv2.Link{
+		{Rel: "self", Href: "/v2/servers/" + f.Account + "/" + s.Name, Id: s.Name, Verbs: []string{"GET", "PATCH", "DELETE"}},
+		{Rel: "group", Href: "/v2/groups/" + f.Account + "/" + groupID, Id: groupID},
+	}
+
+	if n != nil {
+		if ip := f.allocateIP(n, s.Name); ip != "" {
+			s.network = n.Id
+			s.Details.IpAddresses = []clcv2.ServerIPAddress{{Internal: ip}}
+		}
+	}
+
+	f.servers[s.Name] = s
+	g.servers = append(g.servers, s.Name)
+	g.Serverscount = len(g.servers)
+	return s
+}
+
+// setDisks replaces the disks of @s by @disks, assigning IDs to new disks.
+func (f *FakeServer) setDisks(s *fakeServer, disks []clcv2.ServerAdditionalDisk) {
+	var d = &s.Details
+	var used = make(map[clcv2.DiskID]bool)
+
+	for _, disk := range disks {
+		used[disk.Id] = true
+	}
+
+	d.Disks = d.Disks[:0]
+	d.StorageGb = 0
+	for i, disk := range disks {
+		for id := i; disk.Id == ""; id++ {
+			if candidate := clcv2.DiskID(fmt.Sprintf("0:%d", id)); !used[candidate] {
+				disk.Id, used[candidate] = candidate, true
+			}
+		}
+		d.Disks = append(d.Disks, struct {
+			Id             clcv2.DiskID
+			SizeGB         uint32
+			PartitionPaths []string
+		}{disk.Id, disk.SizeGB, []string{}})
+		d.StorageGb += int(disk.SizeGB)
+	}
+	d.DiskCount = len(d.Disks)
+}
+
+// deleteServer removes @s and releases its IP addresses.
+func (f *FakeServer) deleteServer(s *fakeServer) {
+	if g, ok := f.groups[s.GroupId]; ok {
+		g.servers = removeString(g.servers, s.Name)
+		g.Serverscount = len(g.servers)
+	}
+	for _, n := range f.networks {
+		for ip, owner := range n.claimed {
+			if owner == s.Name {
+				delete(n.claimed, ip)
+			}
+		}
+	}
+	delete(f.servers, s.Name)
+}
+
+// deleteGroup recursively removes @g, including its servers.
+func (f *FakeServer) deleteGroup(g *fakeGroup) {
+	for _, child := range append([]string(nil), g.children...) {
+		f.deleteGroup(f.groups[child])
+	}
+	for _, name := range append([]string(nil), g.servers...) {
+		f.deleteServer(f.servers[name])
+	}
+	if p, ok := f.groups[g.parent]; ok {
+		p.children = removeString(p.children, g.Id)
+	}
+	delete(f.groups, g.Id)
+}
+
+// groupTree returns @g with its sub-groups and server links filled in.
+func (f *FakeServer) groupTree(g *fakeGroup) clcv2.Group {
+	var res = g.Group
+
+	res.Groups = []clcv2.Group{}
+	for _, child := range g.children {
+		res.Groups = append(res.Groups, f.groupTree(f.groups[child]))
+	}
+	res.Links = append([]clcv2.Link(nil), g.Links...)
+	for _, name := range g.servers {
+		res.Links = append(res.Links, clcv2.Link{
+			Rel:  "server",
+			Href: "/v2/servers/" + f.Account + "/" + name,
+			Id:   name,
+		})
+	}
+	return res
+}
+
+func (f *FakeServer) newNetwork(location, cidr string) *fakeNetwork {
+	var n = &fakeNetwork{location: location, claimed: make(map[string]string)}
+
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(fmt.Sprintf("invalid network CIDR %q: %s", cidr, err))
+	}
+	base := binary.BigEndian.Uint32(ipNet.IP.To4())
+
+	n.Id = f.nextID()
+	n.Cidr = ipNet.String()
+	n.Netmask = net.IP(ipNet.Mask).String()
+	n.Gateway = uint32ToIP(base + 1).String()
+	n.Type = "private"
+	n.Vlan = 1200 + len(f.networks)
+	n.Name = fmt.Sprintf("vlan_%d_%s", n.Vlan, ipNet.IP)
+	n.Description = n.Name
+	n.Links = []clcv2.Link{{
+		Rel:   "self",
+		Href:  fmt.Sprintf("/v2-experimental/networks/%s/%s/%s", f.Account, location, n.Id),
+		Verbs: []string{"GET", "PUT"},
+	}}
+	f.networks[n.Id] = n
+	return n
+}
+
+// locationNetwork returns the first network in @location, or nil if there is none.
+func (f *FakeServer) locationNetwork(location string) *fakeNetwork {
+	var res *fakeNetwork
+
+	for _, n := range f.networks {
+		if n.location == location && (res == nil || n.Vlan < res.Vlan) {
+			res = n
+		}
+	}
+	return res
+}
+
+// allocateIP claims the next free host address in @n for @server, returning "" if @n is full.
+func (f *FakeServer) allocateIP(n *fakeNetwork, server string) string {
+	for _, ip := range n.hosts() {
+		if _, taken := n.claimed[ip]; !taken {
+			n.claimed[ip] = server
+			return ip
+		}
+	}
+	return ""
+}
+
+// hosts returns the usable addresses of @n, skipping the gateway and reserved addresses (at most 256).
+func (n *fakeNetwork) hosts() (res []string) {
+	_, ipNet, _ := net.ParseCIDR(n.Cidr)
+	base := binary.BigEndian.Uint32(ipNet.IP.To4())
+	ones, bits := ipNet.Mask.Size()
+
+	for i := uint32(12); i < uint32(1)<<uint(bits-ones)-1 && len(res) < 256; i++ {
+		res = append(res, uint32ToIP(base+i).String())
+	}
+	return res
+}
+
+// newJob queues an operation that calls @done once it succeeds, and returns its queue ID.
+func (f *FakeServer) newJob(location string, done func()) string {
+	f.seq++
+	id := fmt.Sprintf("%s-%d", strings.ToLower(location), 100000+f.seq)
+	f.jobs[id] = &fakeJob{done: done, fail: f.jobFails > 0}
+	f.jobIDs = append(f.jobIDs, id)
+	if f.jobFails > 0 {
+		f.jobFails--
+	}
+	return id
+}
+
+// advance moves @j one step towards completion, running its completion function when done.
+// New jobs have an empty status, so that the first status query reports %clcv2.NotStarted.
+func (f *FakeServer) advance(j *fakeJob) {
+	switch j.status {
+	case "":
+		j.status = clcv2.NotStarted
+	case clcv2.NotStarted:
+		j.status = clcv2.Executing
+	case clcv2.Executing:
+		if j.fail {
+			j.status = clcv2.Failed
+			return
+		}
+		j.status = clcv2.Succeeded
+		if j.done != nil {
+			j.done()
+		}
+	}
+}
+
+func uint32ToIP(n uint32) net.IP {
+	var ip = make(net.IP, 4)
+
+	binary.BigEndian.PutUint32(ip, n)
+	return ip
+}
+
+func removeString(list []string, s string) []string {
+	var res = list[:0]
+
+	for _, e := range list {
+		if e != s {
+			res = append(res, e)
+		}
+	}
+	return res
+}
@@ -0,0 +1,862 @@
+package clcv2test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grrtrr/clcv2"
+)
+
+// Message returned by power operations on servers that are missing or in the wrong state.
+const notQueuedMessage = "The operation cannot be queued because the server cannot be found or it is not in a valid state."
+
+// Server names passed to CreateServer: alphanumeric characters and dashes only.
+var serverNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9-]{1,8}$`)
+
+// serveHTTP serves the API request @r, failing it or dropping the response if requested via FailRequests
+// or DropResponses.
+func (f *FakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var drop = strings.ToUpper(r.Method + " " + r.URL.Path)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests[drop]++
+	if failures := f.failures[drop]; len(failures) > 0 {
+		f.failures[drop] = failures[1:]
+		for k, v := range failures[0].header {
+			w.Header()[k] = v
+		}
+		writeError(w, failures[0].status, http.StatusText(failures[0].status))
+		return
+	} else if f.drops[drop] > 0 {
+		f.drops[drop]--
+		f.route(httptest.NewRecorder(), r)
+		if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+			conn.Close()
+		}
+		return
+	}
+	f.route(w, r)
+}
+
+// route dispatches the API request @r according to its path prefix. Must be called with @f.mu held.
+func (f *FakeServer) route(w http.ResponseWriter, r *http.Request) {
+	var seg = strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	if r.Method == "POST" && r.URL.Path == "/v2/authentication/login" {
+		f.login(w, r)
+		return
+	} else if !f.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")] {
+		writeError(w, http.StatusUnauthorized, "Authorization has been denied for this request.")
+		return
+	} else if len(seg) < 3 {
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+		return
+	} else if !strings.EqualFold(seg[2], f.Account) {
+		writeError(w, http.StatusForbidden, fmt.Sprintf("Access to account %q is not permitted.", seg[2]))
+		return
+	}
+
+	switch api, rest := seg[0]+"/"+seg[1], seg[3:]; api {
+	case "v2/datacenters":
+		f.datacenterAPI(w, r, rest)
+	case "v2/groups":
+		f.groupAPI(w, r, rest)
+	case "v2/servers":
+		f.serverAPI(w, r, rest)
+	case "v2/operations":
+		f.operationsAPI(w, r, rest)
+	case "v2-experimental/networks":
+		f.networkAPI(w, r, rest)
+	case "v2-experimental/operations":
+		f.claimStatus(w, r, rest)
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+func (f *FakeServer) login(w http.ResponseWriter, r *http.Request) {
+	var req clcv2.LoginReq
+
+	if !decodeBody(w, r, &req) {
+		return
+	} else if req.Username != f.Username || (f.Password != "" && req.Password != f.Password) {
+		writeError(w, http.StatusUnauthorized, "We didn't recognize the username or password you entered.")
+		return
+	}
+
+	f.seq++
+	token := fmt.Sprintf("fake-token-%d", f.seq)
+	f.tokens[token] = true
+
+	writeJSON(w, http.StatusOK, clcv2.LoginRes{
+		User:          req.Username,
+		AccountAlias:  f.Account,
+		LocationAlias: f.Location,
+		Roles:         []string{"AccountAdmin", "ServerAdmin"},
+		BearerToken:   token,
+	})
+}
+
+/*
+ * Data centres
+ */
+func (f *FakeServer) datacenterAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		var res []clcv2.DataCenter
+
+		if !allowMethod(w, r, "GET") {
+			return
+		}
+		for _, dc := range f.dcs {
+			res = append(res, dc.DataCenter)
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Id < res[j].Id })
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+
+	dc, ok := f.dcs[strings.ToUpper(rest[0])]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Data center %q not found.", rest[0]))
+		return
+	} else if !allowMethod(w, r, "GET") {
+		return
+	}
+
+	switch {
+	case len(rest) == 1:
+		res := dc.DataCenter
+		if r.URL.Query().Get("groupLinks") == "true" {
+			res.Links = append(append([]clcv2.Link(nil), res.Links...), clcv2.Link{
+				Rel:  "group",
+				Href: fmt.Sprintf("/v2/groups/%s/%s", f.Account, dc.root),
+				Id:   dc.root,
+				Name: f.groups[dc.root].Name,
+			})
+		}
+		writeJSON(w, http.StatusOK, res)
+	case len(rest) == 2 && rest[1] == "deploymentCapabilities":
+		writeJSON(w, http.StatusOK, f.deploymentCapabilities(strings.ToUpper(rest[0])))
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+// deploymentCapabilities lists the networks and templates of @location.
+func (f *FakeServer) deploymentCapabilities(location string) map[string]interface{} {
+	var networks, templates = []map[string]string{}, []clcv2.Template{}
+
+	for _, n := range f.sortedNetworks(location) {
+		networks = append(networks, map[string]string{
+			"name":      n.Name,
+			"networkId": n.Id,
+			"type":      n.Type,
+			"accountID": f.Account,
+		})
+	}
+	for _, s := range f.servers {
+		if s.IsTemplate && s.LocationId == location {
+			templates = append(templates, clcv2.Template{
+				Name:          s.Name,
+				Description:   s.Description,
+				OsType:        s.OsType,
+				StorageSizeGB: s.Details.StorageGb,
+				Capabilities:  []string{"cpuAutoscale"},
+			})
+		}
+	}
+	return map[string]interface{}{
+		"dataCenterEnabled":          true,
+		"supportsPremiumStorage":     true,
+		"supportsSharedLoadBalancer": true,
+		"supportsBareMetalServers":   false,
+		"deployableNetworks":         networks,
+		"templates":                  templates,
+	}
+}
+
+/*
+ * Hardware groups
+ */
+func (f *FakeServer) groupAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		if allowMethod(w, r, "POST") {
+			f.createGroup(w, r)
+		}
+		return
+	}
+
+	g, ok := f.groups[strings.ToLower(rest[0])]
+	if !ok || len(rest) > 1 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Group %q not found.", rest[0]))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, f.groupTree(g))
+	case "DELETE":
+		if g.parent == "" {
+			writeError(w, http.StatusBadRequest, "The root group of a data center cannot be deleted.")
+			return
+		}
+		id := f.newJob(g.LocationId, func() {
+			if g, ok := f.groups[g.Id]; ok {
+				f.deleteGroup(g)
+			}
+		})
+		writeJSON(w, http.StatusOK, f.statusLink(id))
+	case "PATCH":
+		var ops []clcv2.PatchOperation
+
+		if !decodeBody(w, r, &ops) {
+			return
+		}
+		for _, op := range ops {
+			val, _ := op.Value.(string)
+			switch op.Member {
+			case "name":
+				g.Name = val
+			case "description":
+				g.Description = val
+			case "parentGroupId":
+				p, ok := f.groups[val]
+				if !ok || g.parent == "" {
+					writeValidationError(w, "body.value", fmt.Sprintf("Invalid parent group %q.", val))
+					return
+				}
+				f.groups[g.parent].children = removeString(f.groups[g.parent].children, g.Id)
+				p.children = append(p.children, g.Id)
+				g.parent = p.Id
+			default:
+				writeValidationError(w, "body.member", fmt.Sprintf("Unsupported member %q.", op.Member))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not supported on groups.", r.Method))
+	}
+}
+
+func (f *FakeServer) createGroup(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name          string
+		Description   string
+		ParentGroupId string
+	}
+
+	if !decodeBody(w, r, &req) {
+		return
+	} else if req.Name == "" {
+		writeValidationError(w, "body.name", "The name field is required.")
+		return
+	}
+
+	parent, ok := f.groups[req.ParentGroupId]
+	if !ok {
+		writeValidationError(w, "body.parentGroupId", fmt.Sprintf("Parent group %q does not exist.", req.ParentGroupId))
+		return
+	}
+	g := f.newGroup(parent.Id, parent.LocationId, req.Name, "default")
+	g.Description = req.Description
+	writeJSON(w, http.StatusCreated, f.groupTree(g))
+}
+
+/*
+ * Servers
+ */
+func (f *FakeServer) serverAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		if allowMethod(w, r, "POST") {
+			f.createServer(w, r)
+		}
+		return
+	}
+
+	s, ok := f.servers[strings.ToUpper(rest[0])]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("The server '%s' was not found.", rest[0]))
+		return
+	}
+
+	switch {
+	case len(rest) == 1 && r.Method == "GET":
+		writeJSON(w, http.StatusOK, s.Server)
+	case len(rest) == 1 && r.Method == "DELETE":
+		s.Status = "queuedForDelete"
+		id := f.newJob(s.LocationId, func() {
+			if s, ok := f.servers[s.Name]; ok {
+				f.deleteServer(s)
+			}
+		})
+		writeJSON(w, http.StatusAccepted, clcv2.StatusResponse{Server: s.Name, IsQueued: true, Links: []clcv2.Link{f.statusLink(id)}})
+	case len(rest) == 1 && r.Method == "PATCH":
+		f.patchServer(w, r, s)
+	case len(rest) == 2 && rest[1] == "credentials" && r.Method == "GET":
+		writeJSON(w, http.StatusOK, map[string]string{"userName": "root", "password": s.password})
+	case len(rest) >= 2 && rest[1] == "publicIPAddresses":
+		f.publicIPAPI(w, r, s, rest[2:])
+	case len(rest) >= 3 && rest[1] == "snapshots":
+		f.snapshotAPI(w, r, s, rest[2:])
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+func (f *FakeServer) createServer(w http.ResponseWriter, r *http.Request) {
+	var req clcv2.CreateServerReq
+	var n *fakeNetwork
+
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	g, ok := f.groups[req.GroupId]
+	src := f.servers[strings.ToUpper(req.SourceServerId)]
+	switch {
+	case !serverNameRegexp.MatchString(req.Name):
+		writeValidationError(w, "body.name", "The name must be 1-8 alphanumeric characters or dashes.")
+		return
+	case !ok:
+		writeValidationError(w, "body.groupId", fmt.Sprintf("The group %q does not exist.", req.GroupId))
+		return
+	case src == nil:
+		writeValidationError(w, "body.sourceServerId", fmt.Sprintf("The source server %q does not exist.", req.SourceServerId))
+		return
+	case req.Cpu < 1 || req.Cpu > 16:
+		writeValidationError(w, "body.cpu", "The cpu value must be between 1 and 16.")
+		return
+	case req.MemoryGB < 1 || req.MemoryGB > 128:
+		writeValidationError(w, "body.memoryGB", "The memoryGB value must be between 1 and 128.")
+		return
+	}
+
+	if req.NetworkId == "" {
+		n = f.locationNetwork(g.LocationId)
+	} else if n = f.networks[req.NetworkId]; n == nil || n.location != g.LocationId {
+		writeValidationError(w, "body.networkId", fmt.Sprintf("The network %s is not valid.", req.NetworkId))
+		return
+	}
+
+	s := f.newServer(g.Id, f.serverName(g.LocationId, req.Name), n)
+	s.Description = req.Description
+	s.OsType = src.OsType
+	s.Details.Cpu = req.Cpu
+	s.Details.MemoryMb = req.MemoryGB * 1024
+	if req.Type != "" {
+		s.Type = req.Type
+	}
+	if req.StorageType != "" {
+		s.StorageType = req.StorageType
+	}
+	if req.Password != "" {
+		s.password = req.Password
+	}
+
+	disks := make([]clcv2.ServerAdditionalDisk, 0, len(src.Details.Disks)+len(req.AdditionalDisks))
+	for _, d := range src.Details.Disks {
+		disks = append(disks, clcv2.ServerAdditionalDisk{Id: d.Id, SizeGB: d.SizeGB})
+	}
+	for _, d := range req.AdditionalDisks {
+		disks = append(disks, clcv2.ServerAdditionalDisk{SizeGB: d.SizeGB, Path: d.Path, Type: d.Type})
+	}
+	f.setDisks(s, disks)
+
+	id := f.newJob(s.LocationId, func() {
+		s.Status = "active"
+		s.Details.PowerState = "started"
+	})
+	writeJSON(w, http.StatusAccepted, clcv2.StatusResponse{
+		Server:   s.Name,
+		IsQueued: true,
+		Links: []clcv2.Link{
+			f.statusLink(id),
+			{Rel: "self", Href: fmt.Sprintf("/v2/servers/%s/%s?uuid=false", f.Account, s.Name), Id: s.Name},
+		},
+	})
+}
+
+func (f *FakeServer) patchServer(w http.ResponseWriter, r *http.Request, s *fakeServer) {
+	var ops []clcv2.PatchOperation
+	var changes []func()
+
+	if !decodeBody(w, r, &ops) {
+		return
+	}
+
+	for _, op := range ops {
+		switch op.Member {
+		case "cpu", "memory":
+			val, err := strconv.Atoi(fmt.Sprint(op.Value))
+			if err != nil || val < 1 || val > 128 || (op.Member == "cpu" && val > 16) {
+				writeValidationError(w, "body.value", fmt.Sprintf("Invalid %s value %v.", op.Member, op.Value))
+				return
+			} else if op.Member == "cpu" {
+				changes = append(changes, func() { s.Details.Cpu = val })
+			} else {
+				changes = append(changes, func() { s.Details.MemoryMb = val * 1024 })
+			}
+		case "disks":
+			var disks []clcv2.ServerAdditionalDisk
+
+			if enc, err := json.Marshal(op.Value); err != nil || json.Unmarshal(enc, &disks) != nil {
+				writeValidationError(w, "body.value", "Invalid disks value.")
+				return
+			}
+			changes = append(changes, func() { f.setDisks(s, disks) })
+		case "password":
+			var pw struct{ Current, Password string }
+
+			if enc, err := json.Marshal(op.Value); err != nil || json.Unmarshal(enc, &pw) != nil {
+				writeValidationError(w, "body.value", "Invalid password value.")
+				return
+			} else if pw.Current != s.password {
+				writeValidationError(w, "body.value.current", "The current password is incorrect.")
+				return
+			}
+			changes = append(changes, func() { s.password = pw.Password })
+		case "description":
+			s.Description = fmt.Sprint(op.Value)
+		case "groupId":
+			g, ok := f.groups[fmt.Sprint(op.Value)]
+			if !ok {
+				writeValidationError(w, "body.value", fmt.Sprintf("The group %v does not exist.", op.Value))
+				return
+			}
+			old := f.groups[s.GroupId]
+			old.servers = removeString(old.servers, s.Name)
+			old.Serverscount = len(old.servers)
+			g.servers = append(g.servers, s.Name)
+			g.Serverscount = len(g.servers)
+			s.GroupId = g.Id
+		default:
+			writeValidationError(w, "body.member", fmt.Sprintf("Unsupported member %q.", op.Member))
+			return
+		}
+	}
+
+	if len(changes) == 0 { // description and groupId take effect immediately
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	id := f.newJob(s.LocationId, func() {
+		for _, change := range changes {
+			change()
+		}
+	})
+	writeJSON(w, http.StatusOK, f.statusLink(id))
+}
+
+func (f *FakeServer) publicIPAPI(w http.ResponseWriter, r *http.Request, s *fakeServer, rest []string) {
+	if len(rest) == 0 {
+		var req clcv2.PublicIPAddress
+
+		if !allowMethod(w, r, "POST") || !decodeBody(w, r, &req) {
+			return
+		}
+
+		internal := req.InternalIPAddress
+		if internal == "" {
+			if n := f.networks[s.network]; n != nil {
+				internal = f.allocateIP(n, s.Name)
+			}
+			if internal == "" {
+				writeError(w, http.StatusBadRequest, "No internal IP address available.")
+				return
+			}
+		} else if !s.hasInternalIP(internal) {
+			writeValidationError(w, "body.internalIPAddress", fmt.Sprintf("%s is not an IP address of %s.", internal, s.Name))
+			return
+		}
+
+		req.InternalIPAddress = internal
+		public := f.nextPublicIP()
+		id := f.newJob(s.LocationId, func() {
+			s.publicIPs[public] = req
+			for i := range s.Details.IpAddresses {
+				if s.Details.IpAddresses[i].Internal == internal && s.Details.IpAddresses[i].Public == "" {
+					s.Details.IpAddresses[i].Public = public
+					return
+				}
+			}
+			s.Details.IpAddresses = append(s.Details.IpAddresses, clcv2.ServerIPAddress{Internal: internal, Public: public})
+		})
+		writeJSON(w, http.StatusOK, f.statusLink(id))
+		return
+	}
+
+	public := rest[0]
+	pip, ok := s.publicIPs[public]
+	if !ok || len(rest) > 1 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Public IP address %s not found on %s.", public, s.Name))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, pip)
+	case "PUT":
+		var req clcv2.PublicIPAddress
+
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		req.InternalIPAddress = pip.InternalIPAddress
+		id := f.newJob(s.LocationId, func() { s.publicIPs[public] = req })
+		writeJSON(w, http.StatusOK, f.statusLink(id))
+	case "DELETE":
+		id := f.newJob(s.LocationId, func() {
+			delete(s.publicIPs, public)
+			for i := range s.Details.IpAddresses {
+				if s.Details.IpAddresses[i].Public == public {
+					s.Details.IpAddresses[i].Public = ""
+				}
+			}
+		})
+		writeJSON(w, http.StatusOK, f.statusLink(id))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not supported on public IPs.", r.Method))
+	}
+}
+
+// nextPublicIP returns an unused public IP address (from the TEST-NET-3 range).
+func (f *FakeServer) nextPublicIP() string {
+	var used = make(map[string]bool)
+
+	for _, s := range f.servers {
+		for ip := range s.publicIPs {
+			used[ip] = true
+		}
+	}
+	for i := 10; ; i++ {
+		if ip := fmt.Sprintf("203.0.113.%d", i); !used[ip] {
+			return ip
+		}
+	}
+}
+
+// hasInternalIP returns true if @ip is one of the internal IP addresses of @s.
+func (s *fakeServer) hasInternalIP(ip string) bool {
+	for _, addr := range s.Details.IpAddresses {
+		if addr.Internal == ip {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FakeServer) snapshotAPI(w http.ResponseWriter, r *http.Request, s *fakeServer, rest []string) {
+	if len(s.Details.Snapshots) == 0 || s.snapshot != rest[0] {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Snapshot %s not found on %s.", rest[0], s.Name))
+		return
+	}
+
+	switch {
+	case len(rest) == 1 && r.Method == "DELETE":
+		id := f.newJob(s.LocationId, func() { s.Details.Snapshots, s.snapshot = nil, "" })
+		writeJSON(w, http.StatusOK, f.statusLink(id))
+	case len(rest) == 2 && rest[1] == "restore" && r.Method == "POST":
+		writeJSON(w, http.StatusOK, f.statusLink(f.newJob(s.LocationId, nil)))
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+// addSnapshot replaces any snapshot of @s by a new one.
+func (f *FakeServer) addSnapshot(s *fakeServer) {
+	f.seq++
+	s.snapshot = strconv.Itoa(f.seq)
+
+	var href = fmt.Sprintf("/v2/servers/%s/%s/snapshots/%s", f.Account, s.Name, s.snapshot)
+
+	s.Details.Snapshots = []clcv2.ServerSnapshot{{
+		Name: time.Now().UTC().Format("2006-01-02.15:04:05"),
+		Links: []clcv2.Link{
+			{Rel: "self", Href: href},
+			{Rel: "delete", Href: href},
+			{Rel: "restore", Href: href + "/restore"},
+		},
+	}}
+}
+
+/*
+ * Queue operations
+ */
+func (f *FakeServer) operationsAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	switch {
+	case len(rest) == 2 && rest[0] == "status" && r.Method == "GET":
+		j, ok := f.jobs[rest[1]]
+		if !ok || j.network != "" {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Status %q not found.", rest[1]))
+			return
+		}
+		f.advance(j)
+		writeJSON(w, http.StatusOK, map[string]clcv2.QueueStatus{"status": j.status})
+	case len(rest) == 2 && rest[0] == "servers" && r.Method == "POST":
+		f.serverOperation(w, r, rest[1])
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+// serverOperation performs the bulk server operation @op.
+func (f *FakeServer) serverOperation(w http.ResponseWriter, r *http.Request, op string) {
+	var targets = make(map[string]func(*fakeServer))
+	var names []string
+
+	switch op {
+	case "createSnapshot":
+		var req struct{ ServerIds []string }
+
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		for _, name := range req.ServerIds {
+			names = append(names, name)
+			targets[name] = f.addSnapshot
+		}
+	case "setMaintenance":
+		var req struct{ Servers []clcv2.MaintenanceMode }
+
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		for _, m := range req.Servers {
+			enable := m.InMaintenanceMode
+			names = append(names, m.Id)
+			targets[m.Id] = func(s *fakeServer) { s.Details.InMaintenanceMode = enable }
+		}
+	default:
+		var effect func(*fakeServer)
+
+		switch op {
+		case "powerOn", "reboot", "reset":
+			effect = func(s *fakeServer) { s.Details.PowerState = "started" }
+		case "powerOff", "shutDown":
+			effect = func(s *fakeServer) { s.Details.PowerState = "stopped" }
+		case "pause":
+			effect = func(s *fakeServer) { s.Details.PowerState = "paused" }
+		case "startMaintenance", "stopMaintenance":
+			enable := op == "startMaintenance"
+			effect = func(s *fakeServer) { s.Details.InMaintenanceMode = enable }
+		case "archive":
+			effect = func(s *fakeServer) { s.Status, s.Details.PowerState = "archived", "stopped" }
+		default:
+			writeError(w, http.StatusNotFound, fmt.Sprintf("Unsupported server operation %q.", op))
+			return
+		}
+		if !decodeBody(w, r, &names) {
+			return
+		}
+		for _, name := range names {
+			targets[name] = effect
+		}
+	}
+
+	var res = []clcv2.StatusResponse{}
+	for _, name := range names {
+		s, ok := f.servers[strings.ToUpper(name)]
+		if !ok || s.IsTemplate || s.Status != "active" {
+			res = append(res, clcv2.StatusResponse{Server: name, Links: []clcv2.Link{}, ErrorMessage: notQueuedMessage})
+			continue
+		}
+		effect := targets[name]
+		id := f.newJob(s.LocationId, func() { effect(s) })
+		res = append(res, clcv2.StatusResponse{Server: s.Name, IsQueued: true, Links: []clcv2.Link{f.statusLink(id)}})
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// statusLink returns the queue status link of job @id.
+func (f *FakeServer) statusLink(id string) clcv2.Link {
+	return clcv2.Link{Rel: "status", Href: fmt.Sprintf("/v2/operations/%s/status/%s", f.Account, id), Id: id}
+}
+
+/*
+ * Networks (experimental API)
+ */
+func (f *FakeServer) networkAPI(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+		return
+	}
+
+	location := strings.ToUpper(rest[0])
+	if _, ok := f.dcs[location]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Data center %q not found.", rest[0]))
+		return
+	}
+
+	switch {
+	case len(rest) == 1 && r.Method == "GET":
+		var res = []clcv2.Network{}
+		for _, n := range f.sortedNetworks(location) {
+			res = append(res, n.Network)
+		}
+		writeJSON(w, http.StatusOK, res)
+		return
+	case len(rest) == 2 && rest[1] == "claim" && r.Method == "POST":
+		f.claimNetwork(w, location)
+		return
+	}
+
+	n, ok := f.networks[rest[1]]
+	if !ok || n.location != location {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Network %q not found in %s.", rest[1], location))
+		return
+	}
+
+	switch {
+	case len(rest) == 2 && r.Method == "GET":
+		writeJSON(w, http.StatusOK, n.details(r.URL.Query().Get("ipAddresses")))
+	case len(rest) == 2 && r.Method == "PUT":
+		var req struct{ Name, Description string }
+
+		if !decodeBody(w, r, &req) {
+			return
+		} else if req.Name == "" {
+			writeValidationError(w, "body.name", "The name field is required.")
+			return
+		}
+		n.Name, n.Description = req.Name, req.Description
+		w.WriteHeader(http.StatusNoContent)
+	case len(rest) == 3 && rest[2] == "release" && r.Method == "POST":
+		if len(n.claimed) > 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Network %s still has %d claimed IP address(es).", n.Name, len(n.claimed)))
+			return
+		}
+		delete(f.networks, n.Id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+	}
+}
+
+// details returns the details of @n, including the IP addresses selected by @query.
+func (n *fakeNetwork) details(query string) clcv2.NetworkDetails {
+	var res = clcv2.NetworkDetails{Network: n.Network, IpAddresses: []clcv2.IpAddressDetails{}}
+
+	for _, ip := range n.hosts() {
+		server, claimed := n.claimed[ip]
+		if query == "all" || (query == "claimed" && claimed) || (query == "free" && !claimed) {
+			res.IpAddresses = append(res.IpAddresses, clcv2.IpAddressDetails{
+				Address: ip,
+				Claimed: claimed,
+				Type:    "private",
+				Server:  server,
+			})
+		}
+	}
+	return res
+}
+
+// claimNetwork queues the creation of a new /24 network in @location.
+func (f *FakeServer) claimNetwork(w http.ResponseWriter, location string) {
+	var j *fakeJob
+
+	id := f.newJob(location, func() {
+		cidr := fmt.Sprintf("10.%d.%d.0/24", 100+len(f.dcs), 10+len(f.networks))
+		j.network = f.newNetwork(location, cidr).Id
+	})
+	j = f.jobs[id]
+	j.network = "pending"
+
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"operationId": id,
+		"uri":         fmt.Sprintf("/v2-experimental/operations/%s/status/%s", f.Account, id),
+	})
+}
+
+// claimStatus reports the progress of a claim-network operation.
+func (f *FakeServer) claimStatus(w http.ResponseWriter, r *http.Request, rest []string) {
+	var links = []clcv2.StatusLink{}
+
+	if len(rest) != 2 || rest[0] != "status" || r.Method != "GET" {
+		writeError(w, http.StatusNotFound, "No HTTP resource was found that matches the request URI.")
+		return
+	}
+
+	j, ok := f.jobs[rest[1]]
+	if !ok || j.network == "" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Operation %q not found.", rest[1]))
+		return
+	}
+	f.advance(j)
+
+	if j.status == clcv2.Succeeded {
+		n := f.networks[j.network]
+		links = append(links, clcv2.StatusLink{Id: n.Id, Rel: "network", Href: n.Links[0].Href})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"requestType": "blueprintOperation",
+		"status":      j.status,
+		"summary": map[string]interface{}{
+			"blueprintId": f.seq,
+			"locationId":  strings.ToLower(strings.SplitN(rest[1], "-", 2)[0]),
+			"links":       links,
+		},
+	})
+}
+
+// sortedNetworks returns the networks of @location ordered by VLAN.
+func (f *FakeServer) sortedNetworks(location string) (res []*fakeNetwork) {
+	for _, n := range f.networks {
+		if n.location == location {
+			res = append(res, n)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Vlan < res[j].Vlan })
+	return res
+}
+
+/*
+ * Helpers
+ */
+
+// writeJSON sends @v as JSON response with the given @status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError sends an error response carrying @msg.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"message": msg})
+}
+
+// writeValidationError sends a 400 response with @msg for @field in the modelState.
+func writeValidationError(w http.ResponseWriter, field, msg string) {
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"message":    "The request is invalid.",
+		"modelState": map[string][]string{field: {msg}},
+	})
+}
+
+// decodeBody decodes the JSON request body of @r into @v, responding with an error if that fails.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("The request body is invalid: %s", err))
+		return false
+	}
+	return true
+}
+
+// allowMethod responds with an error and returns false if @r does not use @method.
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("The requested resource does not support http method '%s'.", r.Method))
+		return false
+	}
+	return true
+}
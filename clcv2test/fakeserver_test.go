@@ -0,0 +1,23 @@
+package clcv2test
+
+import (
+	"reflect"
+	"testing"
+)
+
+// CompleteJobs runs the pending jobs in the order in which they were queued, regardless of their IDs.
+func TestCompleteJobsOrder(t *testing.T) {
+	var f = NewFakeServer("ABCD", "WA1")
+	var order []string
+	defer f.Close()
+
+	for _, location := range []string{"WA1", "CA3", "WA1", "UC1"} {
+		location := location
+		f.newJob(location, func() { order = append(order, location) })
+	}
+	f.CompleteJobs()
+
+	if expected := []string{"WA1", "CA3", "WA1", "UC1"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected jobs to complete in order %v, got %v", expected, order)
+	}
+}
@@ -0,0 +1,276 @@
+// Package clcv2test provides a record/replay HTTP transport for clcv2.Client.
+//
+// A Recorder wraps a live transport and writes each request/response pair as a JSON line, with
+// bearer tokens and passwords redacted (see clcv2.RedactBody). A Replayer serves the recorded
+// interactions back, matching requests by verb, path (including query) and body, so that code
+// built on clcv2.Client can be exercised offline:
+//
+//	client, _, err := clcv2test.NewReplayClient("testdata/server.jsonl")
+//	srv, err := client.GetServer("WA1ABCDWEB01")
+package clcv2test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/pkg/errors"
+)
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	// HTTP verb of the request
+	Verb string `json:"verb"`
+
+	// Request path, including the query string
+	Path string `json:"path"`
+
+	// Request body (redacted), empty if none
+	RequestBody json.RawMessage `json:"requestBody,omitempty"`
+
+	// HTTP status code of the response
+	Status int `json:"status"`
+
+	// Content-Type of the response
+	ContentType string `json:"contentType,omitempty"`
+
+	// Response body (redacted), empty if none
+	ResponseBody json.RawMessage `json:"responseBody,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records all interactions performed through it.
+type Recorder struct {
+	rt http.RoundTripper
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewRecorder returns a Recorder that performs requests via @rt (http.DefaultTransport
+// if nil), writing each interaction as a JSON line to @w.
+func NewRecorder(w io.Writer, rt http.RoundTripper) *Recorder {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &Recorder{rt: rt, w: w}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var in = Interaction{Verb: req.Method, Path: req.URL.RequestURI()}
+
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, errors.Errorf("failed to read %s %s request body: %s", req.Method, req.URL, err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		in.RequestBody = redactBody(req.URL.Path, body)
+	}
+
+	res, err := r.rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, errors.Errorf("failed to read %s %s response body: %s", req.Method, req.URL, err)
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	in.Status = res.StatusCode
+	in.ContentType = res.Header.Get("Content-Type")
+	in.ResponseBody = redactBody(req.URL.Path, body)
+
+	enc, err := json.Marshal(in)
+	if err != nil {
+		return nil, errors.Errorf("failed to encode %s %s interaction: %s", req.Method, req.URL, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.w.Write(append(enc, '\n')); err != nil {
+		return nil, errors.Errorf("failed to record %s %s: %s", req.Method, req.URL, err)
+	}
+	return res, nil
+}
+
+// Replayer is an http.RoundTripper that serves previously recorded interactions.
+// Interactions matching the same request are served in recording order; once all of
+// them have been used, the last one is repeated (e.g. for status polling).
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer returns a Replayer serving the JSON-lines interactions read from @r.
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	var rp = new(Replayer)
+	var scanner = bufio.NewScanner(r)
+
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		var in Interaction
+
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		} else if err := json.Unmarshal(line, &in); err != nil {
+			return nil, errors.Errorf("invalid interaction on line %d: %s", lineNo, err)
+		}
+		rp.interactions = append(rp.interactions, in)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	rp.used = make([]bool, len(rp.interactions))
+	return rp, nil
+}
+
+// LoadReplayer returns a Replayer serving the interactions recorded in the file at @path.
+func LoadReplayer(path string) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rp, err := NewReplayer(f)
+	if err != nil {
+		return nil, errors.Errorf("%s: %s", path, err)
+	}
+	return rp, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rp *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body json.RawMessage
+
+	if req.Body != nil {
+		raw, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, errors.Errorf("failed to read %s %s request body: %s", req.Method, req.URL, err)
+		}
+		body = redactBody(req.URL.Path, raw)
+	}
+
+	in, err := rp.match(req.Method, req.URL.RequestURI(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	body = in.ResponseBody
+	if !strings.Contains(in.ContentType, "json") {
+		var text string
+		/* Non-JSON bodies are recorded as JSON string, see redactBody(). */
+		if json.Unmarshal(body, &text) == nil {
+			body = []byte(text)
+		}
+	}
+
+	res := &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+	if in.ContentType != "" {
+		res.Header.Set("Content-Type", in.ContentType)
+	}
+	return res, nil
+}
+
+// Unused returns the recorded interactions that have not been replayed so far.
+func (rp *Replayer) Unused() (res []Interaction) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	for i, in := range rp.interactions {
+		if !rp.used[i] {
+			res = append(res, in)
+		}
+	}
+	return res
+}
+
+// match returns the next interaction recorded for @verb @path with @body.
+func (rp *Replayer) match(verb, path string, body json.RawMessage) (*Interaction, error) {
+	var last = -1
+
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	for i := range rp.interactions {
+		in := &rp.interactions[i]
+		if in.Verb != verb || in.Path != path || !bytes.Equal(normalize(in.RequestBody), normalize(body)) {
+			continue
+		} else if !rp.used[i] {
+			rp.used[i] = true
+			return in, nil
+		}
+		last = i
+	}
+	if last < 0 {
+		return nil, errors.Errorf("no recorded interaction for %s %s", verb, path)
+	}
+	return &rp.interactions[last], nil
+}
+
+// redactBody returns the body of a request to @path, redacted the same way as clcv2 log output (see
+// clcv2.RedactBody). Bodies that are not valid JSON are stored as JSON string.
+func redactBody(path string, body []byte) json.RawMessage {
+	var buf bytes.Buffer
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	body = clcv2.RedactBody(path, body)
+	if err := json.Compact(&buf, body); err != nil {
+		enc, _ := json.Marshal(string(body))
+		return enc
+	}
+	return buf.Bytes()
+}
+
+// normalize returns a canonical encoding of the JSON in @raw, for comparison purposes.
+func normalize(raw json.RawMessage) []byte {
+	var v interface{}
+
+	if len(raw) == 0 {
+		return nil
+	} else if err := decodeJSON(raw, &v); err != nil {
+		return raw
+	}
+	enc, _ := json.Marshal(v)
+	return enc
+}
+
+// decodeJSON decodes @data into @v, retaining the precision of numbers.
+func decodeJSON(data []byte, v interface{}) error {
+	var dec = json.NewDecoder(bytes.NewReader(data))
+
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	} else if dec.More() {
+		return errors.Errorf("trailing data after JSON value")
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package clcv2test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grrtrr/clcv2"
+)
+
+// replay returns a client served from @fixture, failing @t if that is not possible.
+func replay(t *testing.T, fixture string) (*clcv2.Client, *Replayer) {
+	client, rp, err := NewReplayClient(fixture)
+	if err != nil {
+		t.Fatalf("failed to replay %s: %s", fixture, err)
+	}
+	return client, rp
+}
+
+// checkUsed fails @t if some of the interactions of @rp have not been replayed.
+func checkUsed(t *testing.T, rp *Replayer) {
+	for _, in := range rp.Unused() {
+		t.Errorf("interaction %s %s was not replayed", in.Verb, in.Path)
+	}
+}
+
+func TestReplayServer(t *testing.T) {
+	client, rp := replay(t, "testdata/server.jsonl")
+
+	srv, err := client.GetServer("WA1ABCDWEB01")
+	if err != nil {
+		t.Fatalf("GetServer failed: %s", err)
+	}
+	if srv.Name != "WA1ABCDWEB01" || srv.GroupId != "2a5c0b9662cf4fc8bf6180f139facdc0" || srv.Status != "active" {
+		t.Errorf("unexpected server %s in %s (%s)", srv.Name, srv.GroupId, srv.Status)
+	}
+	if d := srv.Details; d.Cpu != 2 || d.MemoryMb != 4096 || d.StorageGb != 60 || d.PowerState != "started" || len(d.Disks) != 3 {
+		t.Errorf("unexpected details: cpu=%d mem=%d storage=%d power=%s disks=%d", d.Cpu, d.MemoryMb, d.StorageGb, d.PowerState, len(d.Disks))
+	}
+	if ips := srv.IPs(); len(ips) != 3 {
+		t.Errorf("expected 3 IP addresses, got %v", ips)
+	}
+	if len(srv.Details.Snapshots) != 1 || len(srv.Details.CustomFields) != 1 || srv.Details.CustomFields[0].Value != "IT-DEV" {
+		t.Errorf("unexpected snapshots %v or custom fields %v", srv.Details.Snapshots, srv.Details.CustomFields)
+	}
+	if created := time.Date(2017, 3, 1, 10, 15, 22, 0, time.UTC); !srv.ChangeInfo.CreatedDate.Equal(created) {
+		t.Errorf("expected creation date %s, got %s", created, srv.ChangeInfo.CreatedDate)
+	}
+
+	if _, err := client.GetServer("WA1ABCDNOPE01"); !clcv2.IsNotFound(err) {
+		t.Errorf("expected a not-found error, got %v", err)
+	}
+	checkUsed(t, rp)
+}
+
+func TestReplayGroupTree(t *testing.T) {
+	client, rp := replay(t, "testdata/group.jsonl")
+
+	root, err := client.GetGroup("8b9a3e1c2d4f4a6b9c0d1e2f3a4b5c6d")
+	if err != nil {
+		t.Fatalf("GetGroup failed: %s", err)
+	}
+	if root.Name != "WA1 Hardware" || len(root.Groups) != 3 {
+		t.Fatalf("unexpected root group %q with %d sub-groups", root.Name, len(root.Groups))
+	}
+
+	web := clcv2.FindGroupNode(root, func(g *clcv2.Group) bool { return g.Name == "Web" })
+	if web == nil {
+		t.Fatalf("group Web not found")
+	} else if servers := clcv2.ExtractLinks(web.Links, "server"); web.Serverscount != 2 || len(servers) != 2 {
+		t.Errorf("expected 2 servers in Web, got %d (%d links)", web.Serverscount, len(servers))
+	} else if len(web.Groups) != 1 || web.Groups[0].Name != "Staging" {
+		t.Errorf("expected Web to contain Staging, got %v", web.Groups)
+	}
+	checkUsed(t, rp)
+}
+
+func TestReplayStatus(t *testing.T) {
+	client, rp := replay(t, "testdata/status.jsonl")
+
+	statusID, err := client.PowerOnServer("WA1ABCDWEB01")
+	if err != nil {
+		t.Fatalf("PowerOnServer failed: %s", err)
+	} else if statusID != "wa1-123456" {
+		t.Errorf("expected status ID wa1-123456, got %q", statusID)
+	}
+	if _, err := client.PowerOffServer("WA1ABCDWEB02"); err == nil {
+		t.Errorf("expected PowerOffServer to fail")
+	}
+
+	// The last status is repeated once all recorded responses have been used.
+	for _, expected := range []clcv2.QueueStatus{clcv2.NotStarted, clcv2.Executing, clcv2.Succeeded, clcv2.Succeeded} {
+		if status, err := client.GetStatus(statusID); err != nil {
+			t.Fatalf("GetStatus failed: %s", err)
+		} else if status != expected {
+			t.Errorf("expected status %s, got %s", expected, status)
+		}
+	}
+	checkUsed(t, rp)
+}
+
+func TestReplayLbInstances(t *testing.T) {
+	client, rp := replay(t, "testdata/lbaas.jsonl")
+
+	lbs, err := client.GetLbInstances("WA1")
+	if err != nil {
+		t.Fatalf("GetLbInstances failed: %s", err)
+	} else if len(lbs) != 1 {
+		t.Fatalf("expected 1 load balancer, got %d", len(lbs))
+	}
+	if lb := lbs[0]; lb.Name != "web-lb" || lb.ID.String() != "6b4a2d60-7c3e-4a5b-9f1d-2e3c4b5a6d7e" {
+		t.Errorf("unexpected load balancer %s (%s)", lb.Name, lb.ID)
+	}
+	checkUsed(t, rp)
+}
+
+func TestReplaySBSRestorePoints(t *testing.T) {
+	client, rp := replay(t, "testdata/sbs.jsonl")
+
+	rps, err := client.SBSgetRestorePointDetails("1c2d3e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f", "5f9d0a64-4b4d-4c4c-9d2e-6c3f2a1b0e9d",
+		time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2017, 3, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("SBSgetRestorePointDetails failed: %s", err)
+	} else if len(rps) != 2 {
+		t.Fatalf("expected 2 restore points, got %d", len(rps))
+	}
+	if rp := rps[0]; rp.RestorePointID != "20170301021201" || rp.RetentionDays != 7 || rp.FilesTransferredToStorage != 113 {
+		t.Errorf("unexpected restore point %+v", rp)
+	}
+	// Exceeds the precision of float64
+	if rps[1].UnchangedBytesInStorage != 9007199254740993 {
+		t.Errorf("expected 9007199254740993 unchanged bytes, got %d", rps[1].UnchangedBytesInStorage)
+	}
+	checkUsed(t, rp)
+}
+
+// Recorded fixtures must not contain any of the secrets that clcv2 redacts from its log output.
+func TestRecorderRedaction(t *testing.T) {
+	var ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/credentials") {
+			w.Write([]byte(`{"userName":"root","password":"secret-credentials"}`))
+		} else {
+			w.Write([]byte(`{"bearerToken":"secret-token"}`))
+		}
+	}))
+	var recording bytes.Buffer
+	var client = &http.Client{Transport: NewRecorder(&recording, nil)}
+
+	defer ts.Close()
+
+	for _, req := range []struct{ path, body string }{
+		{"/v2/authentication/login", `{"username":"user","password":"secret-login"}`},
+		{"/v2/servers/ABCD", `{"name":"web","password":"secret-new","sourceServerPassword":"secret-source"}`},
+		{"/v2/operations/ABCD/servers/WA1ABCDWEB01/changePassword", `{"current":"secret-current","password":"secret-changed"}`},
+		{"/v2/servers/ABCD/WA1ABCDWEB01/credentials", ""},
+	} {
+		res, err := client.Post(ts.URL+req.path, "application/json", strings.NewReader(req.body))
+		if err != nil {
+			t.Fatalf("POST %s failed: %s", req.path, err)
+		}
+		ioutil.ReadAll(res.Body)
+		res.Body.Close()
+	}
+
+	if strings.Contains(recording.String(), "secret") {
+		t.Errorf("recording contains secrets:\n%s", recording.String())
+	}
+	if n := strings.Count(recording.String(), clcv2.Redacted); n != 9 {
+		t.Errorf("expected 9 redacted values, got %d:\n%s", n, recording.String())
+	}
+	if !strings.Contains(recording.String(), `"name":"web"`) {
+		t.Errorf("recording lacks non-secret fields:\n%s", recording.String())
+	}
+}
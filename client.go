@@ -7,11 +7,10 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"mime"
 	"net/http"
-	"net/http/httputil"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/rehttp"
@@ -23,10 +22,10 @@ const (
 	// CenturyLink Cloud main v2 API url
 	BaseURL = "https://api.ctl.io"
 
-	// Maximum number of retries per request.
+	// Maximum number of retries per request (default for RetryPolicy.MaxAttempts).
 	MaxRetries = 3
 
-	// Per-request retry delay for the retryer.
+	// Per-request retry delay for the retryer (base of the default RetryPolicy.Backoff).
 	StepDelay = time.Second * 10
 )
 
@@ -35,14 +34,11 @@ var (
 	// Errors returned by this package
 	ErrCredentialsInValid = errors.New("authentication credentials are stale or invalid")
 
-	// Set this to true for debugging output
+	// Set this to true for debugging output (default for clients that do not use WithDebug)
 	Debug bool
 
-	// Upper bound on client operations - default timeout value
+	// Upper bound on client operations - default timeout value (see also WithTimeout)
 	ClientTimeout = 180 * time.Second
-
-	// allow overriding of the %BaseURL default
-	baseURL = BaseURL
 )
 
 // Client wraps a http.Client, along with credentials and logging information.
@@ -50,42 +46,156 @@ type Client struct {
 	// Login credentials
 	LoginReq
 
-	// AccountAlias to use (defaults to @credentials.AccountAlias, but can be overridden)
+	// Effective account alias: the account that all API calls act on, except for those documented to
+	// (also) use the registered alias of the login (GetServerNets, LookupMatchingNet).
+	// Defaults to the registered alias (see RegisteredAccountAlias). Rather than changing it while the
+	// client is in use, switch accounts via WithAccount().
 	AccountAlias string
 
-	// Similar to @AccountAlias, set the default data center
+	// Similar to @AccountAlias, set the default data center (see WithLocation)
 	LocationAlias string
 
-	// Logger used for (debugging) output.
+	// Logger used for (debugging) output. Superseded by WithLeveledLogger().
 	Log logrus.StdLogger
 
 	/*
 	 * private
 	 */
-	// Authentication information (may be updated when the BearerToken is stale)
-	credentials *LoginRes
+	// State shared by @c and all of its views (see WithAccount)
+	*session
 
 	// Performs the actual requests
 	requestor *http.Client
 
+	// API endpoints used by this client
+	endpoints Endpoints
+
+	// Underlying transport of @requestor (nil means http.DefaultTransport)
+	transport http.RoundTripper
+
+	// Upper bound on client operations (defaults to %ClientTimeout)
+	timeout time.Duration
+
+	// Determines which failed requests are retried (defaults to DefaultRetryPolicy())
+	retryPolicy RetryPolicy
+
+	// Maximum number of retries set via WithMaxRetries (overrides @retryPolicy.MaxAttempts if >= 0)
+	maxRetries int
+
+	// Whether to dump requests/responses to @Log (defaults to %Debug)
+	debug bool
+
+	// Levelled logger, takes precedence over @Log
+	logger Logger
+
+	// Prefix of request IDs (see newRequestID())
+	logPrefix string
+
+	// Instrumentation callbacks, called for each request
+	hooks []Hooks
+
+	// Client-side throttling per endpoint family (no limits by default)
+	limits map[EndpointFamily]Limits
+
+	// Poll interval of Jobs, overrides the per-type defaults if > 0
+	pollInterval time.Duration
+
+	// Optional journal of queued operations
+	journal *Journal
+
+	// Optional cache of GET responses
+	cache *ResponseCache
+
 	// Cancellation context (used by @cancel). Can be overridden via SetContext()
 	ctx context.Context
 
 	// Cancels this client via @ctx
 	cancel context.CancelFunc
 
-	// controls automatic re-login
-	retryingLogin bool
+	// Optional replacement of login() used by relogin(), e.g. to share tokens among processes
+	refresh func(ctx context.Context, staleToken string) error
 
-	// Optional callback which is called when @credentials are updated
+	// Optional callback which is called once after each re-login (see WithCredentialsChanged)
 	credentialsChanged func() error
 }
 
-// RegisteredAccountAlias is a read-only get method for credentials.AccountAlias
+// session is the part of a Client that is shared with its views.
+type session struct {
+	// Sequence number of request IDs (first, to be 64-bit aligned for atomic access)
+	requestSeq uint64
+
+	// Authentication information (may be updated when the BearerToken is stale)
+	credentials *LoginRes
+
+	// Protects @credentials, which may be replaced by a concurrent re-login
+	credMu sync.RWMutex
+
+	// Serializes logins, so that at most one (re-)login is in flight at a time
+	loginMu sync.Mutex
+
+	// Client-side throttles per endpoint family, created on demand from Client.limits
+	throttles  map[EndpointFamily]*throttle
+	throttleMu sync.Mutex
+}
+
+// WithAccount returns a view of @c that acts on behalf of the account @alias (e.g. a sub-account).
+// The view shares login session, transport, throttling, and all other settings with @c.
+func (c *Client) WithAccount(alias string) *Client {
+	var v = *c
+
+	v.AccountAlias = alias
+	return &v
+}
+
+// WithLocation returns a view of @c whose default data centre is @location (see WithAccount).
+func (c *Client) WithLocation(location string) *Client {
+	var v = *c
+
+	v.LocationAlias = location
+	return &v
+}
+
+// RegisteredAccountAlias returns the account alias of the login user, which may differ from
+// the effective @AccountAlias. It is the parent account when working with a sub-account.
 func (c *Client) RegisteredAccountAlias() string {
+	c.credMu.RLock()
+	defer c.credMu.RUnlock()
+
+	if c.credentials == nil {
+		return ""
+	}
 	return c.credentials.AccountAlias
 }
 
+// bearerToken returns the current bearer token, or "" if not logged in.
+func (c *Client) bearerToken() string {
+	c.credMu.RLock()
+	defer c.credMu.RUnlock()
+
+	if c.credentials == nil {
+		return ""
+	}
+	return c.credentials.BearerToken
+}
+
+// loginCredentials returns a copy of the credentials of the current login (empty if not logged in).
+func (c *Client) loginCredentials() LoginRes {
+	c.credMu.RLock()
+	defer c.credMu.RUnlock()
+
+	if c.credentials == nil {
+		return LoginRes{}
+	}
+	return *c.credentials
+}
+
+// setCredentials atomically replaces the credentials of @c with @creds.
+func (c *Client) setCredentials(creds *LoginRes) {
+	c.credMu.Lock()
+	c.credentials = creds
+	c.credMu.Unlock()
+}
+
 // LoginReq is the data structure required to perform the initial CLCv2 login request.
 type LoginReq struct {
 	// Control Portal user name.
@@ -121,51 +231,105 @@ func (l LoginRes) String() string {
 
 // NewClient returns an initialized client, performing the login request.
 func NewClient(user, pass string) (*Client, error) {
-	var client = newClient(user, pass)
+	return NewClientWithOptions(WithCredentials(user, pass))
+}
 
-	if err := client.login(); err != nil {
+// NewClientWithOptions returns a client configured via @opts, performing the login request.
+// Settings not provided via @opts default to the package-level values (%DefaultEndpoints,
+// %ClientTimeout, DefaultRetryPolicy(), %Debug).
+func NewClientWithOptions(opts ...Option) (*Client, error) {
+	var client = newClient(opts...)
+
+	if err := client.login(client.ctx); err != nil {
 		return nil, err
 	}
 	return client, nil
 }
 
 // newClient initializes the parts common to both Client and CLIClient
-func newClient(user, pass string) *Client {
-	var client = &Client{LoginReq: LoginReq{
-		Username: user,
-		Password: pass,
-	}}
+func newClient(opts ...Option) *Client {
+	var client = &Client{
+		session:     new(session),
+		endpoints:   DefaultEndpoints,
+		timeout:     ClientTimeout,
+		debug:       Debug,
+		retryPolicy: DefaultRetryPolicy(),
+		maxRetries:  -1,
+		logPrefix:   newLogPrefix(),
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+	if client.maxRetries >= 0 { // regardless of whether WithRetryPolicy came first or last
+		client.retryPolicy.MaxAttempts = client.maxRetries + 1
+	}
+	if client.transport == nil {
+		client.transport = http.DefaultTransport
+	}
 
 	client.requestor = &http.Client{
-		Transport: rehttp.NewTransport(nil, // use http.DefaultTransport
-			client.retryer(MaxRetries),
-			// Note: using ClientTimeout as upper bound for the exponential backoff.
-			//       This means g_timeout has to be large enough to run MaxRetries
-			//       requests with individual retries.
-			rehttp.ExpJitterDelay(StepDelay, ClientTimeout),
+		Transport: rehttp.NewTransport(retryThrottle{countingTransport{client.transport}},
+			client.retryer(),
+			client.retryDelay(),
 		),
 		// Timeout applies to all retries taken together as a whole.
 		// See https://medium.com/@nate510/don-t-use-go-s-default-http-client-4804cb19f779
-		Timeout: ClientTimeout,
+		Timeout: client.timeout,
 	}
 	client.ctx, client.cancel = context.WithCancel(context.Background())
 
 	return client
 }
 
-// login wipes olds credentials, logs in, and updates credentials if successful.
-func (c *Client) login() error {
-	c.credentials = new(LoginRes)
+// Endpoints returns the API endpoints used by @c.
+func (c *Client) Endpoints() Endpoints {
+	return c.endpoints
+}
+
+// login logs in, and updates credentials if successful.
+// Account and location alias are set to the login defaults, unless already set.
+func (c *Client) login(ctx context.Context) error {
+	var creds = new(LoginRes)
 
 	if c.LoginReq.Username == "" || c.LoginReq.Password == "" {
 		return errors.Errorf("invalid CLC credentials %q/%q", c.LoginReq.Username, c.LoginReq.Password)
 	}
 
-	if err := c.getCLCResponse("POST", "/v2/authentication/login", &c.LoginReq, c.credentials); err != nil {
+	if err := c.getCLCResponse(ctx, "POST", "/v2/authentication/login", &c.LoginReq, creds); err != nil {
+		return err
+	}
+	c.setCredentials(creds)
+
+	if c.AccountAlias == "" {
+		c.AccountAlias = creds.AccountAlias
+	}
+	if c.LocationAlias == "" {
+		c.LocationAlias = creds.LocationAlias
+	}
+	return nil
+}
+
+// relogin refreshes the credentials after @staleToken was rejected by the server.
+// Concurrent callers are serialized: only the first one performs the login, the others
+// find that the token has already been replaced and reuse the fresh one. Hence
+// @c.credentialsChanged is called once per re-login, however many requests were rejected.
+func (c *Client) relogin(ctx context.Context, staleToken string) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	if c.bearerToken() != staleToken {
+		return nil // another goroutine has already logged in again
+	}
+	c.log(LevelInfo, "credentials are stale, trying new login", Fields{"user": c.Username})
+	if c.refresh != nil {
+		if err := c.refresh(ctx, staleToken); err != nil {
+			return err
+		}
+	} else if err := c.login(ctx); err != nil {
 		return err
 	}
-	c.AccountAlias = c.credentials.AccountAlias
-	c.LocationAlias = c.credentials.LocationAlias
+	c.log(LevelInfo, "login successful", Fields{"user": c.Username})
 
 	if c.credentialsChanged != nil {
 		return c.credentialsChanged()
@@ -173,11 +337,22 @@ func (c *Client) login() error {
 	return nil
 }
 
-// SetContext sets the cancellation context of @c to @ctx
+// SetContext sets the cancellation context of @c to @ctx.
+// This context is used by all methods that do not take an explicit context argument.
 func (c *Client) SetContext(ctx context.Context) {
 	c.ctx, c.cancel = context.WithCancel(ctx)
 }
 
+// SetCache makes @c cache GET responses in @rc. A nil @rc disables caching.
+func (c *Client) SetCache(rc *ResponseCache) {
+	c.cache = rc
+}
+
+// Cache returns the response cache of @c, or nil if none is used.
+func (c *Client) Cache() *ResponseCache {
+	return c.cache
+}
+
 // Cancel cancels @c.ctx
 func (c *Client) Cancel() {
 	c.cancel()
@@ -188,51 +363,46 @@ func (c *Client) Context() context.Context {
 	return c.ctx
 }
 
-// retryer implements the retry policy: (a) any failure, (b) temporary failure status codes
-func (c *Client) retryer(maxRetries int) rehttp.RetryFn {
-	return rehttp.RetryFn(func(at rehttp.Attempt) bool {
-		if c.ctx != nil && c.ctx.Err() != nil {
-			return false
-		}
-		if at.Index < maxRetries {
-			if at.Response == nil {
-				if c.Log != nil {
-					c.Log.Printf("request failed - retry #%d", at.Index+1)
-				}
-				return true
-			}
-			/* Request timeout, server error, bad gateway, service unavailable, gateway timeout */
-			switch at.Response.StatusCode {
-			case 408, 500, 502, 503, 504:
-				if c.Log != nil {
-					c.Log.Printf("request returned %q - retry #%d", at.Response.Status, at.Index+1)
-				}
-				return true
-			}
-		}
-		return false
-	})
-}
-
 // getCLCResponse performs a CLC v2 main API request
 // @verb: Http verb to use
-// @path: relative to BaseURL (includes the 'v2' version).
-func (c *Client) getCLCResponse(verb, path string, reqModel, resModel interface{}) error {
-	return c.getResponse(baseURL+path, verb, reqModel, resModel)
+// @path: relative to the main API endpoint (includes the 'v2' version).
+func (c *Client) getCLCResponse(ctx context.Context, verb, path string, reqModel, resModel interface{}) error {
+	return c.getResponse(ctx, FamilyAPI, c.endpoints.API+path, verb, reqModel, resModel)
 }
 
 // getResponse performs a generic request
+// @family:   endpoint family of @url (for client-side throttling)
 // @url:      request URL
 // @verb:     request verb
 // @reqModel: request model to serialize, or nil.
 // @resModel: result model to deserialize, must be a pointer to the expected result, or nil.
 // Evaluates the StatusCode of the BaseResponse (embedded) in @inModel and sets @err accordingly.
 // If @err == nil, fills in @resModel, else returns error.
-func (c *Client) getResponse(url, verb string, reqModel, resModel interface{}) error {
+// If the bearer token has become stale, logs in again and retries the request once.
+func (c *Client) getResponse(ctx context.Context, family EndpointFamily, url, verb string, reqModel, resModel interface{}) error {
+	if c.cache == nil {
+		return c.doResponse(ctx, family, url, verb, reqModel, resModel, nil)
+	} else if verb != "GET" || resModel == nil {
+		defer c.cache.Invalidate(url)
+		return c.doResponse(ctx, family, url, verb, reqModel, resModel, nil)
+	}
+	return c.cache.get(ctx, url, resModel, func(raw *json.RawMessage) error {
+		if raw == nil { // not cached
+			return c.doResponse(ctx, family, url, verb, reqModel, resModel, nil)
+		}
+		return c.doResponse(ctx, family, url, verb, reqModel, raw, nil)
+	})
+}
+
+// doResponse implements getResponse. A 401 response to the initial request (@retryStatus == nil) triggers a
+// re-login, followed by a single retry. The retry is reported to the Hooks as part of the initial request: it
+// sets @retryStatus to its status code, which replaces the 401 of the initial request.
+func (c *Client) doResponse(ctx context.Context, family EndpointFamily, url, verb string, reqModel, resModel interface{}, retryStatus *int) (err error) {
 	var reqBody io.Reader
+	var jsonReq []byte
 
 	if reqModel != nil {
-		jsonReq, err := json.Marshal(reqModel)
+		jsonReq, err = json.Marshal(reqModel)
 		if err != nil {
 			return errors.Errorf("failed to encode request model %T %+v: %s", reqModel, reqModel, err)
 		}
@@ -249,30 +419,65 @@ func (c *Client) getResponse(url, verb string, reqModel, resModel interface{}) e
 	req, err := http.NewRequest(verb, url, reqBody)
 	if err != nil {
 		return err
-	} else if c.ctx != nil {
-		req = req.WithContext(c.ctx)
+	} else if ctx != nil {
+		req = req.WithContext(ctx)
 	}
 
-	if c.credentials != nil && c.credentials.BearerToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.credentials.BearerToken)
+	var token = c.bearerToken()
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("Accept", "application/json")
 
-	if Debug && c.Log != nil {
-		reqDump, _ := httputil.DumpRequest(req, true)
-		c.Log.Printf("%s", reqDump)
+	var id = c.newRequestID()
+	var fields = Fields{"request_id": id, "verb": verb, "path": req.URL.Path}
+	var start = time.Now()
+
+	req = req.WithContext(context.WithValue(req.Context(), requestIDKey{}, id))
+	c.log(LevelDebug, "request", fields)
+	if c.debug {
+		c.log(LevelDebug, dumpRequest(req, jsonReq), Fields{"request_id": id})
 	}
 
+	var throttle = c.throttleFor(family)
+	release, err := throttle.acquire(req.Context())
+	if err != nil {
+		return err
+	}
+	defer release()
+	req = req.WithContext(context.WithValue(req.Context(), throttledRequestKey{}, &throttledRequest{throttle: throttle}))
+
+	var done = func(status int, _ error) { *retryStatus = status }
+	if retryStatus == nil {
+		req, done = c.instrument(family, req)
+	}
 	res, err := c.requestor.Do(req)
 	if err != nil {
+		done(0, err)
+		fields["duration"], fields["error"] = time.Since(start), err
+		c.log(LevelDebug, "response", fields)
 		return err
 	}
+	var status = res.StatusCode // reported status: that of the retry, if any
+
 	defer res.Body.Close()
+	defer func() {
+		done(status, err)
+		fields["status"], fields["duration"] = res.StatusCode, time.Since(start)
+		if err != nil {
+			fields["error"] = err
+		}
+		c.log(LevelDebug, "response", fields)
+	}()
 
-	if Debug && c.Log != nil {
-		resDump, _ := httputil.DumpResponse(res, true)
-		c.Log.Printf("%s", resDump)
+	if c.debug {
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return errors.Errorf("failed to read %s %s response body: %s", verb, url, err)
+		}
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+		c.log(LevelDebug, dumpResponse(res, body), Fields{"request_id": id})
 	}
 
 	switch res.StatusCode {
@@ -290,28 +495,16 @@ func (c *Client) getResponse(url, verb string, reqModel, resModel interface{}) e
 		return nil
 	case 401:
 		// This is returned if the BearerToken is missing or has become stale.
-		if c.retryingLogin {
+		if _, isLoginReq := reqModel.(*LoginReq); isLoginReq {
+			return ErrCredentialsInValid
+		} else if retryStatus != nil {
 			return errors.New("failed to re-authenticate, credentials may be invalid")
 		}
-		if _, isLoginReq := reqModel.(*LoginReq); !isLoginReq {
-			if c.Log != nil {
-				c.Log.Printf("%s credentials are stale, trying new login ...", c.Username)
-			}
-			// FIXME: the following is not thread-safe (multiple concurrent clients):
-			c.retryingLogin = true
-			if err = c.login(); err != nil {
-				return err
-			}
-			if c.Log != nil {
-				c.Log.Printf("%s login successful, redoing original request ...", c.Username)
-			}
-			if err = c.getResponse(url, verb, reqModel, resModel); err != nil {
-				return err
-			}
-			c.retryingLogin = false
-			return nil
+		release() // do not hold on to the in-flight slot while logging in again
+		if err = c.relogin(ctx, token); err != nil {
+			return err
 		}
-		return ErrCredentialsInValid
+		return c.doResponse(ctx, family, url, verb, reqModel, resModel, &status)
 	}
 
 	// Remaining error cases: res.ContentLength is not reliable - in the SBS case, it uses
@@ -319,52 +512,6 @@ func (c *Client) getResponse(url, verb string, reqModel, resModel interface{}) e
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil && res.ContentLength > 0 {
 		return errors.Errorf("failed to read error response %d body: %s", res.StatusCode, err)
-	} else if len(body) > 0 {
-		var errMsg = string(body)
-		//
-		// So far 5 different types of response have been observed:
-		// 1) bare JSON string
-		// 2) struct { message: "string" }
-		// 3) struct { message: "string", "modelState": map[string]interface{} }
-		//    E.g.:  {"":["The server must be in Active or Archived state."]}
-		//	      "modelState":{"body.networkId":["The network vlan_1249_10.81.149 is not valid."]}
-		//	      "modelState":{"":["The server must be in Active or Archived state."]}
-		// 4) struct { error: "string" }, e.g. { "error":"Missing required parameter: serverId"}
-		// 5) struct { error: "string", validationMessages: ["string"] } - like (4), with array of messages
-		//
-		if ct, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); ct == "application/json" {
-			/* Code thanks to & inspired by clc-go-cli */
-			var payload map[string]interface{}
-			var sbsError struct {
-				Error              string
-				ValidationMessages []string
-			}
-
-			if err := json.Unmarshal(body, &payload); err != nil {
-				/* Failed to decode as struct, try string (1) next. */
-				if err = json.Unmarshal(body, &errMsg); err != nil {
-					errMsg = string(body)
-				}
-			} else if errors, ok := payload["modelState"]; ok {
-				if bytes, err := json.Marshal(errors); err == nil {
-					errMsg = string(bytes)
-				}
-			} else if errors, ok := payload["message"]; ok {
-				if msg, ok := errors.(string); ok {
-					errMsg = msg
-				}
-			} else if err = json.Unmarshal(body, &sbsError); err == nil {
-				errMsg = fmt.Sprintf("Error - %s", sbsError.Error)
-				if len(sbsError.ValidationMessages) > 0 {
-					errMsg += fmt.Sprintf(" Details: %q", strings.Join(sbsError.ValidationMessages, ", "))
-				}
-			} else if error, ok := payload["error"]; ok {
-				if msg, ok := error.(string); ok {
-					errMsg = fmt.Sprintf("Error - %s", msg)
-				}
-			}
-		}
-		return errors.Errorf("%s (status: %d)", errMsg, res.StatusCode)
 	}
-	return errors.New(res.Status)
+	return newAPIError(verb, url, res, body)
 }
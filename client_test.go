@@ -0,0 +1,191 @@
+package clcv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer is a stand-in API server that hands out a new bearer token per login, and accepts only the latest one.
+type tokenServer struct {
+	*httptest.Server
+
+	logins int32 // number of login requests
+
+	mu       sync.Mutex
+	token    string          // currently valid token
+	accepted map[string]bool // tokens of successful requests
+}
+
+func newTokenServer(t *testing.T, staleRequests int) *tokenServer {
+	var ts = &tokenServer{accepted: make(map[string]bool)}
+	var stale = make(chan struct{}) // closed once @staleRequests requests with a stale token have arrived
+	var nstale int32
+
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/authentication/login" {
+			n := atomic.AddInt32(&ts.logins, 1)
+
+			ts.mu.Lock()
+			ts.token = fmt.Sprintf("token-%d", n)
+			ts.mu.Unlock()
+
+			json.NewEncoder(w).Encode(LoginRes{User: "user", AccountAlias: "ABCD", LocationAlias: "WA1", BearerToken: fmt.Sprintf("token-%d", n)})
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		ts.mu.Lock()
+		valid := token == ts.token
+		if valid {
+			ts.accepted[token] = true
+		}
+		ts.mu.Unlock()
+
+		if !valid {
+			// Hold back the 401 responses until all concurrent requests have been rejected, so that they all relogin at once.
+			if atomic.AddInt32(&nstale, 1) == int32(staleRequests) {
+				close(stale)
+			}
+			select {
+			case <-stale:
+			case <-time.After(5 * time.Second):
+				t.Errorf("only %d of %d concurrent requests arrived", atomic.LoadInt32(&nstale), staleRequests)
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"` + r.URL.Path + `"}`))
+	}))
+	return ts
+}
+
+// expire invalidates the current token.
+func (ts *tokenServer) expire() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.token = ""
+}
+
+// Concurrent requests that are all rejected with 401 must share a single re-login.
+func TestConcurrentRelogin(t *testing.T) {
+	const N = 50
+	var ts = newTokenServer(t, N)
+	var stale int32   // number of "credentials are stale" log entries
+	var changed int32 // number of credentialsChanged callbacks
+
+	defer ts.Close()
+
+	c, err := NewClientWithOptions(
+		WithCredentials("user", "pass"),
+		WithEndpoints(Endpoints{API: ts.URL}),
+		WithCredentialsChanged(func() error {
+			atomic.AddInt32(&changed, 1)
+			return nil
+		}),
+		WithLeveledLogger(LoggerFunc(func(level LogLevel, msg string, fields Fields) {
+			if strings.HasPrefix(msg, "credentials are stale") {
+				atomic.AddInt32(&stale, 1)
+			}
+		})),
+	)
+	if err != nil {
+		t.Fatalf("login failed: %s", err)
+	}
+	ts.expire()
+
+	var start = make(chan struct{})
+	var wg sync.WaitGroup
+	var successes int32
+
+	for i := 0; i < N; i++ {
+		wg.Add(1)
+		go func(i int) {
+			var res struct{ Id string }
+
+			defer wg.Done()
+			<-start
+			if err := c.getCLCResponse(c.ctx, "GET", fmt.Sprintf("/v2/test/%d", i), nil, &res); err != nil {
+				t.Errorf("request %d failed: %s", i, err)
+			} else if res.Id != fmt.Sprintf("/v2/test/%d", i) {
+				t.Errorf("request %d: unexpected response %q", i, res.Id)
+			} else {
+				atomic.AddInt32(&successes, 1)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&ts.logins); n != 2 {
+		t.Errorf("expected exactly one re-login after the initial login, got %d logins", n)
+	}
+	if n := atomic.LoadInt32(&stale); n != 1 {
+		t.Errorf("expected exactly one credentials swap, got %d", n)
+	}
+	if n := atomic.LoadInt32(&changed); n != 1 {
+		t.Errorf("expected credentialsChanged to be called once, got %d", n)
+	}
+	if len(ts.accepted) != 1 || !ts.accepted["token-2"] || c.bearerToken() != "token-2" {
+		t.Errorf("expected all requests to use the new token, got %v (client has %q)", ts.accepted, c.bearerToken())
+	}
+	if successes != N {
+		t.Errorf("expected %d successful requests, got %d", N, successes)
+	}
+}
+
+// hookRecorder records the responses reported to Hooks.
+type hookRecorder struct {
+	mu        sync.Mutex
+	requests  int
+	responses []ResponseInfo
+}
+
+func (h *hookRecorder) OnRequest(*RequestInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests++
+}
+
+func (h *hookRecorder) OnResponse(r *ResponseInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.responses = append(h.responses, *r)
+}
+
+// A request that succeeds after a re-login is reported with its final status, the login separately.
+func TestReloginHooks(t *testing.T) {
+	var ts = newTokenServer(t, 1)
+	var h = new(hookRecorder)
+
+	defer ts.Close()
+
+	c, err := NewClientWithOptions(WithCredentials("user", "pass"), WithEndpoints(Endpoints{API: ts.URL}), WithHooks(h))
+	if err != nil {
+		t.Fatalf("login failed: %s", err)
+	}
+	ts.expire()
+
+	var res struct{ Id string }
+	h.requests, h.responses = 0, nil
+	if err := c.getCLCResponse(c.ctx, "GET", "/v2/test", nil, &res); err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+
+	if h.requests != 2 || len(h.responses) != 2 {
+		t.Fatalf("expected 2 responses (login and request), got %+v", h.responses)
+	}
+	if r := h.responses[0]; r.Path != "/v2/authentication/login" || r.StatusCode != 200 {
+		t.Errorf("expected login to be reported first, got %+v", r)
+	}
+	if r := h.responses[1]; r.Path != "/v2/test" || r.StatusCode != 200 || r.Err != nil || r.ErrorClass != ErrClassNone {
+		t.Errorf("expected request to be reported as successful, got %+v", r)
+	}
+}
@@ -1,7 +1,7 @@
 package clcv2
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -14,54 +14,84 @@ import (
 
 	yaml "gopkg.in/yaml.v2"
 
-	"github.com/grrtrr/clcv2/utils"
 	"github.com/pkg/errors"
 )
 
-const (
-	// Name of the file to store the last bearer-token credentials
-	credentialsName = "credentials.json"
-
-	// Configuration file in CLC_HOME that stores the ClientConfig
-	configName = "client_config.yml"
-)
+// Configuration file in CLC_HOME that stores the ClientConfig
+const configName = "client_config.yml"
 
 // CLIClient specializes Client for command-line use
 type CLIClient struct {
 	*Client
 	Config *ClientConfig
+
+	// Bearer tokens shared with other processes, and the key of the token of this client
+	tokens   *tokenCache
+	tokenKey string
 }
 
 // ClientConfig encapsulates a commandline-client configuration file
 type ClientConfig struct {
-	Username string `yaml:"User"`     // CLC portal username
-	Password string `yaml:"Password"` // CLC portal password (FIXME: store encrypted)
-	Account  string `yaml:"Account"`  // account that was used last time
-	Location string `yaml:"Location"` // data centre that was used last time
+	Username  string       `yaml:"User"`                // CLC portal username
+	Password  string       `yaml:"Password,omitempty"`  // CLC portal password (never saved, stored via @Secrets)
+	Account   string       `yaml:"Account"`             // account that was used last time
+	Location  string       `yaml:"Location"`            // data centre that was used last time
+	Endpoints *Endpoints   `yaml:"Endpoints,omitempty"` // API endpoint overrides (optional)
+	Secrets   SecretConfig `yaml:"Secrets,omitempty"`   // where to store the password and bearer token
+
+	// Command that supplies missing credentials (see RunCredentialHelper)
+	CredentialHelper string `yaml:"CredentialHelper,omitempty"`
+
+	// Fail instead of prompting for missing credentials
+	NonInteractive bool `yaml:"-"`
+
+	// Name of the profile these settings belong to (see ConfigFile)
+	Profile string `yaml:"-"`
+
+	// Whether @Password was loaded in cleartext from a legacy configuration file
+	cleartext bool
 }
 
 func (c ClientConfig) String() string {
-	return fmt.Sprintf("Config(%s/%s, account: %q, location: %q)",
-		c.Username, c.Password, c.Account, c.Location)
+	var password = "<none>"
+
+	if c.Password != "" {
+		password = Redacted
+	}
+	return fmt.Sprintf("Config(%s: %s/%s, account: %q, location: %q)",
+		c.Profile, c.Username, password, c.Account, c.Location)
+}
+
+// NeedsMigration returns true if @c was loaded from a configuration file that contains a cleartext password.
+func (c *ClientConfig) NeedsMigration() bool {
+	return c.cleartext
 }
 
 // NewCLIClient returns an authenticated commandline client.
+// Missing credentials are taken from the environment, a credential helper, or prompted for,
+// unless in non-interactive mode (see ClientConfig.NonInteractive).
+// The settings of @conf are completed from the saved profile named @conf.Profile (or, if
+// not set, selected via CLC_PROFILE or the default profile of the configuration file).
 // This will use the default values for AccountAlias  and LocationAlias.
 // It will respect the following environment variables to override the defaults:
 // - CLC_ACCOUNT:  takes precedence over default AccountAlias
 // - CLC_LOCATION: takes precedence over default LocationAlias
-// - CLC_BASE_URL: overrides the API URL (for testing)
-func NewCLIClient(conf *ClientConfig) (*CLIClient, error) {
+// - CLC_BASE_URL: overrides the main API endpoint (for testing)
+// Additional client settings (e.g. endpoints or transport) can be passed via @opts.
+func NewCLIClient(conf *ClientConfig, opts ...Option) (*CLIClient, error) {
+	var profile string
+
+	if conf != nil {
+		profile = conf.Profile
+	}
 	// Attempt to load existing configuration first, and reconcile with @conf.
-	savedConfig, err := LoadClientConfig()
+	savedConfig, err := LoadProfile(profile)
 	if err != nil && conf == nil {
 		return nil, errors.Errorf("failed to load saved configuration: %s", err)
 	}
 
 	if conf == nil {
-		if conf = savedConfig; conf == nil {
-			conf = &ClientConfig{}
-		}
+		conf = savedConfig
 	} else if savedConfig != nil { // Override only parameters that were not explicitly set
 		if conf.Username == "" {
 			conf.Username = savedConfig.Username
@@ -75,25 +105,62 @@ func NewCLIClient(conf *ClientConfig) (*CLIClient, error) {
 		if conf.Location == "" {
 			conf.Location = savedConfig.Location
 		}
+		if conf.Endpoints == nil {
+			conf.Endpoints = savedConfig.Endpoints
+		}
+		if conf.CredentialHelper == "" {
+			conf.CredentialHelper = savedConfig.CredentialHelper
+		}
+		if conf.Secrets == (SecretConfig{}) {
+			conf.Secrets = savedConfig.Secrets
+		}
+		conf.Profile, conf.cleartext = savedConfig.Profile, savedConfig.cleartext
+	}
+	if conf.Profile == "" {
+		conf.Profile = DefaultProfile
+	} else if !profileNameRegexp.MatchString(conf.Profile) {
+		return nil, errors.Errorf("invalid profile name %q", conf.Profile)
 	}
 
-	// Ensure that both username and password are filled in
-	conf.Username, conf.Password = utils.ResolveUserAndPass(conf.Username, conf.Password)
+	// Set/override the main API endpoint (experimental).
+	if envURL := os.Getenv("CLC_BASE_URL"); envURL != "" {
+		url, err := url.Parse(envURL)
+		if err != nil {
+			return nil, err
+		}
+		if url.Scheme == "" {
+			url.Scheme = "https"
+		}
+		opts = append([]Option{WithEndpoints(Endpoints{API: url.String()})}, opts...)
+	}
+	// Endpoints of the profile have the lowest precedence.
+	if conf.Endpoints != nil {
+		opts = append([]Option{WithEndpoints(*conf.Endpoints)}, opts...)
+	}
+
+	secrets, err := conf.Secrets.Store()
+	if err != nil {
+		return nil, err
+	}
 
 	client := &CLIClient{
-		Client: newClient(conf.Username, conf.Password),
+		Client: newClient(opts...),
 		Config: conf,
+		tokens: newTokenCache(secrets),
+	}
+
+	// Ensure that both username and password are filled in
+	if err := resolveCredentials(conf, client.endpoints.API); err != nil {
+		return nil, err
 	}
-	client.credentialsChanged = client.saveCredentials
-	if Debug {
+	client.LoginReq = LoginReq{Username: conf.Username, Password: conf.Password}
+	client.tokenKey = tokenKey(conf.Username, client.endpoints.API)
+	client.refresh = client.refreshCredentials
+	if client.debug && client.Log == nil {
 		client.Log = log.New(os.Stdout, "", log.Ltime|log.Lshortfile)
 	}
 
-	if loginRes, err := client.loadCredentials(); err != nil {
-		return nil, err
-	} else if loginRes != nil {
-		client.credentials = loginRes
-	} else if err = client.login(); err != nil {
+	if err := client.refreshCredentials(client.ctx, ""); err != nil {
 		return nil, err
 	}
 
@@ -103,7 +170,7 @@ func NewCLIClient(conf *ClientConfig) (*CLIClient, error) {
 	} else if account := os.Getenv("CLC_ACCOUNT"); account != "" {
 		client.AccountAlias = account
 	} else {
-		client.AccountAlias = client.credentials.AccountAlias
+		client.AccountAlias = client.RegisteredAccountAlias()
 	}
 
 	// Set/override LocationAlias
@@ -112,19 +179,7 @@ func NewCLIClient(conf *ClientConfig) (*CLIClient, error) {
 	} else if location := os.Getenv("CLC_LOCATION"); location != "" {
 		client.LocationAlias = location
 	} else {
-		client.LocationAlias = client.credentials.LocationAlias
-	}
-
-	// Set/override %baseURL (experimental).
-	if envURL := os.Getenv("CLC_BASE_URL"); envURL != "" {
-		url, err := url.Parse(envURL)
-		if err != nil {
-			return nil, err
-		}
-		if url.Scheme == "" {
-			url.Scheme = "https"
-		}
-		baseURL = url.String()
+		client.LocationAlias = client.loginCredentials().LocationAlias
 	}
 
 	return client, nil
@@ -149,27 +204,33 @@ func GetClcHome() string {
 	}
 }
 
-// LoadClientConfig attempts to load a configuration from CLC_HOME/configName
+// LoadClientConfig loads the settings of the selected profile (see LoadProfile).
 func LoadClientConfig() (*ClientConfig, error) {
-	var confFile = path.Join(GetClcHome(), configName)
+	return LoadProfile("")
+}
 
-	if _, err := os.Stat(confFile); err == nil {
-		var config ClientConfig
+// LoadProfile loads the settings of profile @name (see ConfigFile.ProfileName) from CLC_HOME/configName.
+// If the profile does not exist yet, it returns empty settings for it.
+// The password is retrieved from the configured SecretStore, unless the file still contains
+// it in cleartext (see NeedsMigration).
+func LoadProfile(name string) (*ClientConfig, error) {
+	f, err := LoadConfigFile()
+	if err != nil {
+		return nil, err
+	}
 
-		fd, err := os.Open(confFile)
+	config := f.Profile(name)
+	if config == nil {
+		return &ClientConfig{Profile: f.ProfileName(name), Secrets: f.Secrets}, nil
+	} else if config.Password == "" && config.Username != "" {
+		store, err := config.Secrets.Store()
 		if err != nil {
-			return nil, errors.Errorf("failed to load client config: %s", err)
-		}
-		defer fd.Close()
-
-		if content, err := ioutil.ReadAll(fd); err != nil {
-			return nil, errors.Errorf("failed to read %s: %s", confFile, err)
-		} else if err = yaml.Unmarshal(content, &config); err != nil {
-			return nil, errors.Errorf("failed to deserialize %s: %s", confFile, err)
+			return nil, err
+		} else if config.Password, err = store.Get(passwordKey(config.Username)); err != nil {
+			return nil, errors.Errorf("failed to retrieve password of %s: %s", config.Username, err)
 		}
-		return &config, nil
 	}
-	return configFromCliGo()
+	return config, nil
 }
 
 // configFromCliGo checks to see if a clc-cli-go configuration file exists.
@@ -193,15 +254,32 @@ func configFromCliGo() (*ClientConfig, error) {
 		}
 
 		return &ClientConfig{
-			Username: cliGoData["user"].(string),
-			Password: cliGoData["password"].(string),
-			Location: cliGoData["defaultdatacenter"].(string),
+			Username:  cliGoData["user"].(string),
+			Password:  cliGoData["password"].(string),
+			Location:  cliGoData["defaultdatacenter"].(string),
+			cleartext: true,
 		}, nil
 	}
 	return nil, nil
 }
 
-// SaveConfig writes the configuration data of @c to CLC_HOME/configName
+// MigrateClientConfig moves the cleartext passwords of a legacy configuration file into the
+// configured SecretStore, and rewrites the configuration without them.
+// Returns true if the configuration was migrated.
+func MigrateClientConfig() (bool, error) {
+	f, err := LoadConfigFile()
+	if err != nil {
+		return false, err
+	}
+	for _, conf := range f.Profiles {
+		if conf.NeedsMigration() {
+			return true, f.Save()
+		}
+	}
+	return false, nil
+}
+
+// SaveConfig writes the configuration data of @c to its profile in CLC_HOME/configName
 func (c *CLIClient) SaveConfig() error {
 	if c == nil || c.Client == nil {
 		return errors.New("attempt to save configuration for nil client")
@@ -209,62 +287,73 @@ func (c *CLIClient) SaveConfig() error {
 		return errors.New("attempt to save a nil client configuration")
 	}
 
-	if enc, err := yaml.Marshal(c.Config); err != nil {
-		return errors.Errorf("failed to serialize client configuration: %s", err)
-	} else {
-		return writeCLCdata(configName, enc, 0644)
+	f, err := LoadConfigFile()
+	if err != nil {
+		return err
+	}
+
+	var conf = *c.Config
+	conf.Profile = f.ProfileName(conf.Profile)
+	if err = f.SetProfile(&conf); err != nil {
+		return err
+	} else if err = f.Save(); err != nil {
+		return err
 	}
+	c.Config.cleartext = false
+	return nil
 }
 
-// Populate and allocate c.credentials, either by loading from file or via a fresh login.
-// Save (updated) credentials if successful.
-func (c *CLIClient) loadCredentials() (*LoginRes, error) {
-	var credsFile = path.Join(GetClcHome(), credentialsName)
+// storeSecret stores @secret under @key in @store, unless it is already present.
+func storeSecret(store SecretStore, key, secret string) error {
+	if cur, err := store.Get(key); err == nil && cur == secret {
+		return nil
+	}
+	return store.Set(key, secret)
+}
 
-	if _, err := os.Stat(credsFile); err == nil {
-		var loginRes = new(LoginRes)
+// refreshCredentials sets the credentials of @c from the token cache, unless the cached token
+// is about to expire, or is @staleToken. In that case, it logs in and updates the cache.
+// The cache lock is not held during the login, so as not to block other processes for the duration
+// of a network request. Afterwards, a fresh token cached by another process in the meantime takes
+// precedence, so that concurrent processes converge on a single token.
+func (c *CLIClient) refreshCredentials(ctx context.Context, staleToken string) error {
+	var usable = func(t *cachedToken) bool {
+		return t.valid() && t.Credentials.BearerToken != staleToken && strings.EqualFold(t.Credentials.User, c.LoginReq.Username)
+	}
+	var cached bool
 
-		fd, err := os.Open(credsFile)
-		if err != nil {
-			return nil, errors.Errorf("failed to load credentials: %s", err)
-		}
-		defer fd.Close()
+	err := c.tokens.update(c.tokenKey, func(t *cachedToken) (*cachedToken, error) {
+		var imported *cachedToken
 
-		if err = json.NewDecoder(fd).Decode(loginRes); err != nil {
-			return nil, errors.Errorf("failed to deserialize %s: %s", credsFile, err)
+		if t == nil { // not cached yet: try the file used by earlier versions
+			legacy, err := c.tokens.importLegacy(c.LoginReq.Username, c.endpoints.API)
+			if err != nil {
+				return nil, err
+			}
+			t, imported = legacy, legacy
 		}
-
-		if strings.ToLower(loginRes.User) == strings.ToLower(c.LoginReq.Username) {
-			return loginRes, nil
+		if cached = usable(t); cached {
+			c.setCredentials(&t.Credentials)
+			return imported, nil
 		}
-		// FIXME: if names differ, save to credsFile + loginRes.User
-		/* User switch: move the original credentials file to a backup extension. */
-		os.Rename(credsFile, credsFile+".bak")
-	} else if err != nil && !os.IsNotExist(err) {
-		return nil, err
+		return nil, nil
+	})
+	if err != nil || cached {
+		return err
+	} else if err = c.login(ctx); err != nil {
+		return err
 	}
-	return nil, nil
-}
 
-// Save credentials to CLC_HOME/$credentialsName. Return error on failure.
-func (c *CLIClient) saveCredentials() error {
-	if c.credentials == nil { // nothing to serialize
-		return nil
-	} else if enc, err := json.MarshalIndent(c.credentials, "", "\t"); err != nil {
-		return errors.Errorf("failed to serialize bearer credentials: %s", err)
-	} else {
-		return writeCLCdata(credentialsName, append(enc, '\n'), 0600)
-	}
+	return c.tokens.update(c.tokenKey, func(t *cachedToken) (*cachedToken, error) {
+		if usable(t) {
+			c.setCredentials(&t.Credentials)
+			return nil, nil
+		}
+		return newCachedToken(c.loginCredentials(), c.endpoints.API), nil
+	})
 }
 
-// writeCLCitem writes @data to CLC_HOME/fileName
+// writeCLCdata atomically writes @data to CLC_HOME/fileName
 func writeCLCdata(fileName string, data []byte, perm os.FileMode) error {
-	var clcHome = GetClcHome()
-
-	if _, err := os.Stat(clcHome); os.IsNotExist(err) {
-		if err = os.MkdirAll(clcHome, 0700); err != nil {
-			return errors.Errorf("failed to create CLC directory %s: %s", clcHome, err)
-		}
-	}
-	return ioutil.WriteFile(path.Join(clcHome, fileName), data, perm)
+	return writeFileAtomic(path.Join(GetClcHome(), fileName), data, perm)
 }
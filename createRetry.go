@@ -0,0 +1,117 @@
+package clcv2
+
+/*
+ * Duplicate-safe retries of create requests.
+ */
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// createOnce submits a create request via @submit, which is not retried by the transport. If @submit fails with an
+// error that is retryable according to @c.retryPolicy, the request may have taken effect nevertheless (e.g. a timeout
+// after the server committed the request). In that case, createOnce calls @lookup after the backoff delay, to check
+// whether the resource exists already, and only submits again if @lookup finds nothing.
+// @what:   description of the resource, for logging
+// @submit: sends the create request
+// @lookup: returns true if it has found, and adopted, the resource created by a failed attempt
+func (c *Client) createOnce(ctx context.Context, what string, submit func(context.Context) error, lookup func(context.Context) (bool, error)) error {
+	var backoff = c.backoff()
+
+	for attempt := 0; ; attempt++ {
+		err := submit(withoutRetry(ctx))
+		if err == nil || !c.retryPolicy.RetryNonIdempotent || !c.isRetryableFailure(ctx, err) {
+			return err
+		} else if attempt+1 >= c.retryPolicy.MaxAttempts {
+			c.log(LevelWarn, "giving up", Fields{"create": what, "attempts": attempt + 1, "error": err})
+			return err
+		} else if serr := sleepContext(ctx, backoff(attempt)); serr != nil {
+			return err
+		}
+
+		found, lerr := lookup(ctx)
+		if lerr != nil {
+			return errors.Errorf("%s (failed to check whether %s was created nevertheless: %s)", err, what, lerr)
+		} else if found {
+			c.log(LevelInfo, "adopting resource created by failed request", Fields{"create": what, "error": err})
+			return nil
+		}
+		c.log(LevelInfo, "retrying", Fields{"create": what, "retry": attempt + 1, "error": err})
+	}
+}
+
+// isRetryableFailure returns true if @err is a transport failure, or an API error with a retryable status.
+func (c *Client) isRetryableFailure(ctx context.Context, err error) bool {
+	var apiErr *APIError
+
+	if ctx.Err() != nil {
+		return false
+	} else if errors.As(err, &apiErr) {
+		return c.retryPolicy.isRetryableStatus(apiErr.StatusCode)
+	}
+	_, isTransportErr := err.(*url.Error)
+	return isTransportErr
+}
+
+// linkIDs returns the set of IDs of the @rel links in @links.
+func linkIDs(links []Link, rel string) map[string]bool {
+	var res = make(map[string]bool)
+
+	for _, l := range ExtractLinks(links, rel) {
+		res[strings.ToUpper(l.Id)] = true
+	}
+	return res
+}
+
+// newCreateMarker returns a random correlation marker, which identifies the resource created by a request via its description.
+func newCreateMarker() string {
+	var b = make([]byte, 6)
+
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("[clcv2:%x]", time.Now().UnixNano())
+	}
+	return fmt.Sprintf("[clcv2:%x]", b)
+}
+
+// withMarker returns @desc with the correlation @marker appended.
+func withMarker(desc, marker string) string {
+	return strings.TrimSpace(desc + " " + marker)
+}
+
+// findCreatedServer looks for a server in @groupID that is not in @existing (the server IDs of @groupID before the request),
+// and whose description contains the correlation @marker of the request.
+// Returns the 'self' link of the matching server, or "" if none was found.
+func (c *Client) findCreatedServer(ctx context.Context, groupID, marker string, existing map[string]bool) (string, error) {
+	var matches []Link
+
+	group, err := c.GetGroupContext(ctx, groupID)
+	if err != nil {
+		return "", err
+	}
+	for _, l := range ExtractLinks(group.Links, "server") {
+		if existing[strings.ToUpper(l.Id)] {
+			continue
+		}
+		if s, err := c.GetServerContext(ctx, l.Id); IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		} else if strings.Contains(s.Description, marker) {
+			matches = append(matches, l)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0].Href, nil
+	}
+	return "", errors.Errorf("ambiguous: %d new servers carry the marker %s in group %s", len(matches), marker, groupID)
+}
@@ -0,0 +1,170 @@
+package clcv2_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/grrtrr/clcv2/clcv2test"
+)
+
+// retryClient returns a fake server with a group 'Web' in WA1, and a client that retries non-idempotent requests.
+func retryClient(t *testing.T, opts ...clcv2.Option) (f *clcv2test.FakeServer, client *clcv2.Client, groupID string) {
+	var policy = clcv2.DefaultRetryPolicy()
+
+	policy.RetryNonIdempotent = true
+	policy.Backoff = func(int) time.Duration { return time.Millisecond }
+
+	f = clcv2test.NewFakeServer("ABCD", "WA1")
+	client, err := f.Client(append([]clcv2.Option{clcv2.WithRetryPolicy(policy)}, opts...)...)
+	if err != nil {
+		f.Close()
+		t.Fatalf("login failed: %s", err)
+	}
+	if groupID, err = f.AddGroup(f.RootGroup("WA1"), "Web"); err != nil {
+		f.Close()
+		t.Fatal(err)
+	}
+	return f, client, groupID
+}
+
+// createServerReq returns a request to create server 'WEB' in @groupID.
+func createServerReq(groupID string) *clcv2.CreateServerReq {
+	return &clcv2.CreateServerReq{
+		Name:           "WEB",
+		Description:    "web server",
+		GroupId:        groupID,
+		SourceServerId: "UBUNTU-16-64-TEMPLATE",
+		Cpu:            1,
+		MemoryGB:       2,
+		Type:           "standard",
+	}
+}
+
+// A server created by a request whose response got lost is adopted, rather than created a second time.
+func TestCreateServerDroppedResponse(t *testing.T) {
+	f, client, groupID := retryClient(t)
+	defer f.Close()
+
+	var req = createServerReq(groupID)
+
+	f.DropResponses("POST", "/v2/servers/ABCD", 1)
+	url, statusID, err := client.CreateServerContext(client.Context(), req)
+	if err != nil {
+		t.Fatalf("CreateServer failed: %s", err)
+	} else if req.Description != "web server" {
+		t.Errorf("request was modified: %+v", req)
+	}
+
+	group, err := client.GetGroup(groupID)
+	if err != nil {
+		t.Fatalf("GetGroup failed: %s", err)
+	}
+	servers := clcv2.ExtractLinks(group.Links, "server")
+	if len(servers) != 1 {
+		t.Fatalf("expected exactly 1 server, got %d", len(servers))
+	}
+	if url != servers[0].Href {
+		t.Errorf("expected URL %s of the existing server, got %q", servers[0].Href, url)
+	}
+	if statusID != "" {
+		t.Errorf("expected no status ID for an adopted server, got %q", statusID)
+	}
+	if s, _ := f.ServerState(servers[0].Id); !strings.HasPrefix(s.Description, "web server [clcv2:") {
+		t.Errorf("expected the description to carry a correlation marker, got %q", s.Description)
+	}
+
+	// The Job of an adopted server has already completed.
+	f.DropResponses("POST", "/v2/servers/ABCD", 1)
+	req = createServerReq(groupID)
+	req.Name = "DB"
+	if _, job, err := client.CreateServerJob(req); err != nil {
+		t.Fatalf("CreateServerJob failed: %s", err)
+	} else if job.Status() != clcv2.Succeeded {
+		t.Errorf("expected the job of the adopted server to have succeeded, got %s", job.Status())
+	} else if err = job.Wait(client.Context()); err != nil {
+		t.Errorf("waiting for the adopted server failed: %s", err)
+	}
+}
+
+// A group created by a request whose response got lost is adopted, rather than created a second time.
+func TestCreateGroupDroppedResponse(t *testing.T) {
+	f, client, _ := retryClient(t)
+	defer f.Close()
+
+	var root = f.RootGroup("WA1")
+
+	f.DropResponses("POST", "/v2/groups/ABCD", 1)
+	res, err := client.CreateGroupContext(client.Context(), "DB", root, "database servers", nil)
+	if err != nil {
+		t.Fatalf("CreateGroup failed: %s", err)
+	}
+
+	parent, err := client.GetGroup(root)
+	if err != nil {
+		t.Fatalf("GetGroup failed: %s", err)
+	}
+	var created []clcv2.Group
+	for _, g := range parent.Groups {
+		if g.Name == "DB" {
+			created = append(created, g)
+		}
+	}
+	if len(created) != 1 {
+		t.Fatalf("expected exactly 1 group DB, got %d", len(created))
+	}
+	if res.Id != created[0].Id || res.Description != "database servers" {
+		t.Errorf("expected the existing group %s to be returned, got %q (%q)", created[0].Id, res.Id, res.Description)
+	}
+}
+
+// addServerHook adds another server called @name to @groupID of @f when the first create request is sent.
+type addServerHook struct {
+	f             *clcv2test.FakeServer
+	groupID, name string
+	added         bool
+}
+
+func (h *addServerHook) OnRequest(r *clcv2.RequestInfo) {
+	if r.Verb == "POST" && r.Path == "/v2/servers/ABCD" && !h.added {
+		if _, err := h.f.AddServer(h.groupID, h.name); err == nil {
+			h.added = true
+		}
+	}
+}
+
+func (h *addServerHook) OnResponse(*clcv2.ResponseInfo) {}
+
+// A server with the same name and description, created by someone else in the meantime, is not adopted.
+func TestCreateServerConcurrent(t *testing.T) {
+	var hook = new(addServerHook)
+	f, client, groupID := retryClient(t, clcv2.WithHooks(hook))
+	defer f.Close()
+
+	var req = createServerReq(groupID)
+
+	req.Description = "" // as for servers added via AddServer
+	hook.f, hook.groupID, hook.name = f, groupID, req.Name
+
+	f.DropResponses("POST", "/v2/servers/ABCD", 1)
+	url, _, err := client.CreateServerContext(client.Context(), req)
+	if err != nil {
+		t.Fatalf("CreateServer failed: %s", err)
+	}
+
+	group, err := client.GetGroup(groupID)
+	if err != nil {
+		t.Fatalf("GetGroup failed: %s", err)
+	}
+	servers := clcv2.ExtractLinks(group.Links, "server")
+	if len(servers) != 2 {
+		t.Fatalf("expected no further server to be created, got %d servers", len(servers))
+	}
+	// The other server was added first, and has the lower number.
+	if servers[0].Id != "WA1ABCDWEB01" || servers[1].Id != "WA1ABCDWEB02" {
+		t.Fatalf("unexpected servers %+v", servers)
+	} else if url != servers[1].Href {
+		t.Errorf("expected URL %s of the server created by the request, got %q", servers[1].Href, url)
+	}
+}
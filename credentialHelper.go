@@ -0,0 +1,110 @@
+package clcv2
+
+/*
+ * Credential helpers: external programs that supply login credentials, similar to git credential helpers.
+ *
+ * The helper command is run via the shell, with "get" appended as argument. It receives a description
+ * of the login on stdin, as key=value lines terminated by an empty line, e.g.
+ *
+ *	protocol=https
+ *	host=api.ctl.io
+ *	endpoint=https://api.ctl.io
+ *	user=jdoe
+ *	profile=default
+ *
+ * The "user" line is omitted if the username is not known yet. The helper replies on stdout with
+ * a JSON object, e.g. {"username": "jdoe", "password": "secret"}. Empty output, or an empty field,
+ * means that the helper has no credentials to offer. A non-zero exit status is treated as an error.
+ */
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/grrtrr/clcv2/utils"
+	"github.com/pkg/errors"
+)
+
+// ErrInteractionRequired is returned (wrapped) by NewCLIClient in non-interactive mode when credentials are missing.
+var ErrInteractionRequired = errors.New("credentials are missing, and prompting is disabled in non-interactive mode")
+
+// HelperCredentials are the credentials returned by a credential helper.
+type HelperCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// RunCredentialHelper queries the credential @helper for the credentials of @user (may be empty)
+// at the API endpoint @apiURL, on behalf of @profile. Returns empty credentials if the helper has none.
+func RunCredentialHelper(helper, apiURL, user, profile string) (*HelperCredentials, error) {
+	var stdin, stdout bytes.Buffer
+	var creds = new(HelperCredentials)
+
+	if u, err := url.Parse(apiURL); err == nil && u.Host != "" {
+		fmt.Fprintf(&stdin, "protocol=%s\nhost=%s\n", u.Scheme, u.Host)
+	}
+	fmt.Fprintf(&stdin, "endpoint=%s\n", apiURL)
+	if user != "" {
+		fmt.Fprintf(&stdin, "user=%s\n", user)
+	}
+	if profile != "" {
+		fmt.Fprintf(&stdin, "profile=%s\n", profile)
+	}
+	stdin.WriteString("\n")
+
+	if err := runShell(helper+" get", &stdin, &stdout); err != nil {
+		return nil, errors.Errorf("credential helper: %s", err)
+	} else if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return creds, nil
+	} else if err = json.Unmarshal(stdout.Bytes(), creds); err != nil {
+		return nil, errors.Errorf("credential helper %q returned invalid response: %s", helper, err)
+	}
+	return creds, nil
+}
+
+// resolveCredentials completes the username and password of @conf for the API endpoint @apiURL, using
+// 1. the values already set in @conf (e.g. via command-line flags),
+// 2. environment variables (CLC_USER, or else CLC_USERNAME, and CLC_PASSWORD),
+// 3. the credential helper (@conf.CredentialHelper, or else CLC_CREDENTIAL_HELPER),
+// 4. prompting for the values, unless in non-interactive mode (@conf.NonInteractive or CLC_NONINTERACTIVE).
+func resolveCredentials(conf *ClientConfig, apiURL string) error {
+	if conf.Username == "" {
+		conf.Username = utils.EnvUsername()
+	}
+	if conf.Password == "" {
+		conf.Password = os.Getenv("CLC_PASSWORD")
+	}
+
+	if helper := conf.CredentialHelper; conf.Username == "" || conf.Password == "" {
+		if helper == "" {
+			helper = os.Getenv("CLC_CREDENTIAL_HELPER")
+		}
+		if helper != "" {
+			creds, err := RunCredentialHelper(helper, apiURL, conf.Username, conf.Profile)
+			if err != nil {
+				return err
+			} else if conf.Username == "" {
+				conf.Username = creds.Username
+			}
+			// Only use a password that belongs to the selected user.
+			if conf.Password == "" && (creds.Username == "" || strings.EqualFold(creds.Username, conf.Username)) {
+				conf.Password = creds.Password
+			}
+		}
+	}
+
+	if conf.Username == "" || conf.Password == "" {
+		if conf.NonInteractive || os.Getenv("CLC_NONINTERACTIVE") != "" {
+			if conf.Username == "" {
+				return errors.Wrap(ErrInteractionRequired, "no username given (use flags, CLC_USER, or a credential helper)")
+			}
+			return errors.Wrapf(ErrInteractionRequired, "no password for %s (use flags, CLC_PASSWORD, or a credential helper)",
+				conf.Username)
+		}
+		conf.Username, conf.Password = utils.ResolveUserAndPass(conf.Username, conf.Password)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package clcv2
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// The password of a credential helper is used only if it belongs to the selected user (ignoring case).
+func TestCredentialHelperUser(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+	for _, env := range []string{"CLC_USER", "CLC_USERNAME", "CLC_PASSWORD"} {
+		defer os.Setenv(env, os.Getenv(env))
+		os.Unsetenv(env)
+	}
+
+	const helper = `echo '{"username": "JDoe", "password": "s3cret"}' #`
+
+	for _, tc := range []struct {
+		user, password string
+	}{
+		{"", "s3cret"},
+		{"jdoe", "s3cret"},
+		{"JDOE", "s3cret"},
+		{"other", ""},
+	} {
+		var conf = &ClientConfig{Username: tc.user, CredentialHelper: helper, NonInteractive: true}
+
+		err := resolveCredentials(conf, DefaultEndpoints.API)
+		if tc.password == "" {
+			if errors.Cause(err) != ErrInteractionRequired {
+				t.Errorf("%q: expected the password of another user to be ignored, got %v", tc.user, err)
+			}
+		} else if err != nil {
+			t.Errorf("%q: resolveCredentials failed: %s", tc.user, err)
+		} else if conf.Password != tc.password {
+			t.Errorf("%q: expected password %q, got %q", tc.user, tc.password, conf.Password)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package clcv2
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 /* Custom field as it appears embedded in other structures. */
 type CustomField struct {
@@ -41,7 +44,12 @@ type AccountCustomField struct {
 
 // Retrieve the custom field(s) defined for a given account.
 func (c *Client) GetCustomFields() (res []AccountCustomField, err error) {
-	err = c.getCLCResponse("GET", fmt.Sprintf("/v2/accounts/%s/customFields", c.credentials.AccountAlias), nil, &res)
+	return c.GetCustomFieldsContext(c.ctx)
+}
+
+// GetCustomFieldsContext is like GetCustomFields, using @ctx for cancellation.
+func (c *Client) GetCustomFieldsContext(ctx context.Context) (res []AccountCustomField, err error) {
+	err = c.getCLCResponse(ctx, "GET", fmt.Sprintf("/v2/accounts/%s/customFields", c.AccountAlias), nil, &res)
 	return res, err
 }
 
@@ -1,6 +1,7 @@
 package clcv2
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -18,7 +19,12 @@ type DataCenter struct {
 
 // Get the list of data centers that a given account has access to.
 func (c *Client) GetLocations() (loc []DataCenter, err error) {
-	err = c.getCLCResponse("GET", "/v2/datacenters/"+c.AccountAlias, nil, &loc)
+	return c.GetLocationsContext(c.ctx)
+}
+
+// GetLocationsContext is like GetLocations, using @ctx for cancellation.
+func (c *Client) GetLocationsContext(ctx context.Context) (loc []DataCenter, err error) {
+	err = c.getCLCResponse(ctx, "GET", "/v2/datacenters/"+c.AccountAlias, nil, &loc)
 	return loc, err
 }
 
@@ -26,8 +32,13 @@ func (c *Client) GetLocations() (loc []DataCenter, err error) {
 // @location:   location alias of data centre to query
 // @groupLinks: whether to include 'group' type of links
 func (c *Client) GetDatacenter(location string, groupLinks bool) (res DataCenter, err error) {
+	return c.GetDatacenterContext(c.ctx, location, groupLinks)
+}
+
+// GetDatacenterContext is like GetDatacenter, using @ctx for cancellation.
+func (c *Client) GetDatacenterContext(ctx context.Context, location string, groupLinks bool) (res DataCenter, err error) {
 	path := fmt.Sprintf("/v2/datacenters/%s/%s?groupLinks=%t", c.AccountAlias, location, groupLinks)
-	err = c.getCLCResponse("GET", path, nil, &res)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
@@ -46,9 +57,14 @@ type ComputeLimits struct {
 
 // Get the compute limits for the given data centre.
 func (c *Client) GetDatacenterComputeLimits(location string) (*ComputeLimits, error) {
-	path := fmt.Sprintf("/v2/datacenters/%s/%s/computeLimits", c.credentials.AccountAlias, location)
+	return c.GetDatacenterComputeLimitsContext(c.ctx, location)
+}
+
+// GetDatacenterComputeLimitsContext is like GetDatacenterComputeLimits, using @ctx for cancellation.
+func (c *Client) GetDatacenterComputeLimitsContext(ctx context.Context, location string) (*ComputeLimits, error) {
+	path := fmt.Sprintf("/v2/datacenters/%s/%s/computeLimits", c.AccountAlias, location)
 	res := new(ComputeLimits)
-	return res, c.getCLCResponse("GET", path, nil, res)
+	return res, c.getCLCResponse(ctx, "GET", path, nil, res)
 }
 
 // NetLimits represents the maximum number of networks allowed in a data centre.
@@ -58,9 +74,14 @@ type NetLimits struct {
 
 // Get the networking limits for the given data centre.
 func (c *Client) GetDatacenterNetworkLimits(location string) (*NetLimits, error) {
-	path := fmt.Sprintf("/v2/datacenters/%s/%s/networkLimits", c.credentials.AccountAlias, location)
+	return c.GetDatacenterNetworkLimitsContext(c.ctx, location)
+}
+
+// GetDatacenterNetworkLimitsContext is like GetDatacenterNetworkLimits, using @ctx for cancellation.
+func (c *Client) GetDatacenterNetworkLimitsContext(ctx context.Context, location string) (*NetLimits, error) {
+	path := fmt.Sprintf("/v2/datacenters/%s/%s/networkLimits", c.AccountAlias, location)
 	res := new(NetLimits)
-	return res, c.getCLCResponse("GET", path, nil, res)
+	return res, c.getCLCResponse(ctx, "GET", path, nil, res)
 }
 
 /*
@@ -139,8 +160,13 @@ type Template struct {
 // and shared load balancer configuration are available.
 // @location:   location alias of data centre to query
 func (c *Client) GetDeploymentCapabilities(location string) (res DeploymentCapabilities, err error) {
-	path := fmt.Sprintf("/v2/datacenters/%s/%s/deploymentCapabilities", c.credentials.AccountAlias, location)
-	err = c.getCLCResponse("GET", path, nil, &res)
+	return c.GetDeploymentCapabilitiesContext(c.ctx, location)
+}
+
+// GetDeploymentCapabilitiesContext is like GetDeploymentCapabilities, using @ctx for cancellation.
+func (c *Client) GetDeploymentCapabilitiesContext(ctx context.Context, location string) (res DeploymentCapabilities, err error) {
+	path := fmt.Sprintf("/v2/datacenters/%s/%s/deploymentCapabilities", c.AccountAlias, location)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
@@ -208,7 +234,12 @@ type BareMetalCapabilities struct {
 // including the list of configuration types and the list of supported operating systems.
 // @location:   location alias of data centre to query
 func (c *Client) GetBareMetalCapabilities(location string) (res BareMetalCapabilities, err error) {
-	path := fmt.Sprintf("/v2/datacenters/%s/%s/bareMetalCapabilities", c.credentials.AccountAlias, location)
-	err = c.getCLCResponse("GET", path, nil, &res)
+	return c.GetBareMetalCapabilitiesContext(c.ctx, location)
+}
+
+// GetBareMetalCapabilitiesContext is like GetBareMetalCapabilities, using @ctx for cancellation.
+func (c *Client) GetBareMetalCapabilitiesContext(ctx context.Context, location string) (res BareMetalCapabilities, err error) {
+	path := fmt.Sprintf("/v2/datacenters/%s/%s/bareMetalCapabilities", c.AccountAlias, location)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
@@ -42,7 +42,7 @@ func init() {
 					}
 
 					if reqID != "" {
-						client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+						pollStatus(reqID, "archive", name, func(s clcv2.QueueStatus) {
 							log.Printf("Archiving %s: %s", name, s)
 						})
 					}
@@ -85,7 +85,7 @@ func init() {
 			}
 
 			if reqID != "" {
-				client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+				pollStatus(reqID, "restore", args[0], func(s clcv2.QueueStatus) {
 					log.Printf("Restoring %s into %s: %s", args[0], args[1], s)
 				})
 			}
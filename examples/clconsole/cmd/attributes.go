@@ -37,7 +37,7 @@ func init() {
 			} else {
 				log.Printf("%s changing #CPUs to %s: %s", args[0], args[1], reqID)
 
-				client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+				pollStatus(reqID, "set CPUs", args[0], func(s clcv2.QueueStatus) {
 					log.Printf("%s changing #CPUs to %s: %s", args[0], args[1], s)
 				})
 			}
@@ -63,7 +63,7 @@ func init() {
 			} else {
 				log.Printf("%s changing memory to %s GB: %s", args[0], args[1], reqID)
 
-				client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+				pollStatus(reqID, "set memory", args[0], func(s clcv2.QueueStatus) {
 					log.Printf("%s changing memory to %s GB: %s", args[0], args[1], s)
 				})
 			}
@@ -131,7 +131,7 @@ func init() {
 			if err != nil {
 				exit.Fatalf("failed to change the password on %q: %s", args[0], err)
 			} else {
-				client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+				pollStatus(reqID, "change password", args[0], func(s clcv2.QueueStatus) {
 					log.Printf("Updating %s password: %s", args[0], s)
 				})
 			}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -233,9 +234,9 @@ func extractServerNames(args []string) (ret []string, err error) {
 
 // serverCmd wraps common server tasks
 // @action: name of the command
-// @hdlr:   server action, taking a server ID as argument and returning the status ID, or an error
+// @hdlr:   server action, taking a server ID as argument and returning the Job of the action, or an error
 // @args:   command arguments (server or group names) to loop over
-func serverCmd(action string, hdlr func(string) (string, error), args []string) error {
+func serverCmd(action string, hdlr func(string) (clcv2.Job, error), args []string) error {
 	var eg errgroup.Group
 
 	servers, err := extractServerNames(args)
@@ -244,21 +245,103 @@ func serverCmd(action string, hdlr func(string) (string, error), args []string)
 		return err
 	}
 
-	for _, name := range servers {
-		name := name
+	var jobs = make([]clcv2.Job, len(servers))
+	for i, name := range servers {
+		i, name := i, name
 		eg.Go(func() error {
-			reqID, err := hdlr(name)
+			job, err := hdlr(name)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "ERROR %s %s: %s\n", name, action, err)
 			} else {
-				log.Printf("%s %s: %s", name, action, reqID)
-
-				client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
-					log.Printf("%s %s: %s", name, action, s)
-				})
+				log.Printf("%s %s: %s", name, action, job.ID())
+				jobs[i] = job
 			}
 			return err
 		})
 	}
-	return eg.Wait()
+	err = eg.Wait()
+	if werr := awaitServerJobs(action, servers, jobs); err == nil {
+		err = werr
+	}
+	return err
+}
+
+// serverBatchCmd is like serverCmd, but submits the action for all servers via batch requests.
+// @action: name of the command
+// @batch:  batch server action, taking server IDs and returning one result per server
+// @args:   command arguments (server or group names)
+func serverBatchCmd(action string, batch func([]string) ([]clcv2.BatchResult, error), args []string) error {
+	servers, err := extractServerNames(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		return err
+	}
+
+	res, err := batch(servers)
+	var jobs = make([]clcv2.Job, len(res))
+	for i, r := range res {
+		if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR %s: %s\n", action, r.Err) // r.Err names the server
+		} else {
+			log.Printf("%s %s: %s", r.Server, action, r.StatusID)
+			jobs[i] = client.StatusJob(r.StatusID)
+		}
+	}
+	if werr := awaitServerJobs(action, servers, jobs); err == nil {
+		err = werr
+	}
+	return err
+}
+
+// awaitServerJobs polls the non-nil @jobs of @servers together (rather than one polling loop
+// per server), logging progress. If polling is disabled via @intvl, each job is polled once.
+func awaitServerJobs(action string, servers []string, jobs []clcv2.Job) error {
+	var names []string
+	var pending []clcv2.Job
+
+	for i, j := range jobs {
+		if j != nil {
+			pending, names = append(pending, j), append(names, servers[i])
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	} else if intvl == 0 {
+		var eg errgroup.Group
+
+		for i, j := range pending {
+			i, j := i, j
+			eg.Go(func() error {
+				status, err := j.Poll(client.Context())
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "ERROR %s %s: %s\n", names[i], action, err)
+				} else {
+					log.Printf("%s %s: %s", names[i], action, status)
+				}
+				return err
+			})
+		}
+		return eg.Wait()
+	}
+
+	start := time.Now()
+	sum := clcv2.AwaitAll(client.Context(), pending, &clcv2.AwaitOptions{
+		MinInterval: intvl,
+		OnEvent: func(e clcv2.JobEvent) {
+			log.Printf("%s %s: %s (%s)", names[e.Index], action, e.Status, e.Elapsed.Round(time.Second))
+			if e.Status == clcv2.Succeeded || e.Status == clcv2.Failed {
+				notifyJob(e.Job.ID(), action, names[e.Index], e.Status, start, nil)
+			}
+		},
+	})
+	for i, r := range sum.Results {
+		if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR %s %s: %s\n", names[i], action, r.Err)
+			notifyJob(r.Job.ID(), action, names[i], r.Status, start, r.Err)
+		}
+	}
+	if len(pending) > 1 {
+		log.Printf("%s: %s", action, sum)
+	}
+	return sum.Err()
 }
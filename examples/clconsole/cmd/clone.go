@@ -63,11 +63,10 @@ var Clone = &cobra.Command{
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		var (
-			source      = args[0] // source server
-			dest        string    // destination folder
-			wasStopped  bool
-			maxAttempts = 1
-			url, reqID  string
+			source = args[0] // source server
+			dest   string    // destination folder
+			reqID  string    // queue status ID of the clone
+			start  = time.Now()
 		)
 
 		// First get the details of the source server
@@ -77,23 +76,6 @@ var Clone = &cobra.Command{
 			exit.Fatalf("failed to list details of source server %q: %s", source, err)
 		}
 
-		if wasStopped = src.Details.PowerState == "stopped"; wasStopped {
-			// The source server must be powered on
-			log.Printf("%s is powered-off - powering on ...", src.Name)
-			reqID, err := client.PowerOnServer(src.Name)
-			if err != nil {
-				exit.Fatalf("failed to power on source server %s: %s", src.Name, err)
-			}
-			log.Printf("Waiting for %s to power on (status ID: %s) ...", src.Name, reqID)
-			if _, err = client.AwaitCompletion(reqID); err != nil {
-				exit.Fatalf("failed to await completion of %s: %s", reqID, err)
-			}
-			// When the server is being powered on, it can take up to 5 minutes until
-			// the backend is able to clone it; it requires the server to be fully booted.
-			maxAttempts = 9
-			time.Sleep(1 * time.Minute)
-		}
-
 		// We need the credentials, too
 		log.Printf("Obtaining %s credentials ...", src.Name)
 		credentials, err := client.GetServerCredentials(src.Name)
@@ -188,19 +170,14 @@ var Clone = &cobra.Command{
 				// is prevented from querying details of the parent account, due to insufficient
 				// permission.
 				if parentAccount := client.RegisteredAccountAlias(); client.AccountAlias != parentAccount {
-					var savedAlias = client.AccountAlias
-
-					log.Printf("Network ID not visible under %q account - trying %q instead ...", savedAlias, parentAccount)
-					client.AccountAlias = parentAccount
-					if nets, err = client.GetServerNets(src); err != nil {
+					log.Printf("Network ID not visible under %q account - trying %q instead ...", client.AccountAlias, parentAccount)
+					if nets, err = client.WithAccount(parentAccount).GetServerNets(src); err != nil {
 						exit.Fatalf("failed to query networks of %s using %q account: %s", src.Name, parentAccount, err)
 					}
-					// Restore Account Alias for remainder of program
-					client.AccountAlias = savedAlias
 				}
 				if len(nets) == 0 {
 					log.Printf("Unable to determine Network ID - querying %s deployable networks ...", src.LocationId)
-					capa, err := client.GetDeploymentCapabilities(src.LocationId)
+					capa, err := client.WithAccount(client.RegisteredAccountAlias()).GetDeploymentCapabilities(src.LocationId)
 					if err != nil {
 						exit.Fatalf("failed to determine %s Deployment Capabilities: %s", src.LocationId, err)
 					}
@@ -241,31 +218,22 @@ var Clone = &cobra.Command{
 			req.NetworkId = cloneFlags.net
 		}
 
-		for i := 1; ; i++ {
-			url, reqID, err = client.CreateServer(&req)
-			if err == nil || i == maxAttempts || strings.Index(err.Error(), "body.sourceServerId") > 0 {
-				break
-			}
-			log.Printf("attempt %d/%d failed (%s) - retrying ...", i, maxAttempts, strings.TrimSpace(err.Error()))
-			time.Sleep(1 * time.Minute)
+		if src.Details.PowerState == "stopped" {
+			// The source server must be powered on, and fully booted, which can take several minutes.
+			log.Printf("%s is powered-off - powering on before cloning ...", src.Name)
 		}
-		if err != nil {
-			exit.Fatalf("failed to create server: %s", err)
-		}
-		log.Printf("Cloning %s: %s", src.Name, reqID)
 
-		status, err := client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+		// Powers on the source if necessary, and waits until the new server is active and has an IP address.
+		server, err := client.CloneServerAndWaitFn(client.Context(), &req, func(j clcv2.Job, s clcv2.QueueStatus) {
+			if reqID == "" {
+				reqID = j.ID()
+				log.Printf("Cloning %s: %s", src.Name, reqID)
+			}
 			log.Printf("Cloning %s => %s/%s..%s..: %s", source, dest, src.LocationId, req.Name, s)
 		})
+		notifyJob(reqID, "clone", source, provisionStatus(err), start, err)
 		if err != nil {
-			exit.Fatalf("failed to poll %s status: %s", reqID, err)
-		}
-
-		server, err := client.GetServerByURI(url)
-		if err != nil {
-			log.Fatalf("failed to query server details at %s: %s", url, err)
-		} else if status == clcv2.Failed {
-			exit.Fatalf("failed to clone %s (will show up as 'under construction')", server.Name)
+			exit.Fatalf("failed to clone %s: %s", src.Name, err)
 		}
 		log.Printf("New server %q, with password %q:", server.Name, credentials.Password)
 		showServer(client, server)
@@ -151,21 +151,24 @@ var Create = &cobra.Command{
 			*req.Ttl = time.Now().Add(createFlags.ttl)
 		}
 
-		// The CreateServer request resolves the server name at the end.
-		// This second call can fail at the remote end; it does not mean that
-		// the server has not been created yet.
-		url, reqID, err := client.CreateServer(&req)
+		// Waits until the server is active and has an IP address, since resolving the
+		// new server can fail at the remote end right after the queue job completes.
+		var reqID string
+		var start = time.Now()
+
+		server, err := client.CreateServerAndWaitFn(client.Context(), &req, func(j clcv2.Job, s clcv2.QueueStatus) {
+			if reqID == "" {
+				reqID = j.ID()
+				log.Printf("Status Id: %s", reqID)
+			}
+			log.Printf("%s: %s", reqID, s)
+		})
+		notifyJob(reqID, "create", req.Name, provisionStatus(err), start, err)
 		if err != nil {
 			log.Fatalf("failed to create server: %s", err)
 		}
-		log.Printf("Status Id: %s", reqID)
-		client.PollStatus(reqID, 5*time.Second)
 
 		// Print details after job completes
-		server, err := client.GetServerByURI(url)
-		if err != nil {
-			log.Fatalf("failed to query server details at %s: %s", url, err)
-		}
 		showServer(client, server)
 	},
 }
@@ -115,7 +115,7 @@ func deleteSingleServer(eg *errgroup.Group, srv string) {
 			fmt.Fprintf(os.Stderr, "ERROR deleting server %s: %s\n", srv, err)
 		} else if reqID != "" {
 			log.Printf("Deleting %s: %s", srv, reqID)
-			client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+			pollStatus(reqID, "delete", srv, func(s clcv2.QueueStatus) {
 				log.Printf("Deleting %s: %s", srv, s)
 			})
 		} else {
@@ -133,7 +133,7 @@ func deleteSingleGroup(eg *errgroup.Group, grp *clcv2.GroupInfo) {
 			fmt.Fprintf(os.Stderr, "ERROR deleting group %s (%s): %s\n", grp.Name, grp.ID, err)
 		} else if reqID != "" {
 			log.Printf("Deleting %s (%s): %s", grp.Name, grp.ID, reqID)
-			client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+			pollStatus(reqID, "delete", grp.Name, func(s clcv2.QueueStatus) {
 				log.Printf("Deleting %s (%s): %s", grp.Name, grp.ID, s)
 			})
 		}
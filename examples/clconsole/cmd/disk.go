@@ -68,7 +68,7 @@ var diskAdd = &cobra.Command{
 		}
 
 		log.Printf("%s adding %d GB disk: %s", args[0], diskGB, reqID)
-		client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+		pollStatus(reqID, "add disk", args[0], func(s clcv2.QueueStatus) {
 			log.Printf("%s adding %d GB disk: %s", args[0], diskGB, s)
 		})
 		return nil
@@ -131,7 +131,7 @@ var diskGrow = &cobra.Command{
 		}
 
 		log.Printf("%s resizing disk %s to %dGB: %s", args[0], id, diskGB, reqID)
-		client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+		pollStatus(reqID, "resize disk", args[0], func(s clcv2.QueueStatus) {
 			log.Printf("%s resizing disk %s to %dGB: %s", args[0], id, diskGB, s)
 		})
 		return nil
@@ -235,7 +235,7 @@ var diskRemove = &cobra.Command{
 		}
 
 		log.Printf("%s deleting disk %s: %s", args[0], ids, reqID)
-		client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+		pollStatus(reqID, "delete disk", args[0], func(s clcv2.QueueStatus) {
 			log.Printf("%s deleting disk %s: %s", args[0], ids, s)
 		})
 		return nil
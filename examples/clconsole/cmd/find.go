@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Flags
+var findFlags struct {
+	Where string // Filter expression (see clcv2.ParseServerFilter)
+}
+
+func init() {
+	Find.Flags().StringVar(&findFlags.Where, "where", "", "Filter expression the servers must match (default: all servers)")
+
+	Root.AddCommand(Find)
+}
+
+var Find = &cobra.Command{
+	Use:     "find  [location|all [location]...]",
+	Aliases: []string{"search", "query"},
+	Short:   "Find servers matching a filter",
+	Long: `List the servers in the given data centre(s) (default: -l location, 'all' for all) that match the --where
+filter expression, e.g.
+
+    powerState=stopped and cpu>=4 and os~"ubuntu" and customField.owner=ops
+
+Comparisons '<field> <op> <value>' are combined via 'and', 'or', 'not', and parentheses.
+Operators: = != < <= > >= ~ (case-insensitive regexp match) !~
+
+Fields:   name, description, status, powerState, os, type, storageType, hostname, location,
+          group (name or path), ip, publicIp, createdBy, modifiedBy, customField.<name>,
+          cpu, memory (GB), storage (GB), disks,
+          maintenance, snapshot, template (true/false, or on their own meaning true),
+          created, modified (2006-01-02 or RFC3339)`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return findServers(findFlags.Where, args)
+	},
+}
+
+// Data centre location aliases, e.g. WA1
+var locationRegexp = regexp.MustCompile(`^[[:alpha:]]{2}\d$`)
+
+// findServers lists the servers in the data centres @args that match the filter expression @where
+func findServers(where string, args []string) error {
+	var filter *clcv2.ServerFilter
+	var locations []string
+	var err error
+
+	if where != "" {
+		if filter, err = clcv2.ParseServerFilter(where); err != nil {
+			return err
+		}
+	}
+
+	for _, arg := range args {
+		if strings.EqualFold(arg, "all") {
+			locations = nil
+			break
+		} else if !locationRegexp.MatchString(arg) {
+			return errors.Errorf("%q is not a data centre - filters apply to whole data centres", arg)
+		}
+		locations = append(locations, arg)
+	}
+	if len(args) == 0 {
+		locations = []string{conf.Location}
+	}
+
+	found, err := client.FindServers(client.Context(), locations, filter)
+	if err != nil {
+		return errors.Errorf("failed to search servers: %s", err)
+	} else if len(found) == 0 {
+		fmt.Fprintln(os.Stderr, "No matching servers found.")
+		return nil
+	}
+
+	var rows = make([]serverRow, len(found))
+	for i, s := range found {
+		rows[i] = serverRow{server: s.Server, group: s.GroupPath}
+	}
+	printServers(rows)
+	return nil
+}
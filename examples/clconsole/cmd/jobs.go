@@ -0,0 +1,131 @@
+package cmd
+
+/*
+ * Local journal of queued operations
+ */
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/grrtrr/exit"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// jobsFlags groups the flags to select journal entries
+var jobsFlags struct {
+	since   time.Duration // how far to look back
+	pending bool          // whether to only show pending jobs
+	failed  bool          // whether to only show failed jobs
+}
+
+func init() {
+	var (
+		jobs = &cobra.Command{
+			Use:         "jobs",
+			Short:       "List recently queued operations",
+			Long:        "List the operations recorded in the local job journal in CLC_HOME, along with their final status",
+			Annotations: map[string]string{noLogin: "true"},
+			Run: func(cmd *cobra.Command, args []string) {
+				var table = tablewriter.NewWriter(os.Stdout)
+				var entries = journalEntries(func(e clcv2.JournalEntry) bool {
+					return (!jobsFlags.pending || e.Pending()) && (!jobsFlags.failed || e.Status == clcv2.Failed)
+				})
+
+				if len(entries) == 0 {
+					fmt.Printf("No matching jobs within the last %s.\n", jobsFlags.since)
+					return
+				}
+
+				table.SetAutoFormatHeaders(false)
+				table.SetAlignment(tablewriter.ALIGN_LEFT)
+				table.SetAutoWrapText(false)
+
+				table.SetHeader([]string{"Submitted", "Status ID", "Account", "Operation", "Target", "Status", "Took"})
+				for _, e := range entries {
+					var took string
+
+					if e.Completed != nil {
+						took = e.Completed.Sub(e.Submitted).Round(time.Second).String()
+					}
+					table.Append([]string{
+						e.Submitted.Local().Format("Jan _2 15:04:05"), e.StatusID, e.Account, e.Operation, e.Target, string(e.Status), took,
+					})
+				}
+				table.Render()
+			},
+		}
+
+		waitJobs = &cobra.Command{
+			Use:     "wait",
+			Aliases: []string{"resume"},
+			Short:   "Resume waiting on pending jobs",
+			Long:    "Poll the pending jobs of the current account in the job journal until they complete",
+			Run: func(cmd *cobra.Command, args []string) {
+				var pending, names, ops, targets []string
+				var jobs []clcv2.Job
+
+				for _, e := range journalEntries(func(e clcv2.JournalEntry) bool { return e.Pending() }) {
+					if !strings.EqualFold(e.Account, client.AccountAlias) {
+						log.Printf("%s: skipping job of account %s", e.StatusID, e.Account)
+						continue
+					}
+					pending = append(pending, e.StatusID)
+					names = append(names, fmt.Sprintf("%s %s", e.Target, e.Operation))
+					ops, targets = append(ops, e.Operation), append(targets, e.Target)
+					jobs = append(jobs, client.StatusJob(e.StatusID))
+				}
+				if len(jobs) == 0 {
+					fmt.Println("No pending jobs.")
+					return
+				}
+
+				start := time.Now()
+				sum := clcv2.AwaitAll(client.Context(), jobs, &clcv2.AwaitOptions{
+					MinInterval: intvl,
+					OnEvent: func(e clcv2.JobEvent) {
+						log.Printf("%s (%s): %s", pending[e.Index], strings.TrimSpace(names[e.Index]), e.Status)
+						if e.Status == clcv2.Succeeded || e.Status == clcv2.Failed {
+							notifyJob(pending[e.Index], ops[e.Index], targets[e.Index], e.Status, start, nil)
+						}
+					},
+				})
+				for i, r := range sum.Results {
+					if r.Err != nil {
+						fmt.Fprintf(os.Stderr, "ERROR %s: %s\n", pending[i], r.Err)
+						notifyJob(pending[i], ops[i], targets[i], r.Status, start, r.Err)
+					}
+				}
+				log.Printf("%d jobs: %s", len(jobs), sum)
+				if err := sum.Err(); err != nil {
+					exit.Fatal(err)
+				}
+			},
+		}
+	)
+
+	jobs.PersistentFlags().DurationVar(&jobsFlags.since, "since", 24*time.Hour, "Only consider jobs submitted within this period")
+	jobs.Flags().BoolVar(&jobsFlags.pending, "pending", false, "Only list jobs that have not completed")
+	jobs.Flags().BoolVar(&jobsFlags.failed, "failed", false, "Only list failed jobs")
+
+	jobs.AddCommand(waitJobs)
+	Root.AddCommand(jobs)
+}
+
+// journalEntries returns the journal entries within the --since period that satisfy @match, exiting on error.
+func journalEntries(match func(clcv2.JournalEntry) bool) (res []clcv2.JournalEntry) {
+	entries, err := clcv2.OpenJournal().Entries()
+	if err != nil {
+		exit.Fatalf("failed to load job journal: %s", err)
+	}
+	for _, e := range entries {
+		if time.Since(e.Submitted) <= jobsFlags.since && match(e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/grrtrr/exit"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Root.AddCommand(&cobra.Command{
+		Use:         "migrate-config",
+		Short:       "Move cleartext passwords from the configuration file into the secret backend",
+		Annotations: map[string]string{noLogin: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+			if migrated, err := clcv2.MigrateClientConfig(); err != nil {
+				exit.Fatalf("failed to migrate configuration: %s", err)
+			} else if migrated {
+				fmt.Println("Password moved into the secret backend.")
+			} else {
+				fmt.Println("Nothing to migrate.")
+			}
+		},
+	})
+}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/grrtrr/clcv2"
 	"github.com/grrtrr/exit"
@@ -30,6 +32,35 @@ func init() {
 			}
 		},
 	})
+
+	Root.AddCommand(&cobra.Command{
+		Use:   "claim-network  [location]",
+		Short: "Claim a new network",
+		Long:  "Claim a new network in the default data centre, or in @location if present.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) > 0 {
+				conf.Location = args[0]
+			}
+			var location = strings.ToUpper(conf.Location)
+			var start = time.Now()
+
+			claim, err := client.ClaimNetworkJob(location)
+			if err != nil {
+				exit.Fatalf("failed to claim a new network in %s: %s", location, err)
+			}
+			log.Printf("Claiming new network in %s: %s", location, claim.ID())
+
+			claim.OnProgress(func(_ clcv2.Job, s clcv2.QueueStatus) {
+				log.Printf("Claiming new network in %s: %s", location, s)
+			})
+			err = claim.Wait(client.Context())
+			notifyJob(claim.ID(), "claim-network", location, claim.Status(), start, err)
+			if err != nil {
+				exit.Fatalf("failed to claim a new network in %s: %s", location, err)
+			}
+			log.Printf("Successfully claimed new network %s in %s", claim.NetworkID(), location)
+		},
+	})
 }
 
 // showNetworks shows networks visible to @account in data centre location @location.
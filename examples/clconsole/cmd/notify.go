@@ -0,0 +1,73 @@
+package cmd
+
+/*
+ * Completion notifications (--notify)
+ */
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/grrtrr/exit"
+	"github.com/pkg/errors"
+)
+
+var (
+	notifySpecs []string       // --notify arguments
+	notifier    clcv2.Notifier // parsed @notifySpecs, nil if none
+)
+
+// initNotifier parses the --notify arguments, exiting on error.
+func initNotifier() {
+	var ns clcv2.Notifiers
+
+	for _, spec := range notifySpecs {
+		n, err := clcv2.ParseNotifier(spec)
+		if err != nil {
+			exit.Fatalf("--notify: %s", err)
+		}
+		ns = append(ns, n)
+	}
+	if len(ns) > 0 {
+		notifier = ns
+	}
+}
+
+// notifyJob sends the completion notification of job @id, which ended with @status (or error @err), if requested.
+// @op:     description of the operation
+// @target: server/group/location the operation applies to
+// @start:  time the operation started
+func notifyJob(id, op, target string, status clcv2.QueueStatus, start time.Time, err error) {
+	if notifier == nil {
+		return
+	}
+	n := clcv2.NewNotification(id, op, target, status, time.Since(start), err)
+	if err := notifier.Notify(client.Context(), n); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR %s notification: %s\n", id, err)
+	}
+}
+
+// pollStatus is like client.PollStatusFn(@reqID, intvl, @cb), and sends a completion notification
+// for @op on @target once the job has completed (or polling failed).
+func pollStatus(reqID, op, target string, cb func(clcv2.QueueStatus)) (clcv2.QueueStatus, error) {
+	var start = time.Now()
+
+	status, err := client.PollStatusFn(reqID, intvl, cb)
+	if err != nil || status == clcv2.Succeeded || status == clcv2.Failed {
+		notifyJob(reqID, op, target, status, start, err)
+	}
+	return status, err
+}
+
+// provisionStatus returns the QueueStatus to report for the result @err of CreateServerAndWait/CloneServerAndWait.
+func provisionStatus(err error) clcv2.QueueStatus {
+	if err == nil {
+		return clcv2.Succeeded
+	} else if pe, ok := err.(*clcv2.ProvisionError); ok && (pe.Phase == clcv2.PhaseResolve || pe.Phase == clcv2.PhaseActivate) {
+		return clcv2.Succeeded // the queue job completed, but the server is not fully provisioned
+	} else if errors.Is(err, clcv2.ErrJobFailed) {
+		return clcv2.Failed
+	}
+	return clcv2.Unknown
+}
@@ -19,11 +19,11 @@ func init() {
 		Long:  "Shutdown, power-off (if --hard is set), or pause (if --pause is set) server(s)",
 		Run: func(cmd *cobra.Command, args []string) {
 			if offFlags.pause {
-				serverCmd("pause", client.PauseServer, args)
+				serverBatchCmd("pause", client.PauseServers, args)
 			} else if offFlags.hard {
-				serverCmd("hard power-off", client.PowerOffServer, args)
+				serverBatchCmd("hard power-off", client.PowerOffServers, args)
 			} else {
-				serverCmd("shutdown", client.ShutdownServer, args)
+				serverBatchCmd("shutdown", client.ShutdownServers, args)
 			}
 		},
 	}
@@ -40,7 +40,7 @@ func init() {
 		Short:   "Pause server(s)",
 		Long:    "Pause (suspend) server(s); can be resumed via 'on'",
 		Run: func(cmd *cobra.Command, args []string) {
-			serverCmd("pause", client.PauseServer, args)
+			serverBatchCmd("pause", client.PauseServers, args)
 		}})
 
 	// Shutdown - separate (hidden) command for OS-level (soft) shutdown
@@ -51,7 +51,7 @@ func init() {
 		Short:   "Shutdown server(s)",
 		Long:    "Soft (OS-level) shutdown, followed by power-off",
 		Run: func(cmd *cobra.Command, args []string) {
-			serverCmd("shutdown", client.ShutdownServer, args)
+			serverBatchCmd("shutdown", client.ShutdownServers, args)
 		}})
 
 	// Power-on / un-suspend a server
@@ -61,6 +61,6 @@ func init() {
 		Short:   "Power on server(s)",
 		Long:    "Powers on server(s), or resume from paused state",
 		Run: func(cmd *cobra.Command, args []string) {
-			serverCmd("power-on", client.PowerOnServer, args)
+			serverBatchCmd("power-on", client.PowerOnServers, args)
 		}})
 }
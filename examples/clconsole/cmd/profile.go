@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/grrtrr/exit"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		profile = &cobra.Command{ // Top-level profile command
+			Use:     "profile",
+			Aliases: []string{"profiles"},
+			Short:   "Manage configuration profiles",
+			Long:    "List, add, remove, and select the named profiles of the configuration file",
+
+			Annotations: map[string]string{noLogin: "true"},
+		}
+
+		lsProfiles = &cobra.Command{
+			Use:         "ls",
+			Aliases:     []string{"list"},
+			Short:       "List configuration profiles",
+			Annotations: map[string]string{noLogin: "true"},
+			Run: func(cmd *cobra.Command, args []string) {
+				var f = loadConfigFile()
+				var table = tablewriter.NewWriter(os.Stdout)
+
+				if len(f.Profiles) == 0 {
+					fmt.Println("No profiles configured.")
+					return
+				}
+
+				table.SetAutoFormatHeaders(false)
+				table.SetAlignment(tablewriter.ALIGN_LEFT)
+				table.SetAutoWrapText(false)
+
+				table.SetHeader([]string{"Profile", "User", "Account", "Location", "API Endpoint"})
+				for _, name := range f.ProfileNames() {
+					var p, api = f.Profiles[name], ""
+
+					if name == f.ProfileName("") {
+						name += " *"
+					}
+					if p.Endpoints != nil {
+						api = p.Endpoints.API
+					}
+					table.Append([]string{name, p.Username, p.Account, p.Location, api})
+				}
+				table.Render()
+			},
+		}
+
+		addProfileFlags struct {
+			endpoints  clcv2.Endpoints // endpoint overrides
+			setDefault bool            // whether to make the new profile the default
+		}
+
+		addProfile = &cobra.Command{
+			Use:         "add  <name>",
+			Short:       "Add or update a configuration profile",
+			Long:        "Add profile @name, using the user, password, account, and location given via the global flags",
+			PreRunE:     checkArgs(1, "Need the name of the profile"),
+			Annotations: map[string]string{noLogin: "true"},
+			Run: func(cmd *cobra.Command, args []string) {
+				var f = loadConfigFile()
+				var p = &clcv2.ClientConfig{
+					Profile:  args[0],
+					Username: conf.Username,
+					Password: conf.Password,
+					Account:  conf.Account,
+					Location: conf.Location,
+				}
+
+				if p.Username == "" {
+					exit.Fatalf("profile %s needs a username (use --username)", args[0])
+				} else if addProfileFlags.endpoints != (clcv2.Endpoints{}) {
+					p.Endpoints = &addProfileFlags.endpoints
+				}
+				if err := f.SetProfile(p); err != nil {
+					exit.Fatalf("failed to add profile: %s", err)
+				} else if addProfileFlags.setDefault || len(f.Profiles) == 1 {
+					f.SetDefaultProfile(p.Profile)
+				}
+				saveConfigFile(f)
+			},
+		}
+
+		rmProfile = &cobra.Command{
+			Use:         "rm  <name>",
+			Aliases:     []string{"remove", "del"},
+			Short:       "Remove a configuration profile",
+			PreRunE:     checkArgs(1, "Need the name of the profile"),
+			Annotations: map[string]string{noLogin: "true"},
+			Run: func(cmd *cobra.Command, args []string) {
+				var f = loadConfigFile()
+
+				if err := f.RemoveProfile(args[0]); err != nil {
+					exit.Fatalf("failed to remove profile: %s", err)
+				}
+				saveConfigFile(f)
+			},
+		}
+
+		defaultProfile = &cobra.Command{
+			Use:         "default  <name>",
+			Aliases:     []string{"use", "switch"},
+			Short:       "Set the default configuration profile",
+			PreRunE:     checkArgs(1, "Need the name of the profile"),
+			Annotations: map[string]string{noLogin: "true"},
+			Run: func(cmd *cobra.Command, args []string) {
+				var f = loadConfigFile()
+
+				if err := f.SetDefaultProfile(args[0]); err != nil {
+					exit.Fatalf("failed to set default profile: %s", err)
+				}
+				saveConfigFile(f)
+			},
+		}
+	)
+
+	addProfile.Flags().StringVar(&addProfileFlags.endpoints.API, "api-url", "", "Override the main API endpoint")
+	addProfile.Flags().StringVar(&addProfileFlags.endpoints.LBaaS, "lbaas-url", "", "Override the LBaaS API endpoint")
+	addProfile.Flags().StringVar(&addProfileFlags.endpoints.SBS, "sbs-url", "", "Override the SBS API endpoint")
+	addProfile.Flags().BoolVar(&addProfileFlags.setDefault, "default", false, "Make this the default profile")
+
+	profile.AddCommand(lsProfiles, addProfile, rmProfile, defaultProfile)
+	Root.AddCommand(profile)
+}
+
+// loadConfigFile loads the configuration file, exiting on error.
+func loadConfigFile() *clcv2.ConfigFile {
+	f, err := clcv2.LoadConfigFile()
+	if err != nil {
+		exit.Fatalf("failed to load configuration: %s", err)
+	}
+	return f
+}
+
+// saveConfigFile saves @f, exiting on error.
+func saveConfigFile(f *clcv2.ConfigFile) {
+	if err := f.Save(); err != nil {
+		exit.Fatalf("failed to save configuration: %s", err)
+	}
+}
@@ -110,7 +110,7 @@ var (
 			} else {
 				log.Printf("%s add public IP: %s", args[0], reqID)
 
-				client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+				pollStatus(reqID, "add public IP", args[0], func(s clcv2.QueueStatus) {
 					log.Printf("%s add public IP: %s", args[0], s)
 				})
 			}
@@ -163,7 +163,7 @@ var (
 			} else {
 				log.Printf("%s modify public IP %s: %s", args[0], args[1], reqID)
 
-				client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+				pollStatus(reqID, "modify public IP", args[0], func(s clcv2.QueueStatus) {
 					log.Printf("%s modify public IP %s: %s", args[0], args[1], s)
 				})
 			}
@@ -182,7 +182,7 @@ var (
 			} else {
 				log.Printf("%s delete public IP %s: %s", args[0], args[1], reqID)
 
-				client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
+				pollStatus(reqID, "delete public IP", args[0], func(s clcv2.QueueStatus) {
 					log.Printf("%s delete public IP %s: %s", args[0], args[1], s)
 				})
 			}
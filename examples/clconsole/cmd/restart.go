@@ -15,9 +15,9 @@ func init() {
 		Long:  "Reboot (OS-level) or reset (if --hard) a server",
 		Run: func(cmd *cobra.Command, args []string) {
 			if restartFlags.hard {
-				serverCmd("reset", client.ResetServer, args)
+				serverBatchCmd("reset", client.ResetServers, args)
 			} else {
-				serverCmd("reboot", client.RebootServer, args)
+				serverBatchCmd("reboot", client.RebootServers, args)
 			}
 		},
 	}
@@ -31,7 +31,7 @@ func init() {
 		Short:  "Reset server(s)",
 		Long:   "Performs hard/forced power-cycle, like pressing the physical 'reset' button",
 		Run: func(cmd *cobra.Command, args []string) {
-			serverCmd("reset", client.ResetServer, args)
+			serverBatchCmd("reset", client.ResetServers, args)
 		}})
 
 	Root.AddCommand(&cobra.Command{
@@ -40,7 +40,7 @@ func init() {
 		Short:  "Reboot server(s)",
 		Long:   "Soft (OS-level) reboot",
 		Run: func(cmd *cobra.Command, args []string) {
-			serverCmd("reboot", client.RebootServer, args)
+			serverBatchCmd("reboot", client.RebootServers, args)
 		}})
 
 }
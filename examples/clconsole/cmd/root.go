@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"path"
 	"time"
@@ -22,14 +23,29 @@ var (
 	debug   bool          // enable debug mode
 	intvl   time.Duration // poll interval for statistics updates
 	timeout time.Duration // client timeout
+
+	maxInFlight int     // maximum number of concurrent API requests
+	rateLimit   float64 // maximum sustained API request rate
+	useCache    bool    // whether to cache GET responses in CLC_HOME
 )
 
+// Annotation of commands that do not need an authenticated client
+const noLogin = "noLogin"
+
 // Exit handler: ensure that the updated configuration is saved on program termination
 func ExitHandler() {
-	client.SaveConfig()
+	if client != nil {
+		client.SaveConfig()
+		if rc := client.Cache(); rc != nil {
+			if err := rc.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to save response cache: %s\n", err)
+			}
+		}
+	}
 }
 
 func init() {
+	Root.PersistentFlags().StringVar(&conf.Profile, "profile", os.Getenv("CLC_PROFILE"), "Configuration profile to use (instead of default)")
 	Root.PersistentFlags().StringVarP(&conf.Username, "username", "u", os.Getenv("CLC_USER"), "CLC Login Username")
 	Root.PersistentFlags().StringVarP(&conf.Password, "password", "p", os.Getenv("CLC_PASSWORD"), "CLC Login Password")
 	// Account may be implicit or specified
@@ -45,22 +61,45 @@ func init() {
 		Root.PersistentFlags().StringVarP(&conf.Location, "location", "l", "", "CLC data centre to use (instead of default)")
 	}
 
+	Root.PersistentFlags().StringVar(&conf.CredentialHelper, "credential-helper", os.Getenv("CLC_CREDENTIAL_HELPER"), "Command that supplies missing credentials")
+	Root.PersistentFlags().BoolVar(&conf.NonInteractive, "non-interactive", os.Getenv("CLC_NONINTERACTIVE") != "", "Fail instead of prompting for missing credentials")
 	Root.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Produce debug output")
 	Root.PersistentFlags().DurationVarP(&intvl, "poll-interval", "i", 1*time.Second, "Poll interval for status updates (use 0 to disable)")
+	Root.PersistentFlags().StringArrayVar(&notifySpecs, "notify", nil, "Notify when queued jobs complete (URL, pipe:<path>, or cmd:<command>)")
 	Root.PersistentFlags().DurationVar(&timeout, "timeout", 180*time.Second, "Client default timeout")
+	Root.PersistentFlags().IntVar(&maxInFlight, "max-requests", 16, "Maximum number of concurrent API requests (use 0 to disable)")
+	Root.PersistentFlags().Float64Var(&rateLimit, "rate-limit", 0, "Maximum API requests per second (use 0 to disable)")
+	Root.PersistentFlags().BoolVar(&useCache, "cache", os.Getenv("CLC_CACHE") != "", "Cache API lookups in CLC_HOME for subsequent invocations")
 
 	// Initialize client needed by the sub-commands
 	cobra.OnInitialize(func() {
 		var err error
 
+		if cmd, _, err := Root.Find(os.Args[1:]); err == nil && cmd.Annotations[noLogin] != "" {
+			return
+		}
+
 		clcv2.Debug = debug
 		clcv2.ClientTimeout = timeout
+		initNotifier()
 
-		client, err = clcv2.NewCLIClient(&conf)
+		client, err = clcv2.NewCLIClient(&conf, clcv2.WithLimits(clcv2.FamilyAPI, clcv2.Limits{
+			Rate:        rateLimit,
+			Burst:       maxInFlight,
+			MaxInFlight: maxInFlight,
+		}), clcv2.WithJournal(clcv2.OpenJournal()))
 		if err != nil {
 			exit.Errorf("failed to initialize client: %s", err)
 		}
 
+		if useCache {
+			rc, err := clcv2.OpenResponseCache(client.LoginReq.Username, client.Endpoints().API, clcv2.DefaultCacheTTLs)
+			if err != nil {
+				exit.Errorf("failed to open response cache: %s", err)
+			}
+			client.SetCache(rc)
+		}
+
 		// Set the fallback data centre if no location was given
 		if conf.Location == "" {
 			conf.Location = client.LocationAlias
@@ -21,10 +21,11 @@ import (
 
 // Flags
 var showFlags struct {
-	GroupDetails bool // Whether to print group details instead of showing the contained servers
-	GroupTree    bool // Whether to display groups in tree format
-	GroupID      bool // Whether to display the group (hex) UUID at the right hand side
-	IP           bool // Whether to just display server IPs (implies GroupTree and GroupDetails)
+	GroupDetails bool   // Whether to print group details instead of showing the contained servers
+	GroupTree    bool   // Whether to display groups in tree format
+	GroupID      bool   // Whether to display the group (hex) UUID at the right hand side
+	IP           bool   // Whether to just display server IPs (implies GroupTree and GroupDetails)
+	Where        string // Filter expression to list matching servers by (see clcv2.ParseServerFilter)
 }
 
 func init() {
@@ -32,6 +33,7 @@ func init() {
 	Show.Flags().BoolVar(&showFlags.GroupTree, "tree", false, "Display nested group structure in tree format")
 	Show.Flags().BoolVar(&showFlags.GroupID, "id", true, "Print the UUID of the group as well")
 	Show.Flags().BoolVar(&showFlags.IP, "ip", false, "Print IP addresses of servers as well")
+	Show.Flags().StringVar(&showFlags.Where, "where", "", "List the servers in the given data centre(s) that match this filter expression")
 
 	Root.AddCommand(Show)
 }
@@ -47,6 +49,10 @@ var Show = &cobra.Command{
 		var root *clcv2.Group
 		var err error
 
+		if showFlags.Where != "" {
+			return findServers(showFlags.Where, args)
+		}
+
 		// Showing IP information implies printing the nested group structure
 		if showFlags.IP {
 			showFlags.GroupTree = true
@@ -391,15 +397,10 @@ func showServer(client *clcv2.CLIClient, server clcv2.Server) {
 // @client:    authenticated CLCv2 Client
 // @servnames: server names
 func showServers(client *clcv2.CLIClient, servnames []string) {
-	type asyncServerResult struct {
-		server clcv2.Server
-		group  clcv2.Group
-	}
-
 	var (
 		wg      sync.WaitGroup
-		resChan = make(chan asyncServerResult)
-		results []asyncServerResult
+		resChan = make(chan serverRow)
+		results []serverRow
 	)
 
 	for _, servname := range servnames {
@@ -418,9 +419,9 @@ func showServers(client *clcv2.CLIClient, servnames []string) {
 				fmt.Fprintf(os.Stderr, "Failed to resolve %s group UUID: %s\n", servname, err)
 				return
 			}
-			resChan <- asyncServerResult{
+			resChan <- serverRow{
 				server: server,
-				group:  *grp,
+				group:  grp.Name,
 			}
 		}()
 	}
@@ -434,13 +435,23 @@ func showServers(client *clcv2.CLIClient, servnames []string) {
 		results = append(results, res)
 	}
 
-	if len(results) > 0 {
-		var table = tablewriter.NewWriter(os.Stdout)
-		// Sort in ascending order of last-modified date.
+	// Sort in ascending order of last-modified date.
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].server.ChangeInfo.ModifiedDate.Before(results[j].server.ChangeInfo.ModifiedDate)
+	})
+	printServers(results)
+}
 
-		sort.Slice(results, func(i, j int) bool {
-			return results[i].server.ChangeInfo.ModifiedDate.Before(results[j].server.ChangeInfo.ModifiedDate)
-		})
+// serverRow is a server, along with the name (or path) of its group
+type serverRow struct {
+	server clcv2.Server
+	group  string
+}
+
+// printServers displays condensed details of the servers in @rows, in the given order
+func printServers(rows []serverRow) {
+	if len(rows) > 0 {
+		var table = tablewriter.NewWriter(os.Stdout)
 
 		table.SetAutoFormatHeaders(false)
 		table.SetAlignment(tablewriter.ALIGN_LEFT)
@@ -452,7 +463,7 @@ func showServers(client *clcv2.CLIClient, servnames []string) {
 			"Status", "Last Change",
 		})
 
-		for _, res := range results {
+		for _, res := range rows {
 			IPs := []string{}
 			for _, ip := range res.server.Details.IpAddresses {
 				if ip.Public != "" {
@@ -490,7 +501,7 @@ func showServers(client *clcv2.CLIClient, servnames []string) {
 			}
 
 			table.Append([]string{
-				serverName, res.group.Name, truncate(desc, 30), truncate(res.server.OsType, 15),
+				serverName, res.group, truncate(desc, 30), truncate(res.server.OsType, 15),
 				strings.Join(IPs, " "),
 				fmt.Sprint(res.server.Details.Cpu), fmt.Sprintf("%d G", res.server.Details.MemoryMb/1024),
 				fmt.Sprintf("%d G", res.server.Details.StorageGb),
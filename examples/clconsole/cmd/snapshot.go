@@ -6,6 +6,8 @@ package cmd
 import (
 	"log"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/grrtrr/clcv2"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -33,7 +35,7 @@ func init() {
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			serverCmd("create new snapshot", snapshotHandler, args)
+			serverBatchCmd("create new snapshot", snapshotHandler, args)
 		}}
 	createSnapshot.Flags().IntVar(&snapCreateFlags.days, "days", 10, "Number of days to keep the snapshot for")
 	Root.AddCommand(createSnapshot)
@@ -47,7 +49,7 @@ func init() {
 		Long:    "Delete server snapshot if it exists (error condition of no snapshot exists)",
 		PreRunE: checkAtLeastArgs(1, "Need at least 1 server to remove snapshots from"),
 		Run: func(cmd *cobra.Command, args []string) {
-			serverCmd("delete snapshot", client.DeleteSnapshot, args)
+			serverCmd("delete snapshot", client.DeleteSnapshotJob, args)
 		}})
 
 	// Revert to snapshot
@@ -58,21 +60,80 @@ func init() {
 		Long:    "Revert server(s) to last snapshot (error condition if no snapshot exists)",
 		PreRunE: checkAtLeastArgs(1, "Need at least 1 server to revert"),
 		Run: func(cmd *cobra.Command, args []string) {
-			serverCmd("revert to snapshot", client.RevertToSnapshot, args)
+			serverCmd("revert to snapshot", client.RevertToSnapshotJob, args)
 		}})
 }
 
-// snapshotHandler is a convenience function to automatically delete an existing snapshot before creating a new one.
+// snapshotHandler is a convenience function to automatically delete existing snapshots before creating new ones.
 // This is to satisfy the use case "I want to snapshot what I just did", without having to run multiple commands.
+// The new snapshots are created via a single batch request; servers whose old snapshot could not be deleted are skipped.
 // Note: relies on 'client' and 'intvl' global variables.
-func snapshotHandler(serverId string) (reqId string, err error) {
-	if reqID, err := client.DeleteSnapshot(serverId); err == nil {
-		log.Printf("%s delete current snapshot: %s", serverId, reqID)
-		client.PollStatusFn(reqID, intvl, func(s clcv2.QueueStatus) {
-			log.Printf("%s delete current snapshot: %s", serverId, s)
+func snapshotHandler(serverIds []string) ([]clcv2.BatchResult, error) {
+	var (
+		eg   errgroup.Group
+		res  = make([]clcv2.BatchResult, len(serverIds))
+		jobs = make([]clcv2.Job, len(serverIds))
+	)
+
+	for i, serverId := range serverIds {
+		i, serverId := i, serverId
+		res[i].Server = serverId
+		eg.Go(func() error {
+			if job, err := client.DeleteSnapshotJob(serverId); err == nil {
+				log.Printf("%s delete current snapshot: %s", serverId, job.ID())
+				jobs[i] = job
+			} else if err != clcv2.ErrNoSnapshot {
+				res[i].Err = errors.Errorf("%s: failed to delete potentially existing snapshot: %s", serverId, err)
+			}
+			return nil
 		})
-	} else if err != clcv2.ErrNoSnapshot {
-		return "", errors.Errorf("%s: failed to delete potentially existing snapshot: %s", serverId, err)
 	}
-	return client.CreateSnapshot(serverId, snapCreateFlags.days)
+	eg.Wait()
+
+	// The old snapshot must be gone before creating the new one, hence wait regardless of @intvl.
+	var pending []clcv2.Job
+	var index []int
+	for i, j := range jobs {
+		if j != nil {
+			pending, index = append(pending, j), append(index, i)
+		}
+	}
+	for k, r := range clcv2.AwaitAll(client.Context(), pending, &clcv2.AwaitOptions{MinInterval: intvl}).Results {
+		if r.Err != nil || r.Status != clcv2.Succeeded {
+			res[index[k]].Err = errors.Errorf("%s: failed to delete current snapshot (%s)", serverIds[index[k]], r.Status)
+		}
+	}
+
+	var create []string
+	for _, r := range res {
+		if r.Err == nil {
+			create = append(create, r.Server)
+		}
+	}
+	// A *BatchError only reports per-server failures, which are contained in @created.
+	created, err := client.CreateSnapshots(create, snapCreateFlags.days)
+	if _, partial := err.(*clcv2.BatchError); (err != nil && !partial) || len(created) != len(create) {
+		if err == nil {
+			err = errors.Errorf("expected %d results, got %d", len(create), len(created))
+		}
+		created = make([]clcv2.BatchResult, len(create))
+		for k, serverId := range create {
+			created[k] = clcv2.BatchResult{Server: serverId, Err: errors.Errorf("%s: failed to create snapshot: %s", serverId, err)}
+		}
+	}
+	for i, k := 0, 0; i < len(res) && k < len(created); i++ {
+		if res[i].Err == nil {
+			res[i], k = created[k], k+1
+		}
+	}
+	var failed int
+	for _, r := range res {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return res, errors.Errorf("%d of %d snapshots failed", failed, len(res))
+	}
+	return res, nil
 }
@@ -14,7 +14,7 @@ func init() {
 		Short:   "Await completion of queue job and report status",
 		PreRunE: checkArgs(1, "Need a status ID to poll"),
 		Run: func(cmd *cobra.Command, args []string) {
-			client.PollStatusFn(args[0], intvl, func(s clcv2.QueueStatus) {
+			pollStatus(args[0], "wait", "", func(s clcv2.QueueStatus) {
 				log.Printf("%s: %s", args[0], s)
 			})
 		},
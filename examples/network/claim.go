@@ -4,12 +4,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/grrtrr/clcv2"
 	"github.com/grrtrr/clcv2/clcv2cli"
@@ -19,6 +21,7 @@ import (
 
 func main() {
 	var location string
+	var notify = flag.String("notify", "", "Notify on completion (URL, pipe:<path>, or cmd:<command>)")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "usage: %s [options]  <data-centre>\n", path.Base(os.Args[0]))
@@ -32,14 +35,36 @@ func main() {
 	}
 	location = strings.ToUpper(flag.Arg(0))
 
+	var notifier clcv2.Notifier
+	if *notify != "" {
+		n, err := clcv2.ParseNotifier(*notify)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		notifier = n
+	}
+
 	client, err := clcv2cli.NewCLIClient()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	id, err := client.ClaimNetwork(location, func(s clcv2.QueueStatus) {
+	start := time.Now()
+	claim, err := client.ClaimNetworkJob(location)
+	if err != nil {
+		log.Fatalf("failed to claim a new network in %q: %s", location, err)
+	}
+	claim.OnProgress(func(_ clcv2.Job, s clcv2.QueueStatus) {
 		log.Printf("claiming new network in %s: %s", location, s)
 	})
+	err = claim.Wait(context.Background())
+	if notifier != nil {
+		if nerr := notifier.Notify(context.Background(), clcv2.NewNotification(claim.ID(), "claim-network", location,
+			claim.Status(), time.Since(start), err)); nerr != nil {
+			log.Printf("failed to send notification: %s", nerr)
+		}
+	}
+	id := claim.NetworkID()
 	if err != nil {
 		log.Fatalf("failed to claim a new network in %q: %s", location, err)
 	}
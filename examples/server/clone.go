@@ -17,6 +17,7 @@ import (
 	"github.com/grrtrr/clcv2/clcv2cli"
 	"github.com/grrtrr/exit"
 	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
 )
 
 func main() {
@@ -32,7 +33,8 @@ func main() {
 	var extraDrv = flag.Int("drive", 0, "Extra storage (in GB) to add to server as a raw disk")
 	var wasStopped bool
 	var maxAttempts = 1
-	var url, reqID string
+	var url string
+	var job clcv2.Job
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "usage: %s [options] <Source-Server-Name>\n", path.Base(os.Args[0]))
@@ -160,19 +162,14 @@ func main() {
 			// is prevented from querying details of the parent account, due to insufficient
 			// permission.
 			if parentAccount := client.RegisteredAccountAlias(); client.AccountAlias != parentAccount {
-				var savedAlias = client.AccountAlias
-
-				log.Printf("Network ID not visible under %q account - trying %q instead ...", savedAlias, parentAccount)
-				client.AccountAlias = parentAccount
-				if nets, err = client.GetServerNets(src); err != nil {
+				log.Printf("Network ID not visible under %q account - trying %q instead ...", client.AccountAlias, parentAccount)
+				if nets, err = client.WithAccount(parentAccount).GetServerNets(src); err != nil {
 					exit.Fatalf("failed to query networks of %s using %q account: %s", src.Name, parentAccount, err)
 				}
-				// Restore Account Alias for remainder of program
-				client.AccountAlias = savedAlias
 			}
 			if len(nets) == 0 {
 				log.Printf("Unable to determine Network ID - querying %s deployable networks ...", src.LocationId)
-				capa, err := client.GetDeploymentCapabilities(src.LocationId)
+				capa, err := client.WithAccount(client.RegisteredAccountAlias()).GetDeploymentCapabilities(src.LocationId)
 				if err != nil {
 					exit.Fatalf("failed to determine %s Deployment Capabilities: %s", src.LocationId, err)
 				}
@@ -215,8 +212,10 @@ func main() {
 
 	log.Printf("Cloning %s ...", src.Name)
 	for i := 1; ; i++ {
-		url, reqID, err = client.CreateServer(&req)
-		if err == nil || i == maxAttempts || strings.Index(err.Error(), "body.sourceServerId") > 0 {
+		var apiErr *clcv2.APIError
+
+		url, job, err = client.CreateServerJob(&req)
+		if err == nil || i == maxAttempts || errors.As(err, &apiErr) && len(apiErr.ModelState["body.sourceServerId"]) > 0 {
 			break
 		}
 		log.Printf("attempt %d/%d failed (%s) - retrying ...", i, maxAttempts, strings.TrimSpace(err.Error()))
@@ -226,15 +225,18 @@ func main() {
 		exit.Fatalf("failed to create server: %s", err)
 	}
 
-	status, err := client.PollStatus(reqID, 5*time.Second)
-	if err != nil {
-		exit.Fatalf("failed to poll %s status: %s", reqID, err)
+	// The job of an existing server, adopted after a failed request, has already succeeded.
+	job.OnProgress(func(_ clcv2.Job, s clcv2.QueueStatus) {
+		log.Printf("%s: %s", job.ID(), s)
+	})
+	if err = job.Wait(client.Context()); err != nil && !errors.Is(err, clcv2.ErrJobFailed) {
+		exit.Fatalf("failed to poll %s status: %s", job.ID(), err)
 	}
 
-	server, err := client.GetServerByURI(url)
-	if err != nil {
-		log.Fatalf("failed to query server details at %s: %s", url, err)
-	} else if status == clcv2.Failed {
+	server, serr := client.GetServerByURI(url)
+	if serr != nil {
+		log.Fatalf("failed to query server details at %s: %s", url, serr)
+	} else if err != nil {
 		exit.Fatalf("failed to clone %s (will show up as 'under construction')", server.Name)
 	}
 	log.Printf("New server name: %s\n", server.Name)
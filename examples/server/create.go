@@ -151,12 +151,18 @@ func main() {
 	// The CreateServer request resolves the server name at the end.
 	// This second call can fail at the remote end; it does not mean that
 	// the server has not been created yet.
-	url, reqID, err := client.CreateServer(&req)
+	url, job, err := client.CreateServerJob(&req)
 	if err != nil {
 		log.Fatalf("failed to create server: %s", err)
 	}
-	log.Printf("Status Id: %s", reqID)
-	client.PollStatus(reqID, 5*time.Second)
+	// The job of an existing server, adopted after a failed request, has already succeeded.
+	log.Printf("Status Id: %s", job.ID())
+	job.OnProgress(func(_ clcv2.Job, s clcv2.QueueStatus) {
+		log.Printf("%s: %s", job.ID(), s)
+	})
+	if err = job.Wait(client.Context()); err != nil {
+		log.Fatalf("failed to create server: %s", err)
+	}
 
 	// Print details after job completes
 	server, err := client.GetServerByURI(url)
@@ -0,0 +1,29 @@
+package clcv2
+
+import (
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+)
+
+// lockFile acquires an exclusive advisory lock on the file at @lockPath (created if necessary),
+// blocking until it becomes available. This serializes access among cooperating processes.
+// The returned function releases the lock.
+func lockFile(lockPath string) (unlock func(), err error) {
+	if err = os.MkdirAll(path.Dir(lockPath), 0700); err != nil {
+		return nil, errors.Errorf("failed to create directory for %s: %s", lockPath, err)
+	}
+
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Errorf("failed to open lock file: %s", err)
+	} else if err = lockFd(f); err != nil {
+		f.Close()
+		return nil, errors.Errorf("failed to lock %s: %s", lockPath, err)
+	}
+	return func() {
+		unlockFd(f)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+// +build !windows
+
+package clcv2
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFd places an exclusive flock(2) lock on @f.
+func lockFd(f *os.File) error {
+	for {
+		if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockFd releases the lock placed on @f by lockFd.
+func unlockFd(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
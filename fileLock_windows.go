@@ -0,0 +1,17 @@
+package clcv2
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFd places an exclusive LockFileEx lock on the first byte of @f.
+func lockFd(f *os.File) error {
+	return windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, new(windows.Overlapped))
+}
+
+// unlockFd releases the lock placed on @f by lockFd.
+func unlockFd(f *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, new(windows.Overlapped))
+}
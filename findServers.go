@@ -0,0 +1,148 @@
+package clcv2
+
+/*
+ * Finding servers that match a filter expression, across data centres.
+ */
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Default number of concurrent requests of FindServers
+const findServerWorkers = 8
+
+// FoundServer is a server returned by FindServers.
+type FoundServer struct {
+	Server
+
+	// Slash-separated names of the group of the server and its ancestors, starting at the data centre root group
+	GroupPath string
+}
+
+// FindServers returns the servers in the data centres @locations that match @filter, sorted by location and name.
+// If @locations is empty, all data centres of the account are searched; if @filter is nil, all servers match.
+// The details of the servers are queried concurrently. Servers that disappear while searching are skipped.
+func (c *Client) FindServers(ctx context.Context, locations []string, filter *ServerFilter) ([]FoundServer, error) {
+	var f = &serverFinder{c: c, filter: filter, sem: make(chan struct{}, findServerWorkers)}
+
+	f.ctx, f.cancel = context.WithCancel(ctx)
+	defer f.cancel()
+
+	if len(locations) == 0 {
+		dcs, err := c.GetLocationsContext(ctx)
+		if err != nil {
+			return nil, errors.Errorf("failed to list data centres: %s", err)
+		}
+		for _, dc := range dcs {
+			locations = append(locations, dc.Id)
+		}
+	}
+
+	for _, loc := range locations {
+		loc := strings.ToUpper(loc)
+
+		f.run(func() error {
+			root, err := c.GetGroupsContext(f.ctx, loc)
+			if err != nil {
+				return errors.Errorf("failed to look up groups in %s: %s", loc, err)
+			}
+			f.walk(root, "")
+			return nil
+		})
+	}
+	f.wg.Wait()
+
+	if f.err != nil {
+		return nil, f.err
+	} else if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(f.res, func(i, j int) bool {
+		if f.res[i].LocationId != f.res[j].LocationId {
+			return f.res[i].LocationId < f.res[j].LocationId
+		}
+		return f.res[i].Name < f.res[j].Name
+	})
+	return f.res, nil
+}
+
+// serverFinder implements FindServers.
+type serverFinder struct {
+	c      *Client
+	filter *ServerFilter
+	sem    chan struct{} // limits the number of concurrent requests
+	wg     sync.WaitGroup
+
+	ctx    context.Context
+	cancel context.CancelFunc // cancels @ctx after the first error
+
+	mu  sync.Mutex // protects @res and @err
+	res []FoundServer
+	err error
+}
+
+// run runs @fn asynchronously while holding a worker slot. The first error cancels the search.
+func (f *serverFinder) run(fn func() error) {
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		select {
+		case f.sem <- struct{}{}:
+		case <-f.ctx.Done():
+			return
+		}
+		err := fn()
+		<-f.sem
+
+		if err != nil && f.ctx.Err() == nil {
+			f.mu.Lock()
+			if f.err == nil {
+				f.err = err
+			}
+			f.mu.Unlock()
+			f.cancel()
+		}
+	}()
+}
+
+// walk queries the servers in the group tree at @g, whose parent has the path @parentPath.
+func (f *serverFinder) walk(g *Group, parentPath string) {
+	var path = g.Name
+
+	if parentPath != "" {
+		path = parentPath + "/" + g.Name
+	}
+
+	for _, l := range ExtractLinks(g.Links, "server") {
+		l := l
+		f.run(func() error {
+			var s Server
+			var err error
+
+			if l.Href != "" {
+				s, err = f.c.GetServerByURIContext(f.ctx, l.Href)
+			} else {
+				s, err = f.c.GetServerContext(f.ctx, l.Id)
+			}
+			if IsNotFound(err) { // deleted in the meantime
+				return nil
+			} else if err != nil {
+				return errors.Errorf("failed to query server %s: %s", l.Id, err)
+			} else if f.filter.Match(&s, path) {
+				f.mu.Lock()
+				f.res = append(f.res, FoundServer{Server: s, GroupPath: path})
+				f.mu.Unlock()
+			}
+			return nil
+		})
+	}
+	for idx := range g.Groups {
+		f.walk(&g.Groups[idx], path)
+	}
+}
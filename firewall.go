@@ -1,6 +1,7 @@
 package clcv2
 
 import (
+	"context"
 	"fmt"
 	"path"
 )
@@ -47,8 +48,13 @@ type IntraDataCenterFirewallPolicy struct {
 // @location: Short string representing the data center to query.
 // @policyId: ID of the firewall policy to display.
 func (c *Client) GetIntraDataCenterFirewallPolicy(location, policyId string) (res IntraDataCenterFirewallPolicy, err error) {
-	var path = fmt.Sprintf("/v2-experimental/firewallPolicies/%s/%s/%s", c.credentials.AccountAlias, location, policyId)
-	err = c.getCLCResponse("GET", path, nil, &res)
+	return c.GetIntraDataCenterFirewallPolicyContext(c.ctx, location, policyId)
+}
+
+// GetIntraDataCenterFirewallPolicyContext is like GetIntraDataCenterFirewallPolicy, using @ctx for cancellation.
+func (c *Client) GetIntraDataCenterFirewallPolicyContext(ctx context.Context, location, policyId string) (res IntraDataCenterFirewallPolicy, err error) {
+	var path = fmt.Sprintf("/v2-experimental/firewallPolicies/%s/%s/%s", c.AccountAlias, location, policyId)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
@@ -57,11 +63,16 @@ func (c *Client) GetIntraDataCenterFirewallPolicy(location, policyId string) (re
 // @location:   Short string representing the data center to query.
 // @dstAccount: Optional destination account (empty string to omit).
 func (c *Client) GetIntraDataCenterFirewallPolicyList(location, dstAccount string) (res []IntraDataCenterFirewallPolicy, err error) {
-	var path = fmt.Sprintf("/v2-experimental/firewallPolicies/%s/%s", c.credentials.AccountAlias, location)
+	return c.GetIntraDataCenterFirewallPolicyListContext(c.ctx, location, dstAccount)
+}
+
+// GetIntraDataCenterFirewallPolicyListContext is like GetIntraDataCenterFirewallPolicyList, using @ctx for cancellation.
+func (c *Client) GetIntraDataCenterFirewallPolicyListContext(ctx context.Context, location, dstAccount string) (res []IntraDataCenterFirewallPolicy, err error) {
+	var path = fmt.Sprintf("/v2-experimental/firewallPolicies/%s/%s", c.AccountAlias, location)
 	if dstAccount != "" {
 		path += fmt.Sprintf("?destinationAccount=%s", dstAccount)
 	}
-	err = c.getCLCResponse("GET", path, nil, &res)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
@@ -82,11 +93,16 @@ type IntraDataCenterFirewallPolicyReq struct {
 
 // CreateIntraDataCenterFirewallPolicy creates a new intra-datacenter firewall policy at @location.
 func (c *Client) CreateIntraDataCenterFirewallPolicy(location string, req *IntraDataCenterFirewallPolicyReq) (id string, err error) {
+	return c.CreateIntraDataCenterFirewallPolicyContext(c.ctx, location, req)
+}
+
+// CreateIntraDataCenterFirewallPolicyContext is like CreateIntraDataCenterFirewallPolicy, using @ctx for cancellation.
+func (c *Client) CreateIntraDataCenterFirewallPolicyContext(ctx context.Context, location string, req *IntraDataCenterFirewallPolicyReq) (id string, err error) {
 	var res struct {
 		Links []Link
 	}
 
-	err = c.getCLCResponse("POST", fmt.Sprintf("/v2-experimental/firewallPolicies/%s/%s", c.AccountAlias, location), req, &res)
+	err = c.getCLCResponse(ctx, "POST", fmt.Sprintf("/v2-experimental/firewallPolicies/%s/%s", c.AccountAlias, location), req, &res)
 	if err != nil {
 		/* response failed */
 	} else if link, err := extractLink(res.Links, "self"); err != nil {
@@ -100,7 +116,12 @@ func (c *Client) CreateIntraDataCenterFirewallPolicy(location string, req *Intra
 
 // DeleteIntraDataCenterFirewallPolicy deletes the given cross-datacenter firewall policy @id in datacenter @location.
 func (c *Client) DeleteIntraDataCenterFirewallPolicy(location, id string) error {
-	return c.getCLCResponse("DELETE", fmt.Sprintf("/v2-experimental/firewallPolicies/%s/%s/%s", c.AccountAlias, location, id), nil, nil)
+	return c.DeleteIntraDataCenterFirewallPolicyContext(c.ctx, location, id)
+}
+
+// DeleteIntraDataCenterFirewallPolicyContext is like DeleteIntraDataCenterFirewallPolicy, using @ctx for cancellation.
+func (c *Client) DeleteIntraDataCenterFirewallPolicyContext(ctx context.Context, location, id string) error {
+	return c.getCLCResponse(ctx, "DELETE", fmt.Sprintf("/v2-experimental/firewallPolicies/%s/%s/%s", c.AccountAlias, location, id), nil, nil)
 }
 
 /*
@@ -144,12 +165,17 @@ type CrossDataCenterFirewallPolicy struct {
 // @location:   short string representing the data center to query
 // @dstAccount: optional destination account (empty string to omit)
 func (c *Client) GetCrossDataCenterFirewallPolicyList(location, dstAccount string) (res []CrossDataCenterFirewallPolicy, err error) {
-	var path = fmt.Sprintf("/v2-experimental/crossDcFirewallPolicies/%s/%s", c.credentials.AccountAlias, location)
+	return c.GetCrossDataCenterFirewallPolicyListContext(c.ctx, location, dstAccount)
+}
+
+// GetCrossDataCenterFirewallPolicyListContext is like GetCrossDataCenterFirewallPolicyList, using @ctx for cancellation.
+func (c *Client) GetCrossDataCenterFirewallPolicyListContext(ctx context.Context, location, dstAccount string) (res []CrossDataCenterFirewallPolicy, err error) {
+	var path = fmt.Sprintf("/v2-experimental/crossDcFirewallPolicies/%s/%s", c.AccountAlias, location)
 
 	if dstAccount != "" {
 		path += fmt.Sprintf("?destinationAccount=%s", dstAccount)
 	}
-	err = c.getCLCResponse("GET", path, nil, &res)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
@@ -157,9 +183,14 @@ func (c *Client) GetCrossDataCenterFirewallPolicyList(location, dstAccount strin
 // @location: data center location
 // @id:       cross-datacenter policy ID
 func (c *Client) GetCrossDataCenterFirewallPolicy(location, id string) (res CrossDataCenterFirewallPolicy, err error) {
-	var path = fmt.Sprintf("/v2-experimental/crossDcFirewallPolicies/%s/%s/%s", c.credentials.AccountAlias, location, id)
+	return c.GetCrossDataCenterFirewallPolicyContext(c.ctx, location, id)
+}
+
+// GetCrossDataCenterFirewallPolicyContext is like GetCrossDataCenterFirewallPolicy, using @ctx for cancellation.
+func (c *Client) GetCrossDataCenterFirewallPolicyContext(ctx context.Context, location, id string) (res CrossDataCenterFirewallPolicy, err error) {
+	var path = fmt.Sprintf("/v2-experimental/crossDcFirewallPolicies/%s/%s/%s", c.AccountAlias, location, id)
 
-	err = c.getCLCResponse("GET", path, nil, &res)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
@@ -168,9 +199,14 @@ func (c *Client) GetCrossDataCenterFirewallPolicy(location, id string) (res Cros
 // @id:       cross-datacenter policy ID
 // @enable:   whether to enable or disable @id
 func (c *Client) ToggleCrossDataCenterFirewallPolicy(location, id string, enable bool) error {
+	return c.ToggleCrossDataCenterFirewallPolicyContext(c.ctx, location, id, enable)
+}
+
+// ToggleCrossDataCenterFirewallPolicyContext is like ToggleCrossDataCenterFirewallPolicy, using @ctx for cancellation.
+func (c *Client) ToggleCrossDataCenterFirewallPolicyContext(ctx context.Context, location, id string, enable bool) error {
 	var path = fmt.Sprintf("/v2-experimental/crossDcFirewallPolicies/%s/%s/%s?enabled=%t", c.AccountAlias, location, id, enable)
 
-	return c.getCLCResponse("PUT", path, nil, nil) // the response is an empty "204 No Content"
+	return c.getCLCResponse(ctx, "PUT", path, nil, nil) // the response is an empty "204 No Content"
 }
 
 // CrossDataCenterFirewallPolicyReq contains the requisite data to request a new cross-datacenter firewall policy.
@@ -193,11 +229,21 @@ type CrossDataCenterFirewallPolicyReq struct {
 
 // CreateCrossDataCenterFirewallPolicy creates a new cross-datacenter firewall policy at @location.
 func (c *Client) CreateCrossDataCenterFirewallPolicy(location string, req *CrossDataCenterFirewallPolicyReq) (res CrossDataCenterFirewallPolicy, err error) {
-	err = c.getCLCResponse("POST", fmt.Sprintf("/v2-experimental/crossDcFirewallPolicies/%s/%s", c.AccountAlias, location), req, &res)
+	return c.CreateCrossDataCenterFirewallPolicyContext(c.ctx, location, req)
+}
+
+// CreateCrossDataCenterFirewallPolicyContext is like CreateCrossDataCenterFirewallPolicy, using @ctx for cancellation.
+func (c *Client) CreateCrossDataCenterFirewallPolicyContext(ctx context.Context, location string, req *CrossDataCenterFirewallPolicyReq) (res CrossDataCenterFirewallPolicy, err error) {
+	err = c.getCLCResponse(ctx, "POST", fmt.Sprintf("/v2-experimental/crossDcFirewallPolicies/%s/%s", c.AccountAlias, location), req, &res)
 	return res, err
 }
 
 // DeleteCrossDataCenterFirewallPolicy deletes the given cross-datacenter firewall policy @id in datacenter @location.
 func (c *Client) DeleteCrossDataCenterFirewallPolicy(location, id string) error {
-	return c.getCLCResponse("DELETE", fmt.Sprintf("/v2-experimental/crossDcFirewallPolicies/%s/%s/%s", c.AccountAlias, location, id), nil, nil)
+	return c.DeleteCrossDataCenterFirewallPolicyContext(c.ctx, location, id)
+}
+
+// DeleteCrossDataCenterFirewallPolicyContext is like DeleteCrossDataCenterFirewallPolicy, using @ctx for cancellation.
+func (c *Client) DeleteCrossDataCenterFirewallPolicyContext(ctx context.Context, location, id string) error {
+	return c.getCLCResponse(ctx, "DELETE", fmt.Sprintf("/v2-experimental/crossDcFirewallPolicies/%s/%s/%s", c.AccountAlias, location, id), nil, nil)
 }
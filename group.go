@@ -1,6 +1,7 @@
 package clcv2
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -45,16 +46,26 @@ type Group struct {
 // Get the details of an individual group and any sub-groups (and servers) that it contains.
 // @groupId: ID of the group being queried.
 func (c *Client) GetGroup(groupId string) (res *Group, err error) {
+	return c.GetGroupContext(c.ctx, groupId)
+}
+
+// GetGroupContext is like GetGroup, using @ctx for cancellation.
+func (c *Client) GetGroupContext(ctx context.Context, groupId string) (res *Group, err error) {
 	path := fmt.Sprintf("/v2/groups/%s/%s", c.AccountAlias, groupId)
 	res = new(Group)
-	err = c.getCLCResponse("GET", path, nil, res)
+	err = c.getCLCResponse(ctx, "GET", path, nil, res)
 	return res, err
 }
 
 // Gets a list of all groups with the specified search criteria.
 // @location:  The data center location to query for groups.
 func (c *Client) GetGroups(location string) (rootNode *Group, err error) {
-	dc, err := c.GetDatacenter(location, true)
+	return c.GetGroupsContext(c.ctx, location)
+}
+
+// GetGroupsContext is like GetGroups, using @ctx for cancellation.
+func (c *Client) GetGroupsContext(ctx context.Context, location string) (rootNode *Group, err error) {
+	dc, err := c.GetDatacenterContext(ctx, location, true)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +74,7 @@ func (c *Client) GetGroups(location string) (rootNode *Group, err error) {
 	if err != nil {
 		return nil, err
 	}
-	return c.GetGroup(gl.Id)
+	return c.GetGroupContext(ctx, gl.Id)
 }
 
 // Do a depth-first traversal of the tree to find a specific node.
@@ -96,9 +107,14 @@ func visitGroup(g *Group, res chan *Group, found func(*Group) bool) {
 // @found:     Function returning true if the passed Group qualifies.
 // Returns array of pointers to Group; or error on failure.
 func (c *Client) GetGroupsFiltered(location string, found func(*Group) bool) (res []*Group, err error) {
+	return c.GetGroupsFilteredContext(c.ctx, location, found)
+}
+
+// GetGroupsFilteredContext is like GetGroupsFiltered, using @ctx for cancellation.
+func (c *Client) GetGroupsFilteredContext(ctx context.Context, location string, found func(*Group) bool) (res []*Group, err error) {
 	var rootNode *Group
 
-	if rootNode, err = c.GetGroups(location); err == nil {
+	if rootNode, err = c.GetGroupsContext(ctx, location); err == nil {
 		resultChan := make(chan *Group)
 		go func() {
 			visitGroup(rootNode, resultChan, found)
@@ -116,7 +132,12 @@ func (c *Client) GetGroupsFiltered(location string, found func(*Group) bool) (re
 // @found:     Function returning true if the passed Hardware Group is the one looked for.
 // Returns pointer to Group, nil if not found; or error on failure.
 func (c *Client) GetGroupFiltered(location string, found func(*Group) bool) (res *Group, err error) {
-	if groups, err := c.GetGroupsFiltered(location, found); err != nil {
+	return c.GetGroupFilteredContext(c.ctx, location, found)
+}
+
+// GetGroupFilteredContext is like GetGroupFiltered, using @ctx for cancellation.
+func (c *Client) GetGroupFilteredContext(ctx context.Context, location string, found func(*Group) bool) (res *Group, err error) {
+	if groups, err := c.GetGroupsFilteredContext(ctx, location, found); err != nil {
 		return nil, err
 	} else if len(groups) == 1 {
 		res = groups[0]
@@ -128,13 +149,23 @@ func (c *Client) GetGroupFiltered(location string, found func(*Group) bool) (res
 
 // Look up Hardware Group by @name and @location
 func (c *Client) GetGroupByName(name, location string) (*Group, error) {
-	return c.GetGroupFiltered(location, func(g *Group) bool { return g.Name == name })
+	return c.GetGroupByNameContext(c.ctx, name, location)
+}
+
+// GetGroupByNameContext is like GetGroupByName, using @ctx for cancellation.
+func (c *Client) GetGroupByNameContext(ctx context.Context, name, location string) (*Group, error) {
+	return c.GetGroupFilteredContext(ctx, location, func(g *Group) bool { return g.Name == name })
 }
 
 // Look up Hardware Group by @uuid and @location
 // The @location is required, since there is no global 'resolveGroup(uuid)' function.
 func (c *Client) GetGroupByUUID(uuid, location string) (*Group, error) {
-	return c.GetGroupFiltered(location, func(g *Group) bool { return g.Id == uuid })
+	return c.GetGroupByUUIDContext(c.ctx, uuid, location)
+}
+
+// GetGroupByUUIDContext is like GetGroupByUUID, using @ctx for cancellation.
+func (c *Client) GetGroupByUUIDContext(ctx context.Context, uuid, location string) (*Group, error) {
+	return c.GetGroupFilteredContext(ctx, location, func(g *Group) bool { return g.Id == uuid })
 }
 
 // Create a new Hardware Group.
@@ -142,14 +173,47 @@ func (c *Client) GetGroupByUUID(uuid, location string) (*Group, error) {
 // @parent: The unique identifier of the parent group.
 // @desc:   User-defined description of this group.
 // @cf:     Optional array of Custom Fields to set.
+// Failed requests are only retried if no sub-group of @parent with the same @name and @desc has
+// been created in the meantime (see RetryPolicy). Such a group is returned instead.
 func (c *Client) CreateGroup(name, parent, desc string, cf []SimpleCustomField) (res Group, err error) {
+	return c.CreateGroupContext(c.ctx, name, parent, desc, cf)
+}
+
+// CreateGroupContext is like CreateGroup, using @ctx for cancellation.
+func (c *Client) CreateGroupContext(ctx context.Context, name, parent, desc string, cf []SimpleCustomField) (res Group, err error) {
 	req := struct {
 		Name          string              `json:"name"`
 		Description   string              `json:"description"`
 		ParentGroupId string              `json:"parentGroupId"`
 		CustomFields  []SimpleCustomField `json:"customFields"`
 	}{name, desc, parent, cf}
-	err = c.getCLCResponse("POST", fmt.Sprintf("/v2/groups/%s", c.AccountAlias), &req, &res)
+	var existing = make(map[string]bool) // sub-groups of @parent before the request
+
+	if c.retryPolicy.RetryNonIdempotent {
+		p, err := c.GetGroupContext(ctx, parent)
+		if err != nil {
+			return res, errors.Errorf("failed to query parent group %s: %s", parent, err)
+		}
+		for _, g := range p.Groups {
+			existing[g.Id] = true
+		}
+	}
+
+	err = c.createOnce(ctx, "group "+name, func(ctx context.Context) error {
+		return c.getCLCResponse(ctx, "POST", fmt.Sprintf("/v2/groups/%s", c.AccountAlias), &req, &res)
+	}, func(ctx context.Context) (bool, error) {
+		p, err := c.GetGroupContext(ctx, parent)
+		if err != nil {
+			return false, err
+		}
+		for _, g := range p.Groups {
+			if !existing[g.Id] && g.Name == name && g.Description == desc {
+				res = g
+				return true, nil
+			}
+		}
+		return false, nil
+	})
 	return res, err
 }
 
@@ -157,7 +221,12 @@ func (c *Client) CreateGroup(name, parent, desc string, cf []SimpleCustomField)
 // @groupId: ID of the group to update
 // @newName: new name for @groupId.
 func (c *Client) GroupSetName(groupId, newName string) error {
-	return c.patch(fmt.Sprintf("/v2/groups/%s/%s", c.AccountAlias, groupId),
+	return c.GroupSetNameContext(c.ctx, groupId, newName)
+}
+
+// GroupSetNameContext is like GroupSetName, using @ctx for cancellation.
+func (c *Client) GroupSetNameContext(ctx context.Context, groupId, newName string) error {
+	return c.patch(ctx, fmt.Sprintf("/v2/groups/%s/%s", c.AccountAlias, groupId),
 		&PatchOperation{"set", "name", newName})
 }
 
@@ -165,7 +234,12 @@ func (c *Client) GroupSetName(groupId, newName string) error {
 // @groupId: ID of the group to update
 // @newDesc: new description for @groupId.
 func (c *Client) GroupSetDescription(groupId, newDesc string) error {
-	return c.patch(fmt.Sprintf("/v2/groups/%s/%s", c.AccountAlias, groupId),
+	return c.GroupSetDescriptionContext(c.ctx, groupId, newDesc)
+}
+
+// GroupSetDescriptionContext is like GroupSetDescription, using @ctx for cancellation.
+func (c *Client) GroupSetDescriptionContext(ctx context.Context, groupId, newDesc string) error {
+	return c.patch(ctx, fmt.Sprintf("/v2/groups/%s/%s", c.AccountAlias, groupId),
 		&PatchOperation{"set", "description", newDesc})
 }
 
@@ -173,7 +247,12 @@ func (c *Client) GroupSetDescription(groupId, newDesc string) error {
 // @groupId: ID of the group to update
 // @parentUUID: UUID of new parent group for @groupId.
 func (c *Client) GroupSetParent(groupId, parentUUID string) error {
-	return c.patch(fmt.Sprintf("/v2/groups/%s/%s", c.AccountAlias, groupId),
+	return c.GroupSetParentContext(c.ctx, groupId, parentUUID)
+}
+
+// GroupSetParentContext is like GroupSetParent, using @ctx for cancellation.
+func (c *Client) GroupSetParentContext(ctx context.Context, groupId, parentUUID string) error {
+	return c.patch(ctx, fmt.Sprintf("/v2/groups/%s/%s", c.AccountAlias, groupId),
 		&PatchOperation{"set", "parentGroupId", parentUUID})
 }
 
@@ -181,7 +260,22 @@ func (c *Client) GroupSetParent(groupId, parentUUID string) error {
 // This operation will delete the group and all servers and groups underneath it.
 // @groupId: UUID of the group to be deleted.
 func (c *Client) DeleteGroup(groupId string) (statusId string, err error) {
-	return c.getStatus("DELETE", fmt.Sprintf("/v2/groups/%s/%s", c.AccountAlias, groupId), nil)
+	return c.DeleteGroupContext(c.ctx, groupId)
+}
+
+// DeleteGroupContext is like DeleteGroup, using @ctx for cancellation.
+func (c *Client) DeleteGroupContext(ctx context.Context, groupId string) (statusId string, err error) {
+	return c.getStatus(ctx, "DELETE", fmt.Sprintf("/v2/groups/%s/%s", c.AccountAlias, groupId), nil)
+}
+
+// DeleteGroupJob is like DeleteGroup, but returns the Job of the operation (see StatusJob).
+func (c *Client) DeleteGroupJob(groupId string) (Job, error) {
+	return c.DeleteGroupJobContext(c.ctx, groupId)
+}
+
+// DeleteGroupJobContext is like DeleteGroupJob, using @ctx for cancellation.
+func (c *Client) DeleteGroupJobContext(ctx context.Context, groupId string) (Job, error) {
+	return c.statusJobOf(c.DeleteGroupContext(ctx, groupId))
 }
 
 /*
@@ -189,18 +283,48 @@ func (c *Client) DeleteGroup(groupId string) (statusId string, err error) {
  */
 // ArchiveGroup archives the hardware group @groupId.
 func (c *Client) ArchiveGroup(groupId string) (statusId string, err error) {
-	return c.getStatus("POST", fmt.Sprintf("/v2/groups/%s/%s/archive", c.AccountAlias, groupId), nil)
+	return c.ArchiveGroupContext(c.ctx, groupId)
+}
+
+// ArchiveGroupContext is like ArchiveGroup, using @ctx for cancellation.
+func (c *Client) ArchiveGroupContext(ctx context.Context, groupId string) (statusId string, err error) {
+	return c.getStatus(ctx, "POST", fmt.Sprintf("/v2/groups/%s/%s/archive", c.AccountAlias, groupId), nil)
+}
+
+// ArchiveGroupJob is like ArchiveGroup, but returns the Job of the operation (see StatusJob).
+func (c *Client) ArchiveGroupJob(groupId string) (Job, error) {
+	return c.ArchiveGroupJobContext(c.ctx, groupId)
+}
+
+// ArchiveGroupJobContext is like ArchiveGroupJob, using @ctx for cancellation.
+func (c *Client) ArchiveGroupJobContext(ctx context.Context, groupId string) (Job, error) {
+	return c.statusJobOf(c.ArchiveGroupContext(ctx, groupId))
 }
 
 // RestoreGroup restores @groupId into the HW Group identified by @targetGroupId
 func (c *Client) RestoreGroup(groupId, targetGroupId string) (statusId string, err error) {
+	return c.RestoreGroupContext(c.ctx, groupId, targetGroupId)
+}
+
+// RestoreGroupContext is like RestoreGroup, using @ctx for cancellation.
+func (c *Client) RestoreGroupContext(ctx context.Context, groupId, targetGroupId string) (statusId string, err error) {
 	var path = fmt.Sprintf("/v2/groups/%s/%s/restore", c.AccountAlias, groupId)
 
-	return c.getStatusResponseId("POST", path, false, &struct {
+	return c.getStatusResponseId(ctx, "POST", path, false, &struct {
 		TargetGroupId string `json:"targetGroupId"`
 	}{targetGroupId})
 }
 
+// RestoreGroupJob is like RestoreGroup, but returns the Job of the operation (see StatusJob).
+func (c *Client) RestoreGroupJob(groupId, targetGroupId string) (Job, error) {
+	return c.RestoreGroupJobContext(c.ctx, groupId, targetGroupId)
+}
+
+// RestoreGroupJobContext is like RestoreGroupJob, using @ctx for cancellation.
+func (c *Client) RestoreGroupJobContext(ctx context.Context, groupId, targetGroupId string) (Job, error) {
+	return c.statusJobOf(c.RestoreGroupContext(ctx, groupId, targetGroupId))
+}
+
 /*
  * Group Billing Details
  */
@@ -222,8 +346,13 @@ type GroupBillingDetails struct {
 // Get the current and estimated charges for each server in a designated group hierarchy.
 // @groupId: ID of the group being queried.
 func (c *Client) GetGroupBillingDetails(groupId string) (res GroupBillingDetails, err error) {
-	path := fmt.Sprintf("/v2/groups/%s/%s/billing", c.credentials.AccountAlias, groupId)
-	err = c.getCLCResponse("GET", path, nil, &res)
+	return c.GetGroupBillingDetailsContext(c.ctx, groupId)
+}
+
+// GetGroupBillingDetailsContext is like GetGroupBillingDetails, using @ctx for cancellation.
+func (c *Client) GetGroupBillingDetailsContext(ctx context.Context, groupId string) (res GroupBillingDetails, err error) {
+	path := fmt.Sprintf("/v2/groups/%s/%s/billing", c.AccountAlias, groupId)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
@@ -286,8 +415,13 @@ type GroupScheduledActivity struct {
 // Get the scheduled activities associated with a group.
 // @groupId: ID of the group being queried.
 func (c *Client) GetGroupScheduledActivities(groupId string) (res []GroupScheduledActivity, err error) {
-	path := fmt.Sprintf("/v2/groups/%s/%s/ScheduledActivities", c.credentials.AccountAlias, groupId)
-	err = c.getCLCResponse("GET", path, nil, &res)
+	return c.GetGroupScheduledActivitiesContext(c.ctx, groupId)
+}
+
+// GetGroupScheduledActivitiesContext is like GetGroupScheduledActivities, using @ctx for cancellation.
+func (c *Client) GetGroupScheduledActivitiesContext(ctx context.Context, groupId string) (res []GroupScheduledActivity, err error) {
+	path := fmt.Sprintf("/v2/groups/%s/%s/ScheduledActivities", c.AccountAlias, groupId)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
@@ -325,7 +459,12 @@ type GroupDefaultSetting struct {
 // Set the defaults for a group.
 // @groupId: ID of the group to set defaults of.
 func (c *Client) SetGroupDefaults(groupId string, gd *GroupDefaults) (res map[string]GroupDefaultSetting, err error) {
+	return c.SetGroupDefaultsContext(c.ctx, groupId, gd)
+}
+
+// SetGroupDefaultsContext is like SetGroupDefaults, using @ctx for cancellation.
+func (c *Client) SetGroupDefaultsContext(ctx context.Context, groupId string, gd *GroupDefaults) (res map[string]GroupDefaultSetting, err error) {
 	path := fmt.Sprintf("/v2/groups/%s/%s/defaults", c.AccountAlias, groupId)
-	err = c.getCLCResponse("POST", path, gd, &res)
+	err = c.getCLCResponse(ctx, "POST", path, gd, &res)
 	return res, err
 }
@@ -0,0 +1,196 @@
+package clcv2
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Hooks receives instrumentation callbacks for each API request performed by a Client.
+// Implementations must be safe for concurrent use.
+type Hooks interface {
+	// OnRequest is called right before a request is sent.
+	OnRequest(*RequestInfo)
+
+	// OnResponse is called once a request has completed (successfully or not).
+	OnResponse(*ResponseInfo)
+}
+
+// RequestInfo describes a single API request.
+type RequestInfo struct {
+	// Endpoint family the request was sent to
+	Family EndpointFamily
+
+	// HTTP verb of the request
+	Verb string
+
+	// Request path (without query string)
+	Path string
+
+	// @Path with resource identifiers replaced by placeholders, e.g. "/v2/servers/{account}/{server}"
+	Template string
+}
+
+// ResponseInfo describes the outcome of a single API request.
+type ResponseInfo struct {
+	RequestInfo
+
+	// HTTP status code of the (final) response, 0 if none was received
+	StatusCode int
+
+	// Time taken by the request, including any retries
+	Duration time.Duration
+
+	// Number of retries performed by the retry policy
+	Retries int
+
+	// Error returned to the caller, nil on success
+	Err error
+
+	// Classification of @Err, one of the ErrClass* constants
+	ErrorClass string
+}
+
+// Error classes reported via ResponseInfo.ErrorClass
+const (
+	ErrClassNone        = ""
+	ErrClassNetwork     = "network"      // no response received
+	ErrClassCanceled    = "canceled"     // context canceled or deadline exceeded
+	ErrClassAuth        = "auth"         // authentication failure
+	ErrClassNotFound    = "not_found"    // %ErrNotFound
+	ErrClassConflict    = "conflict"     // %ErrConflict
+	ErrClassValidation  = "validation"   // %ErrValidation
+	ErrClassRateLimited = "rate_limited" // %ErrRateLimited
+	ErrClassClient      = "client"       // other 4xx errors
+	ErrClassServer      = "server"       // 5xx errors
+	ErrClassDecode      = "decode"       // successful response that could not be decoded
+)
+
+// classifyError maps the result (@status, @err) of a request onto an ErrClass* constant.
+func classifyError(ctx context.Context, status int, err error) string {
+	switch {
+	case err == nil:
+		return ErrClassNone
+	case ctx != nil && ctx.Err() != nil:
+		return ErrClassCanceled
+	case status == 0:
+		return ErrClassNetwork
+	case status == http.StatusUnauthorized || errors.Is(err, ErrCredentialsInValid):
+		return ErrClassAuth
+	case errors.Is(err, ErrNotFound):
+		return ErrClassNotFound
+	case errors.Is(err, ErrConflict):
+		return ErrClassConflict
+	case errors.Is(err, ErrRateLimited):
+		return ErrClassRateLimited
+	case errors.Is(err, ErrValidation):
+		return ErrClassValidation
+	case status >= 500:
+		return ErrClassServer
+	case status >= 400:
+		return ErrClassClient
+	}
+	return ErrClassDecode
+}
+
+var (
+	// Path segments that identify individual resources
+	hexIDRegexp   = regexp.MustCompile(`^(?i)[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}$|^(?i)[0-9a-f]{16,}$`)
+	queueIDRegexp = regexp.MustCompile(`^(?i)[a-z]{2}\d-\d+$`)
+	locationRegex = regexp.MustCompile(`^(?i)[a-z]{2}\d$`)
+	serverRegexp  = regexp.MustCompile(`^(?i)[a-z]{2}\d[a-z0-9-]{4,}$`)
+	numberRegexp  = regexp.MustCompile(`^\d+$`)
+)
+
+// pathTemplate replaces the resource identifiers in @path by placeholders, so that
+// requests against the same kind of resource can be aggregated.
+// @accounts: account aliases to replace by "{account}"
+func pathTemplate(path string, accounts ...string) string {
+	var segments = strings.Split(path, "/")
+
+	if idx := strings.IndexByte(path, '?'); idx >= 0 {
+		segments = strings.Split(path[:idx], "/")
+	}
+	for i, seg := range segments {
+		switch {
+		case seg == "":
+		case containsFold(accounts, seg):
+			segments[i] = "{account}"
+		case hexIDRegexp.MatchString(seg), numberRegexp.MatchString(seg), queueIDRegexp.MatchString(seg):
+			segments[i] = "{id}"
+		case locationRegex.MatchString(seg):
+			segments[i] = "{location}"
+		case serverRegexp.MatchString(seg) && strings.ToUpper(seg) == seg:
+			segments[i] = "{server}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// containsFold returns true if @list contains @s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, e := range list {
+		if e != "" && strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// attemptCounterKey is the context key of the per-request attempt counter.
+type attemptCounterKey struct{}
+
+// countingTransport counts the attempts made for each request (including retries).
+type countingTransport struct {
+	http.RoundTripper
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if n, ok := req.Context().Value(attemptCounterKey{}).(*int32); ok {
+		atomic.AddInt32(n, 1)
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+// instrument wraps the request of @req to @family: it calls the OnRequest hooks and
+// returns a function to call the OnResponse hooks once the request has completed.
+func (c *Client) instrument(family EndpointFamily, req *http.Request) (*http.Request, func(status int, err error)) {
+	if len(c.hooks) == 0 {
+		return req, func(int, error) {}
+	}
+
+	var attempts int32
+	var info = RequestInfo{
+		Family:   family,
+		Verb:     req.Method,
+		Path:     req.URL.Path,
+		Template: pathTemplate(req.URL.Path, c.AccountAlias, c.RegisteredAccountAlias()),
+	}
+
+	for _, h := range c.hooks {
+		h.OnRequest(&info)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), attemptCounterKey{}, &attempts))
+	start := time.Now()
+
+	return req, func(status int, err error) {
+		var res = ResponseInfo{
+			RequestInfo: info,
+			StatusCode:  status,
+			Duration:    time.Since(start),
+			Err:         err,
+			ErrorClass:  classifyError(req.Context(), status, err),
+		}
+		if n := int(atomic.LoadInt32(&attempts)); n > 1 {
+			res.Retries = n - 1
+		}
+		for _, h := range c.hooks {
+			h.OnResponse(&res)
+		}
+	}
+}
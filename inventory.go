@@ -0,0 +1,346 @@
+package clcv2
+
+/*
+ * Inventory of groups, servers, networks, public IPs and load balancers across accounts and data centres.
+ */
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Default number of concurrent requests of BuildInventory
+const inventoryWorkers = 8
+
+// InventoryScope identifies the account and data centre that an inventory item belongs to.
+type InventoryScope struct {
+	Account, Location string
+}
+
+func (s InventoryScope) String() string {
+	return s.Account + "/" + s.Location
+}
+
+// InventoryGroup is a hardware group. Its sub-groups are separate inventory items (@Groups is empty).
+type InventoryGroup struct {
+	InventoryScope
+	Group
+
+	// Slash-separated names of the group and its ancestors, starting at the data centre root group
+	Path string
+}
+
+// InventoryServer is a server.
+type InventoryServer struct {
+	InventoryScope
+	Server
+}
+
+// InventoryNetwork is a network.
+type InventoryNetwork struct {
+	InventoryScope
+	Network
+}
+
+// InventoryPublicIP is a public IP address of a server.
+type InventoryPublicIP struct {
+	InventoryScope
+
+	// Name of the server the address belongs to
+	Server string
+
+	// Public address, and the internal address it maps to
+	Public, Internal string
+}
+
+// InventoryLoadBalancer is a shared load balancer.
+type InventoryLoadBalancer struct {
+	InventoryScope
+	LoadBalancer
+}
+
+// InventoryError records a failed query of BuildInventory.
+type InventoryError struct {
+	InventoryScope
+
+	// Query that failed (e.g. "servers")
+	What string
+
+	Err error
+}
+
+func (e *InventoryError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.InventoryScope, e.What, e.Err)
+}
+
+// Inventory is a snapshot of the resources of one or more accounts. Within each category, items are
+// sorted by account, location, and name.
+type Inventory struct {
+	Groups        []InventoryGroup
+	Servers       []InventoryServer
+	Networks      []InventoryNetwork
+	PublicIPs     []InventoryPublicIP
+	LoadBalancers []InventoryLoadBalancer
+
+	// Failed queries: if non-empty, the inventory is incomplete
+	Errors []*InventoryError
+
+	// Time the snapshot was started, and how long it took
+	Taken   time.Time
+	Elapsed time.Duration
+}
+
+// InventoryOptions control BuildInventory.
+type InventoryOptions struct {
+	// Accounts to include. If empty, only the effective account alias of the client is included.
+	// Sub-accounts are not discovered, since the v2 API has no call to enumerate them.
+	Accounts []string
+
+	// Data centres to include. If empty, all data centres of each account are included (see GetLocations).
+	Locations []string
+
+	// Maximum number of concurrent requests (defaults to 8)
+	Workers int
+}
+
+// BuildInventory takes a snapshot of the groups, servers, networks, public IPs and load balancers of the
+// accounts and data centres selected by @opts (which may be nil), querying them concurrently.
+// Failed queries are recorded in the Errors of the result. An error is only returned if @ctx was canceled.
+func (c *Client) BuildInventory(ctx context.Context, opts *InventoryOptions) (*Inventory, error) {
+	var b = &inventoryBuilder{c: c, inv: &Inventory{Taken: time.Now()}}
+	var accounts []string
+
+	if opts == nil {
+		opts = &InventoryOptions{}
+	}
+	if accounts = opts.Accounts; len(accounts) == 0 {
+		accounts = []string{c.AccountAlias}
+	}
+	if b.sem = make(chan struct{}, opts.Workers); opts.Workers <= 0 {
+		b.sem = make(chan struct{}, inventoryWorkers)
+	}
+
+	for _, acct := range accounts {
+		acct := strings.ToUpper(acct)
+
+		b.run(func() {
+			b.account(ctx, c.WithAccount(acct), opts.Locations)
+		})
+	}
+	b.wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	b.inv.sort()
+	b.inv.Elapsed = time.Since(b.inv.Taken)
+	return b.inv, nil
+}
+
+// inventoryBuilder implements BuildInventory.
+type inventoryBuilder struct {
+	c   *Client
+	sem chan struct{} // limits the number of concurrent requests
+	wg  sync.WaitGroup
+
+	mu  sync.Mutex // protects @inv
+	inv *Inventory
+}
+
+// run runs @fn asynchronously.
+func (b *inventoryBuilder) run(fn func()) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		fn()
+	}()
+}
+
+// query performs the request @fn while holding a worker slot, recording failures under @scope/@what.
+// Returns false if the request failed.
+func (b *inventoryBuilder) query(ctx context.Context, scope InventoryScope, what string, fn func() error) bool {
+	select {
+	case b.sem <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+	err := fn()
+	<-b.sem
+
+	if err != nil && ctx.Err() == nil {
+		b.mu.Lock()
+		b.inv.Errors = append(b.inv.Errors, &InventoryError{InventoryScope: scope, What: what, Err: err})
+		b.mu.Unlock()
+	}
+	return err == nil
+}
+
+// account queries the data centres @locations of the account of view @v (all if empty).
+func (b *inventoryBuilder) account(ctx context.Context, v *Client, locations []string) {
+	var scope = InventoryScope{Account: v.AccountAlias}
+
+	if len(locations) == 0 {
+		var dcs []DataCenter
+
+		if !b.query(ctx, scope, "locations", func() (err error) {
+			dcs, err = v.GetLocationsContext(ctx)
+			return err
+		}) {
+			return
+		}
+		for _, dc := range dcs {
+			locations = append(locations, dc.Id)
+		}
+	}
+
+	for _, loc := range locations {
+		scope := InventoryScope{Account: v.AccountAlias, Location: strings.ToUpper(loc)}
+
+		b.run(func() { b.groups(ctx, v, scope) })
+		b.run(func() { b.networks(ctx, v, scope) })
+		b.run(func() { b.loadBalancers(ctx, v, scope) })
+	}
+}
+
+// groups adds the group tree of @scope, and queries the servers in it.
+func (b *inventoryBuilder) groups(ctx context.Context, v *Client, scope InventoryScope) {
+	var root *Group
+
+	if !b.query(ctx, scope, "groups", func() (err error) {
+		root, err = v.GetGroupsContext(ctx, scope.Location)
+		return err
+	}) {
+		return
+	}
+
+	var walk func(g *Group, parentPath string)
+	walk = func(g *Group, parentPath string) {
+		var item = InventoryGroup{InventoryScope: scope, Group: *g, Path: g.Name}
+
+		if parentPath != "" {
+			item.Path = parentPath + "/" + g.Name
+		}
+		item.Groups = nil
+
+		b.mu.Lock()
+		b.inv.Groups = append(b.inv.Groups, item)
+		b.mu.Unlock()
+
+		for _, l := range ExtractLinks(g.Links, "server") {
+			l := l
+			b.run(func() { b.server(ctx, v, scope, l) })
+		}
+		for idx := range g.Groups {
+			walk(&g.Groups[idx], item.Path)
+		}
+	}
+	walk(root, "")
+}
+
+// server adds the server at @link, along with its public IPs.
+func (b *inventoryBuilder) server(ctx context.Context, v *Client, scope InventoryScope, link Link) {
+	var s Server
+
+	if !b.query(ctx, scope, "server "+link.Id, func() (err error) {
+		if link.Href != "" {
+			s, err = v.GetServerByURIContext(ctx, link.Href)
+		} else {
+			s, err = v.GetServerContext(ctx, link.Id)
+		}
+		return err
+	}) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inv.Servers = append(b.inv.Servers, InventoryServer{InventoryScope: scope, Server: s})
+	for _, ip := range s.Details.IpAddresses {
+		if ip.Public != "" {
+			b.inv.PublicIPs = append(b.inv.PublicIPs, InventoryPublicIP{
+				InventoryScope: scope,
+				Server:         s.Name,
+				Public:         ip.Public,
+				Internal:       ip.Internal,
+			})
+		}
+	}
+}
+
+// networks adds the networks of @scope.
+func (b *inventoryBuilder) networks(ctx context.Context, v *Client, scope InventoryScope) {
+	var nets []Network
+
+	if b.query(ctx, scope, "networks", func() (err error) {
+		nets, err = v.GetNetworksContext(ctx, scope.Location, scope.Account)
+		return err
+	}) {
+		b.mu.Lock()
+		for _, n := range nets {
+			b.inv.Networks = append(b.inv.Networks, InventoryNetwork{InventoryScope: scope, Network: n})
+		}
+		b.mu.Unlock()
+	}
+}
+
+// loadBalancers adds the shared load balancers of @scope.
+func (b *inventoryBuilder) loadBalancers(ctx context.Context, v *Client, scope InventoryScope) {
+	var lbs []LoadBalancer
+
+	if b.query(ctx, scope, "load balancers", func() (err error) {
+		lbs, err = v.GetSharedLoadBalancersContext(ctx, scope.Location)
+		return err
+	}) {
+		b.mu.Lock()
+		for _, lb := range lbs {
+			b.inv.LoadBalancers = append(b.inv.LoadBalancers, InventoryLoadBalancer{InventoryScope: scope, LoadBalancer: lb})
+		}
+		b.mu.Unlock()
+	}
+}
+
+// sort sorts the items of @inv by scope and name, since they are collected in random order.
+func (inv *Inventory) sort() {
+	var less = func(a, b InventoryScope, nameA, nameB string) bool {
+		if a != b {
+			return a.String() < b.String()
+		}
+		return nameA < nameB
+	}
+
+	sort.Slice(inv.Groups, func(i, j int) bool {
+		return less(inv.Groups[i].InventoryScope, inv.Groups[j].InventoryScope, inv.Groups[i].Path, inv.Groups[j].Path)
+	})
+	sort.Slice(inv.Servers, func(i, j int) bool {
+		return less(inv.Servers[i].InventoryScope, inv.Servers[j].InventoryScope, inv.Servers[i].Name, inv.Servers[j].Name)
+	})
+	sort.Slice(inv.Networks, func(i, j int) bool {
+		return less(inv.Networks[i].InventoryScope, inv.Networks[j].InventoryScope, inv.Networks[i].Cidr, inv.Networks[j].Cidr)
+	})
+	sort.Slice(inv.PublicIPs, func(i, j int) bool {
+		return less(inv.PublicIPs[i].InventoryScope, inv.PublicIPs[j].InventoryScope, inv.PublicIPs[i].Public, inv.PublicIPs[j].Public)
+	})
+	sort.Slice(inv.LoadBalancers, func(i, j int) bool {
+		return less(inv.LoadBalancers[i].InventoryScope, inv.LoadBalancers[j].InventoryScope, inv.LoadBalancers[i].Name, inv.LoadBalancers[j].Name)
+	})
+	sort.Slice(inv.Errors, func(i, j int) bool {
+		return less(inv.Errors[i].InventoryScope, inv.Errors[j].InventoryScope, inv.Errors[i].What, inv.Errors[j].What)
+	})
+}
+
+// Err returns an error summarizing the failed queries of @inv, or nil if the inventory is complete.
+func (inv *Inventory) Err() error {
+	switch len(inv.Errors) {
+	case 0:
+		return nil
+	case 1:
+		return inv.Errors[0]
+	}
+	return errors.Errorf("inventory incomplete: %d queries failed, first: %s", len(inv.Errors), inv.Errors[0])
+}
@@ -0,0 +1,272 @@
+package clcv2
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrJobFailed is returned (wrapped) by Job.Wait when the job completes with status %Failed.
+var ErrJobFailed = errors.New("job failed")
+
+// Job is an asynchronous operation, regardless of the queue mechanism used by the API to track it
+// (status IDs, NIC-change operations, network claims, or LBaaS requests).
+//
+// Asynchronous operations have Job-returning variants, both those that return a status ID (e.g. CreateServerJob,
+// DeleteServerJob, PowerOnServerJob, ArchiveServerJob, CreateSnapshotJob, DeleteGroupJob) and those with their
+// own queue (ServerAddNicJob, ServerDelNicJob, ClaimNetworkJob, CreateLbInstanceJob). StatusJob turns the status
+// IDs of the remaining operations (e.g. ServerSetCpus, AddPublicIPAddress) or of batch operations into a Job.
+type Job interface {
+	// ID identifies the job within its queue.
+	ID() string
+
+	// Status returns the most recently polled status (%Unknown if not polled yet).
+	Status() QueueStatus
+
+	// Poll queries the current status of the job once.
+	Poll(ctx context.Context) (QueueStatus, error)
+
+	// Wait polls the job until it completes. It returns nil if the job succeeded, an error
+	// wrapping %ErrJobFailed if it failed, or an error if polling failed or @ctx expired.
+	Wait(ctx context.Context) error
+
+	// OnProgress registers @cb to be called whenever polling observes a status change.
+	OnProgress(cb func(job Job, status QueueStatus))
+}
+
+// Default poll intervals and timeouts of the various job types (see also WithPollInterval).
+const (
+	statusPollInterval = 1 * time.Second
+	nicPollInterval    = 1 * time.Second
+	claimPollInterval  = 5 * time.Second // claiming a network may take several minutes
+	lbaasPollInterval  = 2 * time.Second
+
+	nicTimeout = 3 * time.Minute
+)
+
+// job implements Job on top of a function that polls the status.
+type job struct {
+	// Job type, for use in error messages
+	kind string
+
+	id   string
+	poll func(ctx context.Context) (QueueStatus, error)
+
+	// Time between polls, and optional upper bound on Wait()
+	interval, timeout time.Duration
+
+	// The Job passed to progress callbacks (the type embedding this job)
+	self Job
+
+	mu       sync.Mutex
+	status   QueueStatus
+	progress []func(Job, QueueStatus)
+}
+
+// newJob returns a job of @kind with @id, whose status is queried via @poll.
+// The poll interval is @interval, unless overridden via WithPollInterval.
+func (c *Client) newJob(kind, id string, interval time.Duration, poll func(ctx context.Context) (QueueStatus, error)) *job {
+	if c.pollInterval > 0 {
+		interval = c.pollInterval
+	}
+	j := &job{kind: kind, id: id, poll: poll, interval: interval, status: Unknown}
+	j.self = j
+	return j
+}
+
+// ID implements Job.
+func (j *job) ID() string {
+	return j.id
+}
+
+// Status implements Job.
+func (j *job) Status() QueueStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// OnProgress implements Job.
+func (j *job) OnProgress(cb func(Job, QueueStatus)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.progress = append(j.progress, cb)
+}
+
+// Poll implements Job.
+func (j *job) Poll(ctx context.Context) (QueueStatus, error) {
+	status, err := j.poll(ctx)
+	if err != nil {
+		return Unknown, errors.Errorf("failed to query %s %s: %s", j.kind, j.id, err)
+	}
+
+	j.mu.Lock()
+	changed, progress := status != j.status, j.progress
+	j.status = status
+	j.mu.Unlock()
+
+	if changed {
+		for _, cb := range progress {
+			cb(j.self, status)
+		}
+	}
+	return status, nil
+}
+
+// Wait implements Job.
+func (j *job) Wait(ctx context.Context) error {
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
+
+	for {
+		status, err := j.Poll(ctx)
+		if err == nil {
+			switch status {
+			case Succeeded:
+				return nil
+			case Failed:
+				return errors.Wrapf(ErrJobFailed, "%s %s", j.kind, j.id)
+			}
+			err = sleepContext(ctx, j.interval)
+		}
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded && j.timeout > 0 {
+				return errors.Errorf("%s %s timed out after %s", j.kind, j.id, j.timeout)
+			}
+			return err
+		}
+	}
+}
+
+// StatusJob returns the Job tracking the queue status @statusID, as returned by most asynchronous
+// operations (e.g. power operations, CreateServer, DeleteGroup).
+// The completion of the job is journaled once, even if it is polled concurrently.
+func (c *Client) StatusJob(statusID string) Job {
+	return c.statusJob(statusID)
+}
+
+// statusJob implements StatusJob.
+func (c *Client) statusJob(statusID string) *job {
+	var journaled sync.Once
+
+	return c.newJob("status", statusID, statusPollInterval, func(ctx context.Context) (QueueStatus, error) {
+		status, err := c.GetStatusContext(ctx, statusID)
+		if err == nil && (status == Succeeded || status == Failed) {
+			journaled.Do(func() {
+				c.journalComplete(statusID, status)
+				c.invalidateCache(c.endpoints.API + "/v2/operations/" + c.AccountAlias)
+			})
+		}
+		return status, err
+	})
+}
+
+// statusJobOf returns the StatusJob of @statusID, unless the operation that returned @statusID failed with @err.
+func (c *Client) statusJobOf(statusID string, err error) (Job, error) {
+	if err != nil {
+		return nil, err
+	}
+	return c.StatusJob(statusID), nil
+}
+
+// completedJob returns a Job of @kind with @id that has already succeeded, for operations that
+// did not need to be queued (e.g. the creation of a server that turned out to exist already).
+func (c *Client) completedJob(kind, id string) Job {
+	j := c.newJob(kind, id, statusPollInterval, func(context.Context) (QueueStatus, error) {
+		return Succeeded, nil
+	})
+	j.status = Succeeded
+	return j
+}
+
+// nicJob returns the Job tracking the secondary-NIC change @res.
+func (c *Client) nicJob(res ChangeNicResponse) Job {
+	j := c.newJob("NIC change", res.OperationId, nicPollInterval, func(ctx context.Context) (QueueStatus, error) {
+		var s ChangeNicStatus
+
+		err := c.getCLCResponse(ctx, "GET", res.Uri, nil, &s)
+		return s.Status, err
+	})
+	j.timeout = nicTimeout
+	return j
+}
+
+// NetworkClaim is the Job of a network claim (see ClaimNetworkJob).
+type NetworkClaim struct {
+	*job
+
+	mu        sync.Mutex
+	networkID string
+}
+
+// NetworkID returns the ID of the claimed network once the claim has succeeded, "" before.
+func (n *NetworkClaim) NetworkID() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.networkID
+}
+
+// networkClaim returns the Job of the network claim @id, whose status is available at @uri.
+func (c *Client) networkClaim(id, uri string) *NetworkClaim {
+	var n = new(NetworkClaim)
+
+	n.job = c.newJob("claim-network", id, claimPollInterval, func(ctx context.Context) (QueueStatus, error) {
+		var cs claimNetworkStatus
+
+		if err := c.getCLCResponse(ctx, "GET", uri, nil, &cs); err != nil {
+			return Unknown, err
+		} else if cs.Status != Succeeded {
+			return cs.Status, nil
+		}
+		for _, link := range cs.Summary.Links {
+			if link.Rel == "network" {
+				n.mu.Lock()
+				n.networkID = link.Id
+				n.mu.Unlock()
+				return cs.Status, nil
+			}
+		}
+		return Unknown, errors.Errorf("claim-network #%d succeeded, but returned no network ID", cs.Summary.BlueprintID)
+	})
+	n.job.self = n
+	return n
+}
+
+// LbCreateJob is the Job of a 'Create LB instance' request (see CreateLbInstanceJob).
+type LbCreateJob struct {
+	*job
+
+	mu      sync.Mutex
+	request LbCreateRequest
+}
+
+// Request returns the most recently polled state of the request.
+func (l *LbCreateJob) Request() LbCreateRequest {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.request
+}
+
+// lbCreateJob returns the Job of the 'Create LB instance' request @req in @dc.
+func (c *Client) lbCreateJob(dc string, req LbCreateRequest) *LbCreateJob {
+	var l = &LbCreateJob{request: req}
+
+	l.job = c.newJob("LBaaS request", req.ID.String(), lbaasPollInterval, func(ctx context.Context) (QueueStatus, error) {
+		req, err := c.GetLbCreateRequestContext(ctx, dc, req.ID.String())
+		if err != nil {
+			return Unknown, err
+		}
+		l.mu.Lock()
+		l.request = req
+		l.mu.Unlock()
+		return req.queueStatus(), nil
+	})
+	l.job.self = l
+	return l
+}
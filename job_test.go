@@ -0,0 +1,137 @@
+package clcv2_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/grrtrr/clcv2/clcv2test"
+)
+
+// A status job that is polled concurrently journals its completion exactly once.
+func TestStatusJobConcurrentPoll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clcv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var f = clcv2test.NewFakeServer("ABCD", "WA1")
+	var journal = path.Join(dir, "jobs.jsonl")
+
+	defer f.Close()
+
+	client, err := f.Client(clcv2.WithJournal(clcv2.NewJournal(journal)))
+	if err != nil {
+		t.Fatalf("login failed: %s", err)
+	}
+	name, err := f.AddServer(f.RootGroup("WA1"), "WEB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statusID, err := client.PowerOffServer(name)
+	if err != nil {
+		t.Fatalf("PowerOffServer failed: %s", err)
+	}
+	f.CompleteJobs()
+
+	var job = client.StatusJob(statusID)
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if status, err := job.Poll(client.Context()); err != nil {
+				t.Errorf("Poll failed: %s", err)
+			} else if status != clcv2.Succeeded {
+				t.Errorf("expected status %s, got %s", clcv2.Succeeded, status)
+			}
+		}()
+	}
+	wg.Wait()
+
+	content, err := ioutil.ReadFile(journal)
+	if err != nil {
+		t.Fatalf("failed to read journal: %s", err)
+	}
+	// One line on submission, one on completion
+	if n := bytes.Count(content, []byte("\n")); n != 2 {
+		t.Errorf("expected 2 journal lines, got %d:\n%s", n, content)
+	}
+}
+
+// The Job-returning variants of status-ID operations track the operation until it completes.
+func TestStatusJobVariants(t *testing.T) {
+	var f = clcv2test.NewFakeServer("ABCD", "WA1")
+	defer f.Close()
+
+	client, err := f.Client(clcv2.WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatalf("login failed: %s", err)
+	}
+	groupID, err := f.AddGroup(f.RootGroup("WA1"), "Web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, job, err := client.CreateServerJob(&clcv2.CreateServerReq{
+		Name:           "WEB",
+		GroupId:        groupID,
+		SourceServerId: "UBUNTU-16-64-TEMPLATE",
+		Cpu:            1,
+		MemoryGB:       2,
+		Type:           "standard",
+	})
+	if err != nil {
+		t.Fatalf("CreateServerJob failed: %s", err)
+	} else if err = job.Wait(client.Context()); err != nil {
+		t.Fatalf("server creation failed: %s", err)
+	}
+
+	group, err := client.GetGroup(groupID)
+	if err != nil {
+		t.Fatalf("GetGroup failed: %s", err)
+	}
+	servers := clcv2.ExtractLinks(group.Links, "server")
+	if len(servers) != 1 {
+		t.Fatalf("expected 1 server, got %d", len(servers))
+	}
+	name := servers[0].Id
+
+	for _, op := range []struct {
+		desc       string
+		submit     func(string) (clcv2.Job, error)
+		powerState string
+	}{
+		{"power off", client.PowerOffServerJob, "stopped"},
+		{"power on", client.PowerOnServerJob, "started"},
+		{"pause", client.PauseServerJob, "paused"},
+	} {
+		if job, err := op.submit(name); err != nil {
+			t.Errorf("%s failed: %s", op.desc, err)
+		} else if err = job.Wait(client.Context()); err != nil {
+			t.Errorf("%s job %s failed: %s", op.desc, job.ID(), err)
+		} else if job.Status() != clcv2.Succeeded {
+			t.Errorf("%s: expected status %s, got %s", op.desc, clcv2.Succeeded, job.Status())
+		} else if s, _ := f.ServerState(name); s.Details.PowerState != op.powerState {
+			t.Errorf("%s: expected power state %q, got %q", op.desc, op.powerState, s.Details.PowerState)
+		}
+	}
+
+	if job, err := client.DeleteServerJob(name); err != nil {
+		t.Fatalf("DeleteServerJob failed: %s", err)
+	} else if err = job.Wait(client.Context()); err != nil {
+		t.Fatalf("server deletion failed: %s", err)
+	} else if _, ok := f.ServerState(name); ok {
+		t.Errorf("server %s was not deleted", name)
+	}
+	if _, err := client.DeleteServerJob(name); err == nil {
+		t.Errorf("expected the Job variant to fail along with the operation")
+	}
+}
@@ -0,0 +1,247 @@
+package clcv2
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Name of the job journal in CLC_HOME
+	journalName = "jobs.jsonl"
+
+	// Once the journal has more lines than this, it is compacted to the most recent %journalKeep jobs
+	// when it is opened
+	journalMaxLines = 5000
+	journalKeep     = 1000
+)
+
+// JournalEntry records a queued operation.
+type JournalEntry struct {
+	// Queue status ID of the operation
+	StatusID string `json:"statusId"`
+
+	// HTTP verb and path of the request (without the account alias), e.g. "POST operations/servers/powerOn"
+	Operation string `json:"operation"`
+
+	// Server or group the operation applies to, if known
+	Target string `json:"target,omitempty"`
+
+	// Account alias the operation was submitted under
+	Account string `json:"account"`
+
+	// Time the operation was queued
+	Submitted time.Time `json:"submitted"`
+
+	// Most recently observed status (%Unknown until polled to completion)
+	Status QueueStatus `json:"status"`
+
+	// Time the final status was observed
+	Completed *time.Time `json:"completed,omitempty"`
+}
+
+// Pending returns true if @e has not been observed to complete.
+func (e *JournalEntry) Pending() bool {
+	return e.Status != Succeeded && e.Status != Failed
+}
+
+// journalUpdate records the final status of an operation; it is the subset of JournalEntry
+// that changes on completion.
+type journalUpdate struct {
+	StatusID  string      `json:"statusId"`
+	Status    QueueStatus `json:"status"`
+	Completed *time.Time  `json:"completed,omitempty"`
+}
+
+// Journal is an append-only log of queued operations and their final status, shared by
+// concurrent processes. Each line of the file is either a JournalEntry or, lacking an
+// Operation, an update of the Status of the earlier entry with the same StatusID.
+type Journal struct {
+	path string
+}
+
+// OpenJournal returns the job journal in CLC_HOME.
+func OpenJournal() *Journal {
+	return NewJournal(path.Join(GetClcHome(), journalName))
+}
+
+// NewJournal returns the job journal stored in @path, compacting it if it has grown too large.
+// Compaction is best-effort: if it fails, the journal is used as is.
+func NewJournal(path string) *Journal {
+	var j = &Journal{path: path}
+
+	j.compact()
+	return j
+}
+
+// Record adds @e to the journal.
+func (j *Journal) Record(e JournalEntry) error {
+	if e.Status == "" {
+		e.Status = Unknown
+	}
+	return j.append(e)
+}
+
+// Complete records the final @status of the operation @statusID.
+func (j *Journal) Complete(statusID string, status QueueStatus) error {
+	var now = time.Now()
+
+	return j.append(journalUpdate{StatusID: statusID, Status: status, Completed: &now})
+}
+
+// Entries returns the current state of all operations in the journal, most recently submitted first.
+func (j *Journal) Entries() ([]JournalEntry, error) {
+	entries, _, err := j.load()
+	return entries, err
+}
+
+// append appends the JSON encoding of @v as a line to the journal file.
+func (j *Journal) append(v interface{}) error {
+	enc, err := json.Marshal(v)
+	if err != nil {
+		return errors.Errorf("failed to serialize journal entry: %s", err)
+	}
+
+	unlock, err := lockFile(j.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.MkdirAll(path.Dir(j.path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Errorf("failed to open job journal: %s", err)
+	}
+	if _, err = f.Write(append(enc, '\n')); err != nil {
+		f.Close()
+		return errors.Errorf("failed to write job journal: %s", err)
+	}
+	return f.Close()
+}
+
+// compact rewrites the journal with the most recent %journalKeep entries, once it has more than %journalMaxLines lines.
+func (j *Journal) compact() error {
+	unlock, err := lockFile(j.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, lines, err := j.load()
+	if err != nil || lines <= journalMaxLines {
+		return err
+	}
+	if len(entries) > journalKeep {
+		entries = entries[:journalKeep]
+	}
+
+	var buf bytes.Buffer
+	for i := len(entries) - 1; i >= 0; i-- {
+		enc, err := json.Marshal(&entries[i])
+		if err != nil {
+			return errors.Errorf("failed to serialize journal entry: %s", err)
+		}
+		buf.Write(append(enc, '\n'))
+	}
+	return writeFileAtomic(j.path, buf.Bytes(), 0600)
+}
+
+// load reads the journal, returning the merged entries (most recently submitted first) and the number of lines.
+func (j *Journal) load() (entries []JournalEntry, lines int, err error) {
+	var byID = make(map[string]int)
+
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	} else if err != nil {
+		return nil, 0, errors.Errorf("failed to open job journal: %s", err)
+	}
+	defer f.Close()
+
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		var e JournalEntry
+
+		lines++
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.StatusID == "" {
+			continue // skip partially written or corrupt lines
+		}
+		if i, ok := byID[e.StatusID]; !ok && e.Operation == "" {
+			continue // update of an unknown entry
+		} else if ok && e.Operation == "" {
+			entries[i].Status, entries[i].Completed = e.Status, e.Completed
+		} else if ok {
+			entries[i] = e
+		} else {
+			byID[e.StatusID] = len(entries)
+			entries = append(entries, e)
+		}
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].Submitted.After(entries[b].Submitted)
+	})
+	return entries, lines, nil
+}
+
+// journalSubmit records the queued operation @statusID, submitted via @verb and @path, on @target (may be empty).
+func (c *Client) journalSubmit(verb, path, target, statusID string) {
+	if c.journal == nil || statusID == "" {
+		return
+	}
+
+	var op, pathTarget = c.journalOperation(path)
+	if target == "" {
+		target = pathTarget
+	}
+	if err := c.journal.Record(JournalEntry{
+		StatusID:  statusID,
+		Operation: verb + " " + op,
+		Target:    target,
+		Account:   c.AccountAlias,
+		Submitted: time.Now(),
+	}); err != nil {
+		c.log(LevelWarn, "failed to record job in journal", Fields{"status_id": statusID, "error": err})
+	}
+}
+
+// journalComplete records the final @status of @statusID.
+func (c *Client) journalComplete(statusID string, status QueueStatus) {
+	if c.journal == nil {
+		return
+	}
+	if err := c.journal.Complete(statusID, status); err != nil {
+		c.log(LevelWarn, "failed to update job journal", Fields{"status_id": statusID, "error": err})
+	}
+}
+
+// journalOperation returns the description of the request @path for the journal, along with
+// the server or group it applies to (if it can be derived from @path).
+func (c *Client) journalOperation(reqPath string) (op, target string) {
+	var parts = strings.Split(strings.Trim(reqPath, "/"), "/")
+	var out []string
+
+	for i := 0; i < len(parts); i++ {
+		switch {
+		case i == 0 && strings.HasPrefix(parts[i], "v2"):
+		case strings.EqualFold(parts[i], c.AccountAlias):
+			// The item following the alias identifies servers/groups, e.g. /v2/servers/{alias}/{serverId}.
+			if i > 0 && (parts[i-1] == "servers" || parts[i-1] == "groups") && i+1 < len(parts) {
+				target = parts[i+1]
+				i++
+			}
+		default:
+			out = append(out, parts[i])
+		}
+	}
+	return strings.Join(out, "/"), target
+}
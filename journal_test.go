@@ -0,0 +1,141 @@
+package clcv2
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+// journalLines returns the number of lines in the journal file @path.
+func journalLines(t *testing.T, path string) (lines int) {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		lines++
+	}
+	return lines
+}
+
+// Record and Complete append a line each, and completions of unknown entries are ignored.
+func TestJournalComplete(t *testing.T) {
+	withClcHome(t, func(dir string) {
+		var j = NewJournal(path.Join(dir, journalName))
+		var start = time.Now()
+
+		for i, id := range []string{"wa1-1", "wa1-2"} {
+			if err := j.Record(JournalEntry{StatusID: id, Operation: "POST operations/servers/powerOn", Submitted: start.Add(time.Duration(i) * time.Second)}); err != nil {
+				t.Fatalf("Record failed: %s", err)
+			}
+		}
+		for id, status := range map[string]QueueStatus{"wa1-1": Succeeded, "wa1-unknown": Failed} {
+			if err := j.Complete(id, status); err != nil {
+				t.Fatalf("Complete failed: %s", err)
+			}
+		}
+		if n := journalLines(t, j.path); n != 4 {
+			t.Errorf("expected 4 lines, got %d", n)
+		}
+
+		entries, err := j.Entries()
+		if err != nil {
+			t.Fatalf("Entries failed: %s", err)
+		} else if len(entries) != 2 {
+			t.Fatalf("expected 2 entries, got %+v", entries)
+		}
+		if e := entries[0]; e.StatusID != "wa1-2" || e.Status != Unknown || !e.Pending() || e.Completed != nil {
+			t.Errorf("unexpected pending entry %+v", e)
+		}
+		if e := entries[1]; e.StatusID != "wa1-1" || e.Status != Succeeded || e.Completed == nil || e.Operation == "" || !e.Submitted.Equal(start) {
+			t.Errorf("unexpected completed entry %+v", e)
+		}
+	})
+}
+
+// A journal that has grown too large is compacted to the most recent entries when it is opened.
+func TestJournalCompaction(t *testing.T) {
+	withClcHome(t, func(dir string) {
+		var file = path.Join(dir, journalName)
+		var start = time.Now()
+		var lines []byte
+
+		// One line per job, plus a completion line for each job but the most recent one.
+		for i := 0; i < journalMaxLines/2+1; i++ {
+			var id = fmt.Sprintf("wa1-%d", i)
+
+			enc, _ := json.Marshal(JournalEntry{StatusID: id, Operation: "POST operations/servers/reboot", Status: Unknown, Submitted: start.Add(time.Duration(i) * time.Second)})
+			lines = append(append(lines, enc...), '\n')
+			if i < journalMaxLines/2 {
+				enc, _ = json.Marshal(journalUpdate{StatusID: id, Status: Succeeded})
+				lines = append(append(lines, enc...), '\n')
+			}
+		}
+		if err := ioutil.WriteFile(file, lines, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Appending does not compact.
+		var j = &Journal{path: file}
+		if err := j.Record(JournalEntry{StatusID: "wa1-new", Operation: "POST operations/servers/reboot", Submitted: start.Add(-time.Hour)}); err != nil {
+			t.Fatalf("Record failed: %s", err)
+		} else if n := journalLines(t, file); n != journalMaxLines+2 {
+			t.Fatalf("expected %d lines, got %d", journalMaxLines+2, n)
+		}
+
+		j = NewJournal(file)
+		if n := journalLines(t, file); n != journalKeep {
+			t.Errorf("expected %d lines after compaction, got %d", journalKeep, n)
+		}
+		entries, err := j.Entries()
+		if err != nil {
+			t.Fatalf("Entries failed: %s", err)
+		} else if len(entries) != journalKeep {
+			t.Fatalf("expected %d entries, got %d", journalKeep, len(entries))
+		}
+		if e := entries[0]; e.StatusID != fmt.Sprintf("wa1-%d", journalMaxLines/2) || !e.Pending() {
+			t.Errorf("unexpected most recent entry %+v", e)
+		}
+		if e := entries[1]; e.Status != Succeeded || e.Operation == "" {
+			t.Errorf("expected the completion to be kept, got %+v", e)
+		}
+
+		// Compacted journals are left alone.
+		if info, err := os.Stat(file); err != nil {
+			t.Fatal(err)
+		} else if NewJournal(file); journalLines(t, file) != journalKeep {
+			t.Errorf("journal was compacted again")
+		} else if info2, _ := os.Stat(file); !os.SameFile(info, info2) {
+			t.Errorf("journal was rewritten")
+		}
+	})
+}
+
+// Only operations that returned a status ID are recorded.
+func TestJournalSubmit(t *testing.T) {
+	withClcHome(t, func(dir string) {
+		var c = &Client{journal: NewJournal(path.Join(dir, journalName))}
+
+		c.AccountAlias = "ABCD"
+		c.journalSubmit("POST", "/v2/operations/ABCD/servers/powerOn", "", "")
+		if _, err := os.Stat(c.journal.path); !os.IsNotExist(err) {
+			t.Fatalf("expected no journal for an operation without status ID, got %v", err)
+		}
+
+		c.journalSubmit("DELETE", "/v2/servers/ABCD/WA1ABCDWEB01", "", "wa1-1")
+		if entries, err := c.journal.Entries(); err != nil {
+			t.Fatalf("Entries failed: %s", err)
+		} else if len(entries) != 1 {
+			t.Fatalf("expected 1 entry, got %+v", entries)
+		} else if e := entries[0]; e.Operation != "DELETE servers" || e.Target != "WA1ABCDWEB01" || e.Account != "ABCD" || e.Status != Unknown {
+			t.Errorf("unexpected entry %+v", e)
+		}
+	})
+}
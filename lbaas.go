@@ -6,13 +6,15 @@ package clcv2
  */
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	uuid "github.com/satori/go.uuid"
 )
 
-// This service uses a different API endpoint
+// This service uses a different API endpoint (default value of Endpoints.LBaaS)
 const lbaasBaseUrl = "https://api.loadbalancer.ctl.io"
 
 // LbCreateRequest represents CLCv2 load balancer data
@@ -41,21 +43,55 @@ type LbCreateRequest struct {
 // @desc:   textual description of the load balancer
 // @dc:     location alias of the data centre in which to create the load balancer
 func (c *Client) CreateLbInstance(name, desc, dc string) (req LbCreateRequest, err error) {
+	return c.CreateLbInstanceContext(c.ctx, name, desc, dc)
+}
+
+// CreateLbInstanceContext is like CreateLbInstance, using @ctx for cancellation.
+func (c *Client) CreateLbInstanceContext(ctx context.Context, name, desc, dc string) (req LbCreateRequest, err error) {
 	var path = fmt.Sprintf("/%s/%s/loadbalancers", c.AccountAlias, dc)
 
-	return req, c.getLbResponse("POST", path, struct {
+	return req, c.getLbResponse(ctx, "POST", path, struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 	}{name, desc}, &req)
 }
 
+// CreateLbInstanceJob is like CreateLbInstance, but returns a Job that tracks the request.
+func (c *Client) CreateLbInstanceJob(name, desc, dc string) (*LbCreateJob, error) {
+	return c.CreateLbInstanceJobContext(c.ctx, name, desc, dc)
+}
+
+// CreateLbInstanceJobContext is like CreateLbInstanceJob, using @ctx for cancellation.
+func (c *Client) CreateLbInstanceJobContext(ctx context.Context, name, desc, dc string) (*LbCreateJob, error) {
+	req, err := c.CreateLbInstanceContext(ctx, name, desc, dc)
+	if err != nil {
+		return nil, err
+	}
+	return c.lbCreateJob(dc, req), nil
+}
+
 // GetLbCreateRequest retrieves the current status of the 'Create LB instance' request @id.
 // @dc: location alias of the data centre associated with @id
 // @id: LBaaS instance UUID
 func (c *Client) GetLbCreateRequest(dc, id string) (req LbCreateRequest, err error) {
+	return c.GetLbCreateRequestContext(c.ctx, dc, id)
+}
+
+// GetLbCreateRequestContext is like GetLbCreateRequest, using @ctx for cancellation.
+func (c *Client) GetLbCreateRequestContext(ctx context.Context, dc, id string) (req LbCreateRequest, err error) {
 	var path = fmt.Sprintf("/%s/%s/loadbalancers/requests/%s", c.AccountAlias, dc, id)
 
-	return req, c.getLbResponse("GET", path, nil, &req)
+	return req, c.getLbResponse(ctx, "GET", path, nil, &req)
+}
+
+// queueStatus maps the state of @r onto a QueueStatus.
+func (r LbCreateRequest) queueStatus() QueueStatus {
+	if strings.EqualFold(r.Status, "FAILED") {
+		return Failed
+	} else if r.Completed == nil {
+		return Executing
+	}
+	return Succeeded
 }
 
 // LbInstance represents an LBaaS instance
@@ -96,20 +132,30 @@ type LbInstance struct {
 
 // GetLbInstances returns the list all LBaaS instances in the data center @dc.
 func (c *Client) GetLbInstances(dc string) ([]LbInstance, error) {
+	return c.GetLbInstancesContext(c.ctx, dc)
+}
+
+// GetLbInstancesContext is like GetLbInstances, using @ctx for cancellation.
+func (c *Client) GetLbInstancesContext(ctx context.Context, dc string) ([]LbInstance, error) {
 	var path = fmt.Sprintf("/%s/%s/loadbalancers", c.AccountAlias, dc)
 	var result struct {
 		Values []LbInstance
 	}
 
-	return result.Values, c.getLbResponse("GET", path, nil, &result)
+	return result.Values, c.getLbResponse(ctx, "GET", path, nil, &result)
 }
 
 // DeleteLbInstance deletes the load balancer @id in @dc
 // @id: UUID of the load balancer to delete
 // @dc: location alias of the data centre the load balancer resides in
 func (c *Client) DeleteLbInstance(id, dc string) error {
+	return c.DeleteLbInstanceContext(c.ctx, id, dc)
+}
+
+// DeleteLbInstanceContext is like DeleteLbInstance, using @ctx for cancellation.
+func (c *Client) DeleteLbInstanceContext(ctx context.Context, id, dc string) error {
 	var path = fmt.Sprintf("/%s/%s/loadbalancers/%s", c.AccountAlias, dc, id)
-	return c.getLbResponse("DELETE", path, nil, nil)
+	return c.getLbResponse(ctx, "DELETE", path, nil, nil)
 }
 
 // LbEpochSeconds is the custom date/time format used by the LBaaS API.
@@ -123,7 +169,7 @@ func (l LbEpochSeconds) String() string {
 	return l.Time().String()
 }
 
-// getLbResponse is like getCLCResponse but hits the LBaaS API base URL instead.
-func (c *Client) getLbResponse(verb, path string, reqModel, resModel interface{}) error {
-	return c.getResponse(lbaasBaseUrl+path, verb, reqModel, resModel)
+// getLbResponse is like getCLCResponse but hits the LBaaS API endpoint instead.
+func (c *Client) getLbResponse(ctx context.Context, verb, path string, reqModel, resModel interface{}) error {
+	return c.getResponse(ctx, FamilyLBaaS, c.endpoints.LBaaS+path, verb, reqModel, resModel)
 }
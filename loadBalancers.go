@@ -4,7 +4,10 @@ package clcv2
  * Load Balancers
  */
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // LoadBalancerStatus reflects the current state of a shared load balancer
 type LoadBalancerStatus string
@@ -81,8 +84,13 @@ type LoadBalancerNode struct {
 // GetSharedLoadBalancers returns the list of shared load balancers for a given account and data center.
 // @dc: location alias of data centre to query
 func (c *Client) GetSharedLoadBalancers(dc string) (lb []LoadBalancer, err error) {
+	return c.GetSharedLoadBalancersContext(c.ctx, dc)
+}
+
+// GetSharedLoadBalancersContext is like GetSharedLoadBalancers, using @ctx for cancellation.
+func (c *Client) GetSharedLoadBalancersContext(ctx context.Context, dc string) (lb []LoadBalancer, err error) {
 	path := fmt.Sprintf("/v2/sharedLoadBalancers/%s/%s", c.AccountAlias, dc)
-	err = c.getCLCResponse("GET", path, nil, &lb)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &lb)
 	return lb, err
 }
 
@@ -92,8 +100,13 @@ func (c *Client) GetSharedLoadBalancers(dc string) (lb []LoadBalancer, err error
 // @active: whether to create the load balancer in 'enabled' state, one of "enabled" or "disabled"
 // @dc:     location alias of the data centre in which to create the load balancer
 func (c *Client) CreateSharedLoadBalancer(name, desc, active, dc string) (lb LoadBalancer, err error) {
+	return c.CreateSharedLoadBalancerContext(c.ctx, name, desc, active, dc)
+}
+
+// CreateSharedLoadBalancerContext is like CreateSharedLoadBalancer, using @ctx for cancellation.
+func (c *Client) CreateSharedLoadBalancerContext(ctx context.Context, name, desc, active, dc string) (lb LoadBalancer, err error) {
 	path := fmt.Sprintf("/v2/sharedLoadBalancers/%s/%s", c.AccountAlias, dc)
-	err = c.getCLCResponse("POST", path, struct {
+	err = c.getCLCResponse(ctx, "POST", path, struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 		Status      string `json:"status"`
@@ -105,6 +118,11 @@ func (c *Client) CreateSharedLoadBalancer(name, desc, active, dc string) (lb Loa
 // @id: ID of the load balancer to delete
 // @dc: location alias of the data centre the load balancer resides in
 func (c *Client) DeleteSharedLoadBalancer(id, dc string) error {
+	return c.DeleteSharedLoadBalancerContext(c.ctx, id, dc)
+}
+
+// DeleteSharedLoadBalancerContext is like DeleteSharedLoadBalancer, using @ctx for cancellation.
+func (c *Client) DeleteSharedLoadBalancerContext(ctx context.Context, id, dc string) error {
 	path := fmt.Sprintf("/v2/sharedLoadBalancers/%s/%s/%s", c.AccountAlias, dc, id)
-	return c.getCLCResponse("DELETE", path, nil, nil)
+	return c.getCLCResponse(ctx, "DELETE", path, nil, nil)
 }
@@ -0,0 +1,130 @@
+package clcv2
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogLevel is the severity of a log entry.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota // request/response details
+	LevelInfo                  // retries, re-authentication
+	LevelWarn                  // requests that are given up on
+	LevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// Fields are the structured key/value pairs of a log entry. Per-request entries carry
+// "request_id", "verb" and "path", and responses additionally "status" and "duration".
+type Fields map[string]interface{}
+
+// String formats @f as space-separated key=value pairs, in key order.
+func (f Fields) String() string {
+	var keys = make([]string, 0, len(f))
+	var res []string
+
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		res = append(res, fmt.Sprintf("%s=%v", k, f[k]))
+	}
+	return strings.Join(res, " ")
+}
+
+// Logger is a levelled, structured logger. Messages and the values of Fields (strings, errors,
+// fmt.Stringers, and structs or maps holding secret fields) pass through the redaction layer
+// (see RedactBody) before they reach the Logger.
+type Logger interface {
+	Log(level LogLevel, msg string, fields Fields)
+}
+
+// LoggerFunc adapts a function to the Logger interface.
+type LoggerFunc func(level LogLevel, msg string, fields Fields)
+
+// Log implements Logger.
+func (f LoggerFunc) Log(level LogLevel, msg string, fields Fields) {
+	f(level, msg, fields)
+}
+
+// NewLogrusLogger returns a Logger that logs to @l, using logrus levels and fields.
+func NewLogrusLogger(l logrus.FieldLogger) Logger {
+	return LoggerFunc(func(level LogLevel, msg string, fields Fields) {
+		entry := l.WithFields(logrus.Fields(fields))
+
+		switch level {
+		case LevelDebug:
+			entry.Debug(msg)
+		case LevelInfo:
+			entry.Info(msg)
+		case LevelWarn:
+			entry.Warn(msg)
+		default:
+			entry.Error(msg)
+		}
+	})
+}
+
+// log sends @msg to the Logger of @c (see WithLeveledLogger). If there is none, it falls back
+// to @c.Log, where debug entries are only printed in debug mode.
+func (c *Client) log(level LogLevel, msg string, fields Fields) {
+	if c.logger == nil && (c.Log == nil || (level == LevelDebug && !c.debug)) {
+		return
+	}
+
+	msg, fields = redactString(msg), redactFields(fields)
+	if c.logger != nil {
+		c.logger.Log(level, msg, fields)
+	} else if len(fields) == 0 {
+		c.Log.Printf("%s", msg)
+	} else {
+		c.Log.Printf("%s [%s]", msg, fields)
+	}
+}
+
+// requestIDKey is the context key of the per-request ID.
+type requestIDKey struct{}
+
+// newRequestID returns a new ID to correlate the log entries of a single request.
+// IDs consist of a per-client prefix and a sequence number.
+func (c *Client) newRequestID() string {
+	return fmt.Sprintf("%s-%d", c.logPrefix, atomic.AddUint64(&c.requestSeq, 1))
+}
+
+// requestID returns the request ID stored in @ctx, or "" if none.
+func requestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newLogPrefix returns a random prefix to distinguish the request IDs of different clients.
+func newLogPrefix() string {
+	var b = make([]byte, 3)
+
+	if _, err := rand.Read(b); err != nil {
+		return "clc"
+	}
+	return fmt.Sprintf("%x", b)
+}
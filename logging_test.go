@@ -0,0 +1,81 @@
+package clcv2
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// Messages and field values reach the Logger with their secrets redacted.
+func TestLogRedaction(t *testing.T) {
+	var mu sync.Mutex
+	var out []string
+	var c = newClient(WithLeveledLogger(LoggerFunc(func(level LogLevel, msg string, fields Fields) {
+		mu.Lock()
+		defer mu.Unlock()
+		out = append(out, msg+" "+fields.String())
+	})))
+
+	c.log(LevelInfo, `request {"password":"secret-1","name":"web"}`, Fields{
+		"error":   errors.Errorf("failed to encode request model %+v", LoginReq{Username: "user", Password: "secret-2"}),
+		"header":  "Authorization: Bearer secret-3",
+		"body":    `{"sourceServerPassword": "secret-4", "current":"secret-5"}`,
+		"retries": 2,
+	})
+
+	if len(out) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(out))
+	} else if strings.Contains(out[0], "secret") {
+		t.Errorf("log entry contains secrets: %s", out[0])
+	}
+	for _, s := range []string{`"name":"web"`, "Username:user", "retries=2", "Bearer " + Redacted} {
+		if !strings.Contains(out[0], s) {
+			t.Errorf("log entry lacks %q: %s", s, out[0])
+		}
+	}
+}
+
+// Field values of any kind are redacted by field name or key, up to the end of the value.
+func TestRedactFields(t *testing.T) {
+	type nested struct {
+		Login LoginReq
+		Note  string
+	}
+	var plain = struct{ Name string }{"web"}
+
+	res := redactFields(Fields{
+		"struct":  LoginReq{Username: "user", Password: "secret 1"},
+		"pointer": &LoginReq{Username: "user", Password: "secret-2"},
+		"nested":  nested{Login: LoginReq{Username: "user", Password: "secret 3"}, Note: "ok"},
+		"map":     map[string]string{"password": "secret 4", "name": "web"},
+		"slice":   []LoginReq{{Username: "user", Password: "secret-5"}},
+		"error":   errors.Errorf("invalid request %+v", LoginReq{Username: "user", Password: "secret 6 with spaces"}),
+		"line":    "current: secret 7\nnext line",
+		"plain":   plain,
+		"count":   3,
+		"nil":     nil,
+	})
+
+	for k, v := range res {
+		if s := fmt.Sprintf("%+v", v); strings.Contains(s, "secret") {
+			t.Errorf("%s: value contains secrets: %s", k, s)
+		}
+	}
+	for k, expected := range map[string]string{
+		"struct": "{Username:user Password:" + Redacted + "}",
+		"nested": "{Login:{Username:user Password:" + Redacted + "} Note:ok}",
+		"map":    "map[name:web password:" + Redacted + "]",
+		"error":  "invalid request {Username:user Password:" + Redacted + "}",
+		"line":   "current:" + Redacted + "\nnext line",
+	} {
+		if res[k] != expected {
+			t.Errorf("%s: expected %q, got %q", k, expected, res[k])
+		}
+	}
+	if res["plain"] != plain || res["count"] != 3 || res["nil"] != nil {
+		t.Errorf("values without secrets changed: %v", res)
+	}
+}
@@ -0,0 +1,212 @@
+package clcv2
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the upper bounds (in seconds) of the request duration histogram.
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// MetricsCollector is a Hooks implementation that aggregates request counts, latencies,
+// retries and errors per endpoint, and exposes them in the Prometheus text format.
+// Use it via WithHooks(), and serve it (it is an http.Handler) on a /metrics endpoint.
+type MetricsCollector struct {
+	buckets []float64
+
+	mu       sync.Mutex
+	requests map[requestKey]uint64  // completed requests by status
+	errors   map[errorKey]uint64    // failed requests by error class
+	retries  map[endpointKey]uint64 // retries by endpoint
+	latency  map[endpointKey]*latencyHistogram
+}
+
+// endpointKey identifies an endpoint (path template) of an API family.
+type endpointKey struct {
+	family EndpointFamily
+	verb   string
+	path   string
+}
+
+type requestKey struct {
+	endpointKey
+	status int
+}
+
+type errorKey struct {
+	endpointKey
+	class string
+}
+
+// latencyHistogram is a cumulative histogram of request durations.
+type latencyHistogram struct {
+	counts []uint64 // per bucket, non-cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewMetricsCollector returns a collector using @buckets (in seconds) for the latency histogram.
+// If @buckets is empty, %DefaultLatencyBuckets is used.
+func NewMetricsCollector(buckets ...float64) *MetricsCollector {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &MetricsCollector{
+		buckets:  buckets,
+		requests: make(map[requestKey]uint64),
+		errors:   make(map[errorKey]uint64),
+		retries:  make(map[endpointKey]uint64),
+		latency:  make(map[endpointKey]*latencyHistogram),
+	}
+}
+
+// OnRequest implements Hooks. Requests are only counted once they complete.
+func (m *MetricsCollector) OnRequest(*RequestInfo) {}
+
+// OnResponse implements Hooks.
+func (m *MetricsCollector) OnResponse(r *ResponseInfo) {
+	var key = endpointKey{family: r.Family, verb: r.Verb, path: r.Template}
+	var secs = r.Duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[requestKey{key, r.StatusCode}]++
+	if r.ErrorClass != ErrClassNone {
+		m.errors[errorKey{key, r.ErrorClass}]++
+	}
+	if r.Retries > 0 {
+		m.retries[key] += uint64(r.Retries)
+	}
+
+	h, ok := m.latency[key]
+	if !ok {
+		h = &latencyHistogram{counts: make([]uint64, len(m.buckets))}
+		m.latency[key] = h
+	}
+	for i, le := range m.buckets {
+		if secs <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += secs
+}
+
+// ServeHTTP implements http.Handler, serving the metrics in the Prometheus text format.
+func (m *MetricsCollector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := m.WritePrometheus(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// WritePrometheus writes the current metrics to @w in the Prometheus text exposition format.
+func (m *MetricsCollector) WritePrometheus(w io.Writer) error {
+	var out = bufio.NewWriter(w)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(out, "# HELP clcv2_requests_total Number of completed CLC API requests.")
+	fmt.Fprintln(out, "# TYPE clcv2_requests_total counter")
+	reqKeys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		reqKeys = append(reqKeys, k)
+	}
+	sort.Slice(reqKeys, func(i, j int) bool {
+		if reqKeys[i].endpointKey != reqKeys[j].endpointKey {
+			return reqKeys[i].endpointKey.less(reqKeys[j].endpointKey)
+		}
+		return reqKeys[i].status < reqKeys[j].status
+	})
+	for _, k := range reqKeys {
+		fmt.Fprintf(out, "clcv2_requests_total{%s,status=%s} %d\n", k.labels(), quoteLabel(strconv.Itoa(k.status)), m.requests[k])
+	}
+
+	fmt.Fprintln(out, "# HELP clcv2_request_errors_total Number of failed CLC API requests, by error class.")
+	fmt.Fprintln(out, "# TYPE clcv2_request_errors_total counter")
+	errKeys := make([]errorKey, 0, len(m.errors))
+	for k := range m.errors {
+		errKeys = append(errKeys, k)
+	}
+	sort.Slice(errKeys, func(i, j int) bool {
+		if errKeys[i].endpointKey != errKeys[j].endpointKey {
+			return errKeys[i].endpointKey.less(errKeys[j].endpointKey)
+		}
+		return errKeys[i].class < errKeys[j].class
+	})
+	for _, k := range errKeys {
+		fmt.Fprintf(out, "clcv2_request_errors_total{%s,class=%s} %d\n", k.labels(), quoteLabel(k.class), m.errors[k])
+	}
+
+	fmt.Fprintln(out, "# HELP clcv2_request_retries_total Number of CLC API request retries.")
+	fmt.Fprintln(out, "# TYPE clcv2_request_retries_total counter")
+	for _, k := range sortedEndpoints(m.retries) {
+		fmt.Fprintf(out, "clcv2_request_retries_total{%s} %d\n", k.labels(), m.retries[k])
+	}
+
+	fmt.Fprintln(out, "# HELP clcv2_request_duration_seconds Duration of CLC API requests, including retries.")
+	fmt.Fprintln(out, "# TYPE clcv2_request_duration_seconds histogram")
+	latKeys := make([]endpointKey, 0, len(m.latency))
+	for k := range m.latency {
+		latKeys = append(latKeys, k)
+	}
+	sort.Slice(latKeys, func(i, j int) bool { return latKeys[i].less(latKeys[j]) })
+	for _, k := range latKeys {
+		var h, cum = m.latency[k], uint64(0)
+
+		for i, le := range m.buckets {
+			cum += h.counts[i]
+			fmt.Fprintf(out, "clcv2_request_duration_seconds_bucket{%s,le=%q} %d\n",
+				k.labels(), strconv.FormatFloat(le, 'g', -1, 64), cum)
+		}
+		fmt.Fprintf(out, "clcv2_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", k.labels(), h.count)
+		fmt.Fprintf(out, "clcv2_request_duration_seconds_sum{%s} %g\n", k.labels(), h.sum)
+		fmt.Fprintf(out, "clcv2_request_duration_seconds_count{%s} %d\n", k.labels(), h.count)
+	}
+	return out.Flush()
+}
+
+// labels formats @k as Prometheus label pairs.
+func (k endpointKey) labels() string {
+	return fmt.Sprintf("family=%s,verb=%s,path=%s",
+		quoteLabel(string(k.family)), quoteLabel(k.verb), quoteLabel(k.path))
+}
+
+func (k endpointKey) less(o endpointKey) bool {
+	if k.family != o.family {
+		return k.family < o.family
+	} else if k.path != o.path {
+		return k.path < o.path
+	}
+	return k.verb < o.verb
+}
+
+// sortedEndpoints returns the keys of @m in a stable order.
+func sortedEndpoints(m map[endpointKey]uint64) []endpointKey {
+	var keys = make([]endpointKey, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+	return keys
+}
+
+// labelEscaper escapes label values as required by the Prometheus text format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func quoteLabel(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
@@ -1,6 +1,7 @@
 package clcv2
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"time"
@@ -44,30 +45,50 @@ type Network struct {
 // @location: The Network's home datacenter alias.
 // @account:  (parent) AccountAlias to use (defaults to client's default AccountAlias)
 func (c *Client) GetNetworks(location, account string) (nets []Network, err error) {
+	return c.GetNetworksContext(c.ctx, location, account)
+}
+
+// GetNetworksContext is like GetNetworks, using @ctx for cancellation.
+func (c *Client) GetNetworksContext(ctx context.Context, location, account string) (nets []Network, err error) {
 	if account == "" {
 		account = c.AccountAlias
 	}
-	err = c.getCLCResponse("GET", fmt.Sprintf("/v2-experimental/networks/%s/%s", account, location), nil, &nets)
+	err = c.getCLCResponse(ctx, "GET", fmt.Sprintf("/v2-experimental/networks/%s/%s", account, location), nil, &nets)
 	return nets, err
 }
 
 // GetNetworkIdByName looks up a network by @name in @location
 func (c *Client) GetNetworkIdByName(name, location string) (*Network, error) {
-	return c.LookupMatchingNet(location, func(n *Network) bool { return n.Name == name })
+	return c.GetNetworkIdByNameContext(c.ctx, name, location)
+}
+
+// GetNetworkIdByNameContext is like GetNetworkIdByName, using @ctx for cancellation.
+func (c *Client) GetNetworkIdByNameContext(ctx context.Context, name, location string) (*Network, error) {
+	return c.LookupMatchingNetContext(ctx, location, func(n *Network) bool { return n.Name == name })
 }
 
 // GetNetworkIdByCIDR looks up a network by @cidr in @location.
 func (c *Client) GetNetworkIdByCIDR(cidr, location string) (*Network, error) {
-	return c.LookupMatchingNet(location, func(n *Network) bool { return n.Cidr == cidr })
+	return c.GetNetworkIdByCIDRContext(c.ctx, cidr, location)
+}
+
+// GetNetworkIdByCIDRContext is like GetNetworkIdByCIDR, using @ctx for cancellation.
+func (c *Client) GetNetworkIdByCIDRContext(ctx context.Context, cidr, location string) (*Network, error) {
+	return c.LookupMatchingNetContext(ctx, location, func(n *Network) bool { return n.Cidr == cidr })
 }
 
 // GetNetworkIdByIP tries to find a network matching @ip in @location.
 func (c *Client) GetNetworkIdByIP(ip, location string) (*Network, error) {
+	return c.GetNetworkIdByIPContext(c.ctx, ip, location)
+}
+
+// GetNetworkIdByIPContext is like GetNetworkIdByIP, using @ctx for cancellation.
+func (c *Client) GetNetworkIdByIPContext(ctx context.Context, ip, location string) (*Network, error) {
 	ipAddr := net.ParseIP(ip)
 	if ipAddr == nil {
 		return nil, errors.Errorf("invalid IP address %s", ip)
 	}
-	return c.LookupMatchingNet(location, func(n *Network) bool {
+	return c.LookupMatchingNetContext(ctx, location, func(n *Network) bool {
 		_, network, err := net.ParseCIDR(n.Cidr)
 		if err != nil {
 			panic(errors.Errorf("invalid CIDR address in %+v: %s", n, err))
@@ -77,9 +98,15 @@ func (c *Client) GetNetworkIdByIP(ip, location string) (*Network, error) {
 }
 
 // LookupMatchingNet looks up a network in the scope of @c for which @matches returns true.
+// Searches the effective @c.AccountAlias first, then the registered (parent) account.
 func (c *Client) LookupMatchingNet(location string, matches func(*Network) bool) (*Network, error) {
+	return c.LookupMatchingNetContext(c.ctx, location, matches)
+}
+
+// LookupMatchingNetContext is like LookupMatchingNet, using @ctx for cancellation.
+func (c *Client) LookupMatchingNetContext(ctx context.Context, location string, matches func(*Network) bool) (*Network, error) {
 	// First pass: try the (lowest) scope of the given account alias
-	nets, err := c.GetNetworks(location, c.AccountAlias)
+	nets, err := c.GetNetworksContext(ctx, location, c.AccountAlias)
 	if err != nil {
 		return nil, errors.Errorf("failed to lookup up %s networks in %s: %s",
 			c.AccountAlias, location, err)
@@ -91,7 +118,7 @@ func (c *Client) LookupMatchingNet(location string, matches func(*Network) bool)
 	}
 	// Second pass: check the parent account, if any
 	if parentAcct := c.RegisteredAccountAlias(); parentAcct != c.AccountAlias {
-		if nets, err = c.GetNetworks(location, parentAcct); err != nil {
+		if nets, err = c.GetNetworksContext(ctx, location, parentAcct); err != nil {
 			return nil, errors.Errorf("failed to lookup up %s networks in %s: %s",
 				parentAcct, location, err)
 		}
@@ -152,8 +179,13 @@ type NetworkDetails struct {
 //              - "free"    (returns details of the network as well as information about free IP addresses) or
 //              - "all"     (returns details of the network as well as information about all IP addresses).
 func (c *Client) GetNetworkDetails(datacentre, network, ipQuery string) (det NetworkDetails, err error) {
+	return c.GetNetworkDetailsContext(c.ctx, datacentre, network, ipQuery)
+}
+
+// GetNetworkDetailsContext is like GetNetworkDetails, using @ctx for cancellation.
+func (c *Client) GetNetworkDetailsContext(ctx context.Context, datacentre, network, ipQuery string) (det NetworkDetails, err error) {
 	path := fmt.Sprintf("/v2-experimental/networks/%s/%s/%s?ipAddresses=%s", c.AccountAlias, datacentre, network, ipQuery)
-	err = c.getCLCResponse("GET", path, nil, &det)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &det)
 	return det, err
 }
 
@@ -170,9 +202,14 @@ func ExtractIPs(detailsList []IpAddressDetails) (ips []string) {
 // @location: Location (data centre alias) to look @ip up in.
 // Return details for @ip, nil if not found, or error.
 func (c *Client) GetNetworkDetailsByIp(ip, location string) (iad *IpAddressDetails, err error) {
+	return c.GetNetworkDetailsByIpContext(c.ctx, ip, location)
+}
+
+// GetNetworkDetailsByIpContext is like GetNetworkDetailsByIp, using @ctx for cancellation.
+func (c *Client) GetNetworkDetailsByIpContext(ctx context.Context, ip, location string) (iad *IpAddressDetails, err error) {
 	var candidateNetworkIds []string
 
-	if networks, err := c.GetNetworks(location, c.AccountAlias); err != nil {
+	if networks, err := c.GetNetworksContext(ctx, location, c.AccountAlias); err != nil {
 		return nil, err
 	} else if len(networks) == 0 {
 		return nil, errors.Errorf("No %s networks in %s available", c.AccountAlias, location)
@@ -189,7 +226,7 @@ func (c *Client) GetNetworkDetailsByIp(ip, location string) (iad *IpAddressDetai
 	}
 
 	for _, id := range candidateNetworkIds {
-		details, err := c.GetNetworkDetails(location, id, "claimed")
+		details, err := c.GetNetworkDetailsContext(ctx, location, id, "claimed")
 		if err != nil {
 			return nil, errors.Errorf("failed to query details of network %s: %s", id, err)
 		}
@@ -224,47 +261,53 @@ type claimNetworkStatus struct {
 
 // ClaimNetwork claims a new network in @datacentre and returns a status URI for this request.
 func (c *Client) ClaimNetwork(datacentre string, cb func(QueueStatus)) (networkID string, err error) {
+	return c.ClaimNetworkContext(c.ctx, datacentre, cb)
+}
+
+// ClaimNetworkContext is like ClaimNetwork, using @ctx for cancellation.
+func (c *Client) ClaimNetworkContext(ctx context.Context, datacentre string, cb func(QueueStatus)) (networkID string, err error) {
+	claim, err := c.ClaimNetworkJobContext(ctx, datacentre)
+	if err != nil {
+		return "", err
+	} else if cb != nil {
+		claim.OnProgress(func(_ Job, status QueueStatus) { cb(status) })
+	}
+	if err = claim.Wait(ctx); err != nil {
+		return "", err
+	}
+	return claim.NetworkID(), nil
+}
+
+// ClaimNetworkJob starts claiming a new network in @datacentre, without waiting for completion.
+func (c *Client) ClaimNetworkJob(datacentre string) (*NetworkClaim, error) {
+	return c.ClaimNetworkJobContext(c.ctx, datacentre)
+}
+
+// ClaimNetworkJobContext is like ClaimNetworkJob, using @ctx for cancellation.
+func (c *Client) ClaimNetworkJobContext(ctx context.Context, datacentre string) (*NetworkClaim, error) {
 	var (
 		path = fmt.Sprintf("/v2-experimental/networks/%s/%s/claim", c.AccountAlias, datacentre)
 		res  struct {
 			ID  string `json:"operationId"`
 			URI string `json:"URI"`
 		}
-		cs claimNetworkStatus
 	)
 
-	if err := c.getCLCResponse("POST", path, nil, &res); err != nil {
-		return "", err
-	}
-
-	for prevStatus := Unknown; ; {
-		if err := c.getCLCResponse("GET", res.URI, nil, &cs); err != nil {
-			return "", errors.Errorf("failed to query claim-network queue status: %s", err)
-		}
-		if cs.Status != prevStatus {
-			if cb != nil {
-				cb(cs.Status)
-			}
-			prevStatus = cs.Status
-		}
-		if cs.Status == Failed {
-			return "", errors.Errorf("claim-network failed with status %q", cs.Status)
-		} else if cs.Status == Succeeded {
-			for _, link := range cs.Summary.Links {
-				if link.Rel == "network" {
-					return link.Id, nil
-				}
-			}
-			return "", errors.Errorf("claim-network #%d succeeded, but returned no network ID", cs.Summary.BlueprintID)
-		}
-		time.Sleep(5 * time.Second) // operation may take several minutes
+	if err := c.getCLCResponse(ctx, "POST", path, nil, &res); err != nil {
+		return nil, err
 	}
+	return c.networkClaim(res.ID, res.URI), nil
 }
 
 // ReleaseNetwork releases @networkID in @datacentre
 func (c *Client) ReleaseNetwork(datacentre, networkID string) error {
+	return c.ReleaseNetworkContext(c.ctx, datacentre, networkID)
+}
+
+// ReleaseNetworkContext is like ReleaseNetwork, using @ctx for cancellation.
+func (c *Client) ReleaseNetworkContext(ctx context.Context, datacentre, networkID string) error {
 	path := fmt.Sprintf("/v2-experimental/networks/%s/%s/%s/release", c.AccountAlias, datacentre, networkID)
-	return c.getCLCResponse("POST", path, nil, nil)
+	return c.getCLCResponse(ctx, "POST", path, nil, nil)
 }
 
 // Update the attributes of a given Network via PUT.
@@ -274,8 +317,13 @@ func (c *Client) ReleaseNetwork(datacentre, networkID string) error {
 //               (the default is the VLAN number combined with the network address).
 // @description: Description of VLAN, a free text field that defaults to the VLAN number plus network address.
 func (c *Client) UpdateNetwork(datacentre, network, name, description string) error {
+	return c.UpdateNetworkContext(c.ctx, datacentre, network, name, description)
+}
+
+// UpdateNetworkContext is like UpdateNetwork, using @ctx for cancellation.
+func (c *Client) UpdateNetworkContext(ctx context.Context, datacentre, network, name, description string) error {
 	path := fmt.Sprintf("/v2-experimental/networks/%s/%s/%s", c.AccountAlias, datacentre, network)
-	return c.getCLCResponse("PUT", path, &struct {
+	return c.getCLCResponse(ctx, "PUT", path, &struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 	}{name, description}, nil)
@@ -0,0 +1,210 @@
+package clcv2
+
+/*
+ * Completion notifications for queued operations.
+ */
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Notification describes a completed job. It is sent as JSON payload by all notifiers.
+type Notification struct {
+	// ID of the job (e.g. the queue status ID)
+	JobID string `json:"jobId"`
+
+	// Description of the operation (e.g. "clone")
+	Operation string `json:"operation,omitempty"`
+
+	// Server, group, or location the operation applies to
+	Target string `json:"target,omitempty"`
+
+	// Final status of the job (%Unknown if waiting for it failed)
+	Status QueueStatus `json:"status"`
+
+	// Time taken, in seconds
+	Duration float64 `json:"duration"`
+
+	// Error message if waiting for the job failed
+	Error string `json:"error,omitempty"`
+}
+
+// NewNotification returns the notification of job @id, which ended with @status (or error @err) after @elapsed.
+func NewNotification(id, operation, target string, status QueueStatus, elapsed time.Duration, err error) Notification {
+	var n = Notification{
+		JobID:     id,
+		Operation: operation,
+		Target:    target,
+		Status:    status,
+		Duration:  elapsed.Round(time.Millisecond).Seconds(),
+	}
+	if err != nil {
+		n.Error = err.Error()
+	}
+	return n
+}
+
+// Notifier delivers completion notifications.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Notifiers is a Notifier that delivers to all of its elements.
+type Notifiers []Notifier
+
+// Notify implements Notifier. It returns the first error encountered, after attempting all notifiers.
+func (ns Notifiers) Notify(ctx context.Context, n Notification) (err error) {
+	for _, notifier := range ns {
+		if e := notifier.Notify(ctx, n); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// ParseNotifier returns the Notifier described by @spec:
+// "http://..." or "https://..." POSTs the JSON payload to the URL; "pipe:<path>" writes the JSON payload as a single
+// line to the named pipe (or file) <path>; "cmd:<command>" runs <command> via the shell, with the JSON payload on stdin.
+// The command is a text/template, whose fields {{.JobID}}, {{.Operation}}, {{.Target}}, {{.Status}}, {{.Duration}},
+// and {{.Error}} are substituted as (shell-quoted) arguments.
+func ParseNotifier(spec string) (Notifier, error) {
+	switch {
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return &HTTPNotifier{URL: spec}, nil
+	case strings.HasPrefix(spec, "pipe:"):
+		if path := strings.TrimPrefix(spec, "pipe:"); path != "" {
+			return &PipeNotifier{Path: path}, nil
+		}
+	case strings.HasPrefix(spec, "cmd:"):
+		return NewCommandNotifier(strings.TrimPrefix(spec, "cmd:"))
+	}
+	return nil, errors.Errorf("invalid notification target %q (expecting URL, pipe:<path>, or cmd:<command>)", spec)
+}
+
+// HTTPNotifier POSTs notifications as JSON to @URL.
+type HTTPNotifier struct {
+	URL string
+
+	// HTTP client to use (defaults to a client with a timeout of %ClientTimeout)
+	Client *http.Client
+}
+
+// Notify implements Notifier.
+func (h *HTTPNotifier) Notify(ctx context.Context, n Notification) error {
+	var client = h.Client
+
+	body, err := json.Marshal(&n)
+	if err != nil {
+		return errors.Errorf("failed to serialize notification: %s", err)
+	}
+	req, err := http.NewRequest("POST", h.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Errorf("invalid notification URL %q: %s", h.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = &http.Client{Timeout: ClientTimeout}
+	}
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Errorf("failed to notify %s: %s", h.URL, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return errors.Errorf("failed to notify %s: %s", h.URL, res.Status)
+	}
+	return nil
+}
+
+// PipeNotifier writes notifications as JSON lines to the named pipe (or regular file) @Path.
+type PipeNotifier struct {
+	Path string
+}
+
+// Notify implements Notifier. Opening a named pipe blocks until there is a reader, or until @ctx is canceled.
+func (p *PipeNotifier) Notify(ctx context.Context, n Notification) error {
+	var done = make(chan error, 1)
+
+	body, err := json.Marshal(&n)
+	if err != nil {
+		return errors.Errorf("failed to serialize notification: %s", err)
+	}
+
+	go func() {
+		f, err := os.OpenFile(p.Path, os.O_WRONLY|os.O_APPEND, 0)
+		if err == nil {
+			_, err = f.Write(append(body, '\n'))
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+		done <- err
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		return errors.Errorf("failed to notify %s: %s", p.Path, err)
+	}
+	return nil
+}
+
+// CommandNotifier runs a templated shell command for each notification.
+type CommandNotifier struct {
+	tmpl *template.Template
+}
+
+// NewCommandNotifier returns a CommandNotifier for the command template @cmdTmpl (see ParseNotifier).
+func NewCommandNotifier(cmdTmpl string) (*CommandNotifier, error) {
+	if strings.TrimSpace(cmdTmpl) == "" {
+		return nil, errors.New("empty notification command")
+	}
+	tmpl, err := template.New("notify").Option("missingkey=error").Parse(cmdTmpl)
+	if err != nil {
+		return nil, errors.Errorf("invalid notification command %q: %s", cmdTmpl, err)
+	}
+	return &CommandNotifier{tmpl: tmpl}, nil
+}
+
+// Notify implements Notifier.
+func (c *CommandNotifier) Notify(ctx context.Context, n Notification) error {
+	var cmdLine bytes.Buffer
+
+	body, err := json.Marshal(&n)
+	if err != nil {
+		return errors.Errorf("failed to serialize notification: %s", err)
+	}
+	if err := c.tmpl.Execute(&cmdLine, map[string]string{
+		"JobID":     shellQuote(n.JobID),
+		"Operation": shellQuote(n.Operation),
+		"Target":    shellQuote(n.Target),
+		"Status":    shellQuote(string(n.Status)),
+		"Duration":  shellQuote((time.Duration(n.Duration * float64(time.Second))).Round(time.Second).String()),
+		"Error":     shellQuote(n.Error),
+	}); err != nil {
+		return errors.Errorf("failed to expand notification command: %s", err)
+	}
+	return runShell(cmdLine.String(), bytes.NewReader(body), nil)
+}
+
+// shellQuote quotes @s as a single argument to the shell used by runShell.
+func shellQuote(s string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
+	}
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,172 @@
+package clcv2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var testNotification = NewNotification("wa1-123", "clone", "WA1ABCDWEB01", Failed, 1500*time.Millisecond, errors.New(`it's "broken"`))
+
+func TestParseNotifier(t *testing.T) {
+	for spec, expected := range map[string]Notifier{
+		"https://example.com/hook": &HTTPNotifier{},
+		"pipe:/tmp/events":         &PipeNotifier{},
+		"cmd:true":                 &CommandNotifier{},
+		"pipe:":                    nil,
+		"cmd: ":                    nil,
+		"cmd:{{.Unclosed":          nil,
+		"/tmp/events":              nil,
+	} {
+		n, err := ParseNotifier(spec)
+		if expected == nil {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %T", spec, n)
+			}
+		} else if err != nil {
+			t.Errorf("%q: unexpected error %s", spec, err)
+		} else if fmt.Sprintf("%T", n) != fmt.Sprintf("%T", expected) {
+			t.Errorf("%q: expected %T, got %T", spec, expected, n)
+		}
+	}
+}
+
+func TestHTTPNotifier(t *testing.T) {
+	var received = make(chan Notification, 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n Notification
+
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected %s request with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("invalid payload: %s", err)
+		}
+		received <- n
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+
+	if err := (&HTTPNotifier{URL: ts.URL + "/hook"}).Notify(context.Background(), testNotification); err != nil {
+		t.Fatalf("Notify failed: %s", err)
+	} else if n := <-received; n != testNotification {
+		t.Errorf("expected %+v, got %+v", testNotification, n)
+	}
+
+	if err := (&HTTPNotifier{URL: ts.URL + "/fail"}).Notify(context.Background(), testNotification); err == nil {
+		t.Errorf("expected an error status to fail the notification")
+	} else {
+		<-received
+	}
+}
+
+func TestPipeNotifier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clcv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var p = &PipeNotifier{Path: path.Join(dir, "events")}
+	if err := p.Notify(context.Background(), testNotification); err == nil {
+		t.Errorf("expected a missing pipe to fail the notification")
+	}
+
+	if err := ioutil.WriteFile(p.Path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := p.Notify(context.Background(), testNotification); err != nil {
+			t.Fatalf("Notify %d failed: %s", i, err)
+		}
+	}
+
+	content, err := ioutil.ReadFile(p.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", content)
+	}
+	for _, line := range lines {
+		var n Notification
+
+		if err := json.Unmarshal([]byte(line), &n); err != nil || n != testNotification {
+			t.Errorf("unexpected line %q (%v)", line, err)
+		}
+	}
+}
+
+// Template fields are passed to the command as single arguments, not interpreted by the shell.
+func TestCommandNotifierQuoting(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+	dir, err := ioutil.TempDir("", "clcv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var marker = path.Join(dir, "injected")
+	var args, payload = path.Join(dir, "args"), path.Join(dir, "payload")
+
+	c, err := NewCommandNotifier(fmt.Sprintf(`printf '%%s\n' {{.JobID}} {{.Target}} {{.Status}} {{.Duration}} {{.Error}} > %s; cat > %s`,
+		shellQuote(args), shellQuote(payload)))
+	if err != nil {
+		t.Fatalf("NewCommandNotifier failed: %s", err)
+	}
+
+	for _, target := range []string{
+		"web; touch " + marker,
+		"$(touch " + marker + ")",
+		"`touch " + marker + "`",
+		"o'brien'; touch " + marker + "; echo '",
+		`"quoted" {{.JobID}} %s`,
+	} {
+		var n = testNotification
+
+		n.Target = target
+		if err := c.Notify(context.Background(), n); err != nil {
+			t.Fatalf("Notify(%q) failed: %s", target, err)
+		}
+
+		expected := strings.Join([]string{n.JobID, target, "failed", "2s", n.Error}, "\n") + "\n"
+		if content, err := ioutil.ReadFile(args); err != nil {
+			t.Fatal(err)
+		} else if string(content) != expected {
+			t.Errorf("expected the command to receive %q, got %q", expected, content)
+		}
+
+		var received Notification
+		if content, err := ioutil.ReadFile(payload); err != nil {
+			t.Fatal(err)
+		} else if err := json.Unmarshal(content, &received); err != nil || received != n {
+			t.Errorf("unexpected payload %q (%v)", content, err)
+		}
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Errorf("target was interpreted by the shell")
+	}
+
+	if c, err = NewCommandNotifier("exit 3"); err != nil {
+		t.Fatalf("NewCommandNotifier failed: %s", err)
+	} else if err = c.Notify(context.Background(), testNotification); err == nil {
+		t.Errorf("expected a failing command to fail the notification")
+	}
+}
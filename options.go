@@ -0,0 +1,158 @@
+package clcv2
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Endpoints groups the base URLs of the various CLC API families used by a Client.
+type Endpoints struct {
+	// Main CLC v2 API (see %BaseURL)
+	API string `yaml:"API,omitempty"`
+
+	// Load Balancer as a Service API
+	LBaaS string `yaml:"LBaaS,omitempty"`
+
+	// Simple Backup Service API (see %SBSurl)
+	SBS string `yaml:"SBS,omitempty"`
+}
+
+// DefaultEndpoints are the public CenturyLink Cloud API endpoints.
+var DefaultEndpoints = Endpoints{
+	API:   BaseURL,
+	LBaaS: lbaasBaseUrl,
+	SBS:   SBSurl,
+}
+
+// Option configures a Client constructed via NewClientWithOptions.
+type Option func(*Client)
+
+// WithCredentials sets the CLC portal @user and @pass to log in with.
+func WithCredentials(user, pass string) Option {
+	return func(c *Client) {
+		c.LoginReq = LoginReq{Username: user, Password: pass}
+	}
+}
+
+// WithEndpoints overrides the API endpoints of the client.
+// Empty fields of @e retain their default value.
+func WithEndpoints(e Endpoints) Option {
+	return func(c *Client) {
+		if e.API != "" {
+			c.endpoints.API = e.API
+		}
+		if e.LBaaS != "" {
+			c.endpoints.LBaaS = e.LBaaS
+		}
+		if e.SBS != "" {
+			c.endpoints.SBS = e.SBS
+		}
+	}
+}
+
+// WithTransport sets the RoundTripper that performs the actual HTTP requests.
+// Retries are layered on top of @rt. If @rt is nil, http.DefaultTransport is used.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = rt
+	}
+}
+
+// WithTimeout sets the upper bound on client operations (including retries).
+// Overrides the %ClientTimeout default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithMaxRetries sets the maximum number of retries per request (default: %MaxRetries).
+// It takes precedence over the MaxAttempts of WithRetryPolicy, regardless of the order of the options.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		if n < 0 {
+			n = 0
+		}
+		c.maxRetries = n
+	}
+}
+
+// WithRetryPolicy replaces the default retry policy (see DefaultRetryPolicy) by @p.
+// All fields of @p are used as given; only @p.MaxAttempts is overridden by WithMaxRetries.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = p
+	}
+}
+
+// WithCredentialsChanged sets a callback @fn that is called after the client has logged in again,
+// because its bearer token was rejected. Concurrent requests that were rejected share a single
+// re-login, so that @fn is called once. An error returned by @fn fails the request that triggered it.
+func WithCredentialsChanged(fn func() error) Option {
+	return func(c *Client) {
+		c.credentialsChanged = fn
+	}
+}
+
+// WithLimits sets client-side throttling @l for requests to the @family endpoint.
+// The limits are shared by all goroutines using the client.
+func WithLimits(family EndpointFamily, l Limits) Option {
+	return func(c *Client) {
+		if c.limits == nil {
+			c.limits = make(map[EndpointFamily]Limits)
+		}
+		c.limits[family] = l
+	}
+}
+
+// WithHooks adds instrumentation callbacks @h, which are called for each request.
+func WithHooks(h ...Hooks) Option {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, h...)
+	}
+}
+
+// WithPollInterval sets the time between status polls of all Jobs, overriding the per-type defaults.
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Client) {
+		c.pollInterval = d
+	}
+}
+
+// WithJournal records all queued operations, and their final status once polled, in @j.
+func WithJournal(j *Journal) Option {
+	return func(c *Client) {
+		c.journal = j
+	}
+}
+
+// WithCache caches GET responses in @rc (see ResponseCache).
+func WithCache(rc *ResponseCache) Option {
+	return func(c *Client) {
+		c.cache = rc
+	}
+}
+
+// WithLogger sets the logger used for (debugging) output.
+func WithLogger(l logrus.StdLogger) Option {
+	return func(c *Client) {
+		c.Log = l
+	}
+}
+
+// WithLeveledLogger sets the levelled, structured logger @l, which takes precedence over WithLogger().
+// Debug-level entries are passed to @l regardless of WithDebug, which only controls request/response dumps.
+func WithLeveledLogger(l Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithDebug enables/disables dumping of requests and responses. Overrides the %Debug default.
+func WithDebug(debug bool) Option {
+	return func(c *Client) {
+		c.debug = debug
+	}
+}
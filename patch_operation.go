@@ -1,5 +1,7 @@
 package clcv2
 
+import "context"
+
 // Patch operation describes a single PATCH operation to be performed on a CLCv2 resource.
 type PatchOperation struct {
 	// The operation to perform on a given property of the resource.
@@ -13,11 +15,11 @@ type PatchOperation struct {
 }
 
 // Run patch operation(s) @ops and return Link status.
-func (c *Client) patchStatus(path string, ops ...*PatchOperation) (statusId string, err error) {
-	return c.getStatus("PATCH", path, ops)
+func (c *Client) patchStatus(ctx context.Context, path string, ops ...*PatchOperation) (statusId string, err error) {
+	return c.getStatus(ctx, "PATCH", path, ops)
 }
 
 // Like patchStatus(), but without statusId. For those patch operations that return '204 No Content'.
-func (c *Client) patch(path string, ops ...*PatchOperation) error {
-	return c.getCLCResponse("PATCH", path, ops, nil)
+func (c *Client) patch(ctx context.Context, path string, ops ...*PatchOperation) error {
+	return c.getCLCResponse(ctx, "PATCH", path, ops, nil)
 }
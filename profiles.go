@@ -0,0 +1,197 @@
+package clcv2
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/pkg/errors"
+)
+
+// Profile names are also used in file names
+var profileNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// DefaultProfile is the name of the profile used if none is selected.
+// The settings of single-profile configuration files belong to this profile.
+const DefaultProfile = "default"
+
+// ConfigFile is the content of the CLC_HOME configuration file: a set of named profiles.
+// Profiles are selected by name, via CLC_PROFILE, or else via @DefaultProfile.
+type ConfigFile struct {
+	// Profile to use if none is selected (defaults to %DefaultProfile)
+	DefaultProfile string `yaml:"DefaultProfile,omitempty"`
+
+	// Secret backend shared by all profiles
+	Secrets SecretConfig `yaml:"Secrets,omitempty"`
+
+	// Profile name -> settings
+	Profiles map[string]*ClientConfig `yaml:"Profiles,omitempty"`
+}
+
+// LoadConfigFile loads the configuration file in CLC_HOME. A single-profile (legacy) configuration
+// becomes the %DefaultProfile. If there is no configuration file, the settings of clc-go-cli are
+// imported, if present; otherwise the result contains no profiles.
+func LoadConfigFile() (*ConfigFile, error) {
+	var f = &ConfigFile{Profiles: make(map[string]*ClientConfig)}
+	var confFile = path.Join(GetClcHome(), configName)
+	var legacy ClientConfig
+
+	content, err := ioutil.ReadFile(confFile)
+	if os.IsNotExist(err) {
+		if conf, err := configFromCliGo(); err != nil {
+			return nil, err
+		} else if conf != nil {
+			f.Profiles[DefaultProfile] = conf
+		}
+	} else if err != nil {
+		return nil, errors.Errorf("failed to read %s: %s", confFile, err)
+	} else if err = yaml.Unmarshal(content, f); err != nil {
+		return nil, errors.Errorf("failed to deserialize %s: %s", confFile, err)
+	} else if err = yaml.Unmarshal(content, &legacy); err != nil {
+		return nil, errors.Errorf("failed to deserialize %s: %s", confFile, err)
+	} else if _, ok := f.Profiles[DefaultProfile]; !ok && (legacy.Username != "" || legacy.Account != "" || legacy.Location != "") {
+		f.Profiles[DefaultProfile] = &legacy
+	}
+
+	for name, conf := range f.Profiles {
+		if conf == nil {
+			conf = new(ClientConfig)
+			f.Profiles[name] = conf
+		}
+		conf.Profile, conf.Secrets = name, f.Secrets
+		conf.cleartext = conf.Password != ""
+	}
+	return f, nil
+}
+
+// Save stores cleartext passwords in the SecretStore of @f (unless it is read-only), and writes the remaining settings
+// to the configuration file in CLC_HOME.
+func (f *ConfigFile) Save() error {
+	var out = ConfigFile{
+		DefaultProfile: f.DefaultProfile,
+		Secrets:        f.Secrets,
+		Profiles:       make(map[string]*ClientConfig, len(f.Profiles)),
+	}
+	var store SecretStore
+
+	for name, conf := range f.Profiles {
+		var saved = *conf
+
+		if conf.Password != "" && conf.Username != "" {
+			if store == nil {
+				s, err := f.Secrets.Store()
+				if err != nil {
+					return err
+				}
+				store = s
+			}
+			// A read-only store cannot persist the password: it has to be supplied again (flag, environment, helper).
+			if err := storeSecret(store, passwordKey(conf.Username), conf.Password); err != nil && err != ErrReadOnlySecretStore {
+				return errors.Errorf("failed to store password of %s: %s", conf.Username, err)
+			}
+		}
+		saved.Password, saved.Secrets = "", SecretConfig{}
+		out.Profiles[name] = &saved
+	}
+
+	if enc, err := yaml.Marshal(&out); err != nil {
+		return errors.Errorf("failed to serialize client configuration: %s", err)
+	} else if err = writeCLCdata(configName, enc, 0644); err != nil {
+		return err
+	}
+	for _, conf := range f.Profiles {
+		conf.cleartext = false
+	}
+	return nil
+}
+
+// ProfileName resolves the name of the profile to use: @name if set, else the value of
+// CLC_PROFILE, else @f.DefaultProfile, and finally %DefaultProfile.
+func (f *ConfigFile) ProfileName(name string) string {
+	if name != "" {
+		return name
+	} else if name = os.Getenv("CLC_PROFILE"); name != "" {
+		return name
+	} else if f.DefaultProfile != "" {
+		return f.DefaultProfile
+	}
+	return DefaultProfile
+}
+
+// Profile returns the settings of the profile @name (resolved via ProfileName), or nil if it does not exist.
+func (f *ConfigFile) Profile(name string) *ClientConfig {
+	return f.Profiles[f.ProfileName(name)]
+}
+
+// ProfileNames returns the names of all profiles, in sorted order.
+func (f *ConfigFile) ProfileNames() []string {
+	var names = make([]string, 0, len(f.Profiles))
+
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetProfile adds or replaces the profile named @conf.Profile.
+func (f *ConfigFile) SetProfile(conf *ClientConfig) error {
+	if conf == nil || conf.Profile == "" {
+		return errors.New("attempt to add a profile without name")
+	} else if !profileNameRegexp.MatchString(conf.Profile) {
+		return errors.Errorf("invalid profile name %q", conf.Profile)
+	}
+	conf.Secrets = f.Secrets
+	f.Profiles[conf.Profile] = conf
+	return nil
+}
+
+// RemoveProfile removes the profile @name, along with its cached bearer token unless another
+// profile uses the same token (i.e. the same user at the same endpoint, see tokenKey).
+func (f *ConfigFile) RemoveProfile(name string) error {
+	conf, ok := f.Profiles[name]
+	if !ok {
+		return errors.Errorf("no such profile %q", name)
+	}
+	delete(f.Profiles, name)
+	if f.DefaultProfile == name {
+		f.DefaultProfile = ""
+	}
+
+	if conf.Username == "" {
+		return nil
+	}
+	key := tokenKey(conf.Username, conf.apiEndpoint())
+	for _, other := range f.Profiles {
+		if tokenKey(other.Username, other.apiEndpoint()) == key {
+			return nil
+		}
+	}
+
+	store, err := f.Secrets.Store()
+	if err != nil {
+		return err
+	}
+	return newTokenCache(store).remove(key)
+}
+
+// SetDefaultProfile makes @name the default profile.
+func (f *ConfigFile) SetDefaultProfile(name string) error {
+	if _, ok := f.Profiles[name]; !ok {
+		return errors.Errorf("no such profile %q", name)
+	}
+	f.DefaultProfile = name
+	return nil
+}
+
+// apiEndpoint returns the main API endpoint used by profile @c.
+func (c *ClientConfig) apiEndpoint() string {
+	if c.Endpoints != nil && c.Endpoints.API != "" {
+		return c.Endpoints.API
+	}
+	return DefaultEndpoints.API
+}
@@ -0,0 +1,130 @@
+package clcv2
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withClcHome runs @fn with CLC_HOME set to a new temporary directory.
+func withClcHome(t *testing.T, fn func(dir string)) {
+	dir, err := ioutil.TempDir("", "clcv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer os.Setenv("CLC_HOME", os.Getenv("CLC_HOME"))
+	os.Setenv("CLC_HOME", dir)
+
+	fn(dir)
+}
+
+// testToken returns a JWT-style bearer token called @name that expires at @exp.
+func testToken(name string, exp time.Time) string {
+	var claims = base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+
+	return name + "." + claims + ".signature"
+}
+
+// Single-profile configuration files and clc-go-cli settings become the default profile,
+// whose cleartext password is moved into the secret store on migration.
+func TestLoadLegacyConfig(t *testing.T) {
+	defer os.Setenv(passphraseEnv, os.Getenv(passphraseEnv))
+	os.Setenv(passphraseEnv, "passphrase")
+
+	for _, tc := range []struct {
+		desc, file, content string
+	}{
+		{"single profile", configName, "User: alice\nPassword: s3cret\nAccount: ABCD\nLocation: WA1\n"},
+		{"clc-go-cli", "config.yml", "user: alice\npassword: s3cret\ndefaultdatacenter: WA1\n"},
+	} {
+		withClcHome(t, func(dir string) {
+			if err := ioutil.WriteFile(path.Join(dir, tc.file), []byte(tc.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := LoadConfigFile()
+			if err != nil {
+				t.Fatalf("%s: LoadConfigFile failed: %s", tc.desc, err)
+			}
+			conf := f.Profile("")
+			if names := f.ProfileNames(); len(names) != 1 || conf == nil {
+				t.Fatalf("%s: expected a single default profile, got %v", tc.desc, names)
+			} else if conf.Username != "alice" || conf.Password != "s3cret" || conf.Location != "WA1" || !conf.NeedsMigration() {
+				t.Errorf("%s: unexpected profile %s", tc.desc, conf)
+			}
+
+			if migrated, err := MigrateClientConfig(); err != nil || !migrated {
+				t.Fatalf("%s: expected migration, got %t, %v", tc.desc, migrated, err)
+			} else if content, err := ioutil.ReadFile(path.Join(dir, configName)); err != nil {
+				t.Fatalf("%s: configuration was not written: %s", tc.desc, err)
+			} else if strings.Contains(string(content), "s3cret") {
+				t.Errorf("%s: migrated configuration contains the password: %s", tc.desc, content)
+			}
+
+			if conf, err = LoadProfile(""); err != nil {
+				t.Errorf("%s: LoadProfile failed: %s", tc.desc, err)
+			} else if conf.Username != "alice" || conf.Password != "s3cret" || conf.NeedsMigration() {
+				t.Errorf("%s: unexpected migrated profile %s", tc.desc, conf)
+			}
+			if migrated, err := MigrateClientConfig(); err != nil || migrated {
+				t.Errorf("%s: expected no further migration, got %t, %v", tc.desc, migrated, err)
+			}
+		})
+	}
+}
+
+// The cached token of a removed profile is removed with the last profile that uses it.
+func TestRemoveProfileToken(t *testing.T) {
+	defer os.Setenv(passphraseEnv, os.Getenv(passphraseEnv))
+	os.Setenv(passphraseEnv, "passphrase")
+
+	withClcHome(t, func(dir string) {
+		var f = &ConfigFile{Profiles: make(map[string]*ClientConfig)}
+		var key = tokenKey("alice", DefaultEndpoints.API)
+
+		store, err := f.Secrets.Store()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tc := newTokenCache(store)
+
+		for _, conf := range []*ClientConfig{
+			{Profile: "a", Username: "alice", Account: "ABCD"},
+			{Profile: "b", Username: "Alice", Account: "EFGH"},
+			{Profile: "c", Username: "bob"},
+		} {
+			if err := f.SetProfile(conf); err != nil {
+				t.Fatal(err)
+			}
+		}
+		creds := LoginRes{User: "alice", BearerToken: testToken("alice", time.Now().Add(time.Hour))}
+		if err := tc.store(key, newCachedToken(creds, DefaultEndpoints.API)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.RemoveProfile("a"); err != nil {
+			t.Fatalf("RemoveProfile failed: %s", err)
+		} else if cached, err := tc.load(key); err != nil || !cached.valid() {
+			t.Errorf("expected the token shared with profile b to be kept, got %+v, %v", cached, err)
+		}
+		if err := f.RemoveProfile("b"); err != nil {
+			t.Fatalf("RemoveProfile failed: %s", err)
+		} else if cached, err := tc.load(key); err != nil || cached != nil {
+			t.Errorf("expected the token to be removed, got %+v, %v", cached, err)
+		} else if secret, err := store.Get("token/" + key); err != nil || secret != "" {
+			t.Errorf("expected the token to be removed from the secret store, got %q, %v", secret, err)
+		}
+		if err := f.RemoveProfile("a"); err == nil {
+			t.Errorf("expected removal of a missing profile to fail")
+		}
+		if names := f.ProfileNames(); len(names) != 1 || names[0] != "c" {
+			t.Errorf("expected only profile c to remain, got %v", names)
+		}
+	})
+}
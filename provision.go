@@ -0,0 +1,228 @@
+package clcv2
+
+/*
+ * Creating/cloning servers and waiting until they are fully provisioned.
+ */
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ProvisionPhase identifies the phases of CreateServerAndWait/CloneServerAndWait.
+type ProvisionPhase string
+
+const (
+	// Preparing the source server of a clone (powering it on, obtaining credentials)
+	PhaseSource ProvisionPhase = "source"
+
+	// Submitting the 'Create Server' request
+	PhaseSubmit ProvisionPhase = "submit"
+
+	// Waiting for the queue job of the request to complete
+	PhaseQueue ProvisionPhase = "queue"
+
+	// Resolving the 'self' link of the new server
+	PhaseResolve ProvisionPhase = "resolve"
+
+	// Waiting for the new server to become active and to have an internal IP address
+	PhaseActivate ProvisionPhase = "activate"
+)
+
+const (
+	// Time between attempts to resolve, and to check the status of, a new server
+	provisionPollInterval = 5 * time.Second
+
+	// Number of attempts to submit a clone request while the (just powered-on) source server boots
+	cloneSubmitAttempts = 9
+
+	// Time between clone-request attempts
+	cloneSubmitDelay = 1 * time.Minute
+)
+
+// ProvisionError is returned by CreateServerAndWait and CloneServerAndWait, recording which phase failed.
+type ProvisionError struct {
+	Phase ProvisionPhase
+
+	// Queue status ID and 'self' link of the new server, if known
+	StatusID, URI string
+
+	// Underlying error
+	Err error
+}
+
+// Error implements error.
+func (e *ProvisionError) Error() string {
+	var msg = fmt.Sprintf("server provisioning failed in %s phase", e.Phase)
+
+	if e.StatusID != "" {
+		msg += fmt.Sprintf(" (status ID %s)", e.StatusID)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// Cause returns the underlying error (github.com/pkg/errors).
+func (e *ProvisionError) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns the underlying error (errors.Is/As).
+func (e *ProvisionError) Unwrap() error {
+	return e.Err
+}
+
+// CreateServerAndWait creates a new server according to @req and waits until it is fully provisioned:
+// it waits for the queue job, resolves the new server (retrying until it exists), and waits until the
+// server is active and has an internal IP address. Returns the populated Server, or a *ProvisionError.
+func (c *Client) CreateServerAndWait(ctx context.Context, req *CreateServerReq) (Server, error) {
+	return c.CreateServerAndWaitFn(ctx, req, nil)
+}
+
+// CreateServerAndWaitFn is like CreateServerAndWait, calling @cb whenever the queue status changes.
+func (c *Client) CreateServerAndWaitFn(ctx context.Context, req *CreateServerReq, cb func(Job, QueueStatus)) (Server, error) {
+	var p = &provisioning{c: c, cb: cb}
+
+	uri, statusID, err := c.CreateServerContext(ctx, req)
+	if err != nil {
+		return Server{}, p.fail(PhaseSubmit, err)
+	}
+	return p.wait(ctx, uri, statusID)
+}
+
+// CloneServerAndWait is like CreateServerAndWait, but clones the server @req.SourceServerId. If necessary,
+// it powers on the source server, and fills in @req.SourceServerPassword from the source credentials.
+// Since the source server must be fully booted, submitting is retried after powering it on.
+func (c *Client) CloneServerAndWait(ctx context.Context, req *CreateServerReq) (Server, error) {
+	return c.CloneServerAndWaitFn(ctx, req, nil)
+}
+
+// CloneServerAndWaitFn is like CloneServerAndWait, calling @cb whenever the queue status of the clone changes.
+func (c *Client) CloneServerAndWaitFn(ctx context.Context, req *CreateServerReq, cb func(Job, QueueStatus)) (Server, error) {
+	var p = &provisioning{c: c, cb: cb}
+	var attempts = 1
+
+	src, err := c.GetServerContext(ctx, req.SourceServerId)
+	if err != nil {
+		return Server{}, p.fail(PhaseSource, err)
+	}
+
+	if req.SourceServerPassword == "" {
+		creds, err := c.GetServerCredentialsContext(ctx, src.Name)
+		if err != nil {
+			return Server{}, p.fail(PhaseSource, errors.Errorf("failed to obtain credentials of %s: %s", src.Name, err))
+		}
+		req.SourceServerPassword = creds.Password
+	}
+
+	if src.Details.PowerState == "stopped" {
+		c.log(LevelInfo, "powering on clone source", Fields{"server": src.Name})
+
+		statusID, err := c.PowerOnServerContext(ctx, src.Name)
+		if err == nil {
+			err = c.StatusJob(statusID).Wait(ctx)
+		}
+		if err != nil {
+			return Server{}, p.fail(PhaseSource, errors.Errorf("failed to power on %s: %s", src.Name, err))
+		}
+		// It may take several minutes until the backend is able to clone a freshly booted server.
+		attempts = cloneSubmitAttempts
+	}
+
+	for i := 1; ; i++ {
+		uri, statusID, err := c.CreateServerContext(ctx, req)
+		if err == nil {
+			return p.wait(ctx, uri, statusID)
+		} else if i == attempts || invalidSource(err) {
+			return Server{}, p.fail(PhaseSubmit, err)
+		}
+		c.log(LevelWarn, "clone request failed, retrying", Fields{"attempt": i, "source": src.Name, "error": err})
+		if err := sleepContext(ctx, cloneSubmitDelay); err != nil {
+			return Server{}, p.fail(PhaseSubmit, err)
+		}
+	}
+}
+
+// invalidSource returns true if @err rejects the source server of a clone request, which retrying does not fix.
+func invalidSource(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && len(apiErr.ModelState["body.sourceServerId"]) > 0
+}
+
+// provisioning tracks the state of CreateServerAndWait/CloneServerAndWait.
+type provisioning struct {
+	c             *Client
+	cb            func(Job, QueueStatus)
+	statusID, uri string
+}
+
+// fail returns the ProvisionError of @phase.
+func (p *provisioning) fail(phase ProvisionPhase, err error) error {
+	return &ProvisionError{Phase: phase, StatusID: p.statusID, URI: p.uri, Err: err}
+}
+
+// wait waits until the server at @uri, created by the queue job @statusID, is fully provisioned.
+func (p *provisioning) wait(ctx context.Context, uri, statusID string) (Server, error) {
+	var interval = provisionPollInterval
+	var s Server
+
+	p.uri, p.statusID = uri, statusID
+	if p.c.pollInterval > 0 {
+		interval = p.c.pollInterval
+	}
+
+	// The status ID is empty if CreateServer adopted a server created by a failed request.
+	if statusID != "" {
+		job := p.c.StatusJob(statusID)
+		job.OnProgress(func(j Job, status QueueStatus) {
+			p.c.log(LevelInfo, "server provisioning", Fields{"status_id": statusID, "status": status})
+			if p.cb != nil {
+				p.cb(j, status)
+			}
+		})
+		if err := job.Wait(ctx); err != nil {
+			return s, p.fail(PhaseQueue, err)
+		}
+	}
+
+	// The server may not be visible immediately after the queue job completes.
+	for {
+		var err error
+
+		if s, err = p.c.GetServerByURIContext(ctx, uri); err == nil {
+			break
+		} else if !IsNotFound(err) {
+			return s, p.fail(PhaseResolve, err)
+		} else if err = sleepContext(ctx, interval); err != nil {
+			return s, p.fail(PhaseResolve, err)
+		}
+	}
+
+	for !serverProvisioned(&s) {
+		var err error
+
+		if s.Status != "active" && s.Status != "underConstruction" {
+			return s, p.fail(PhaseActivate, errors.Errorf("server %s has status %q", s.Name, s.Status))
+		} else if err = sleepContext(ctx, interval); err != nil {
+			return s, p.fail(PhaseActivate, errors.Errorf("server %s did not become active: %s", s.Name, err))
+		} else if s, err = p.c.GetServerByURIContext(ctx, uri); err != nil {
+			return s, p.fail(PhaseActivate, err)
+		}
+	}
+	return s, nil
+}
+
+// serverProvisioned returns true if @s is active and has an internal IP address.
+func serverProvisioned(s *Server) bool {
+	if s.Status != "active" {
+		return false
+	}
+	for _, ip := range s.Details.IpAddresses {
+		if ip.Internal != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package clcv2_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/grrtrr/clcv2/clcv2test"
+	"github.com/pkg/errors"
+)
+
+// requestHook is a clcv2.Hooks that calls @fn once, before the first @verb request on @path is sent.
+type requestHook struct {
+	verb, path string
+	fn         func()
+	once       sync.Once
+}
+
+func (h *requestHook) OnRequest(r *clcv2.RequestInfo) {
+	if r.Verb == h.verb && r.Path == h.path {
+		h.once.Do(h.fn)
+	}
+}
+
+func (h *requestHook) OnResponse(*clcv2.ResponseInfo) {}
+
+// provisionClient returns a client of @f that polls rapidly and does not retry failed requests.
+func provisionClient(t *testing.T, f *clcv2test.FakeServer, opts ...clcv2.Option) *clcv2.Client {
+	client, err := f.Client(append([]clcv2.Option{clcv2.WithPollInterval(time.Millisecond), clcv2.WithMaxRetries(0)}, opts...)...)
+	if err != nil {
+		t.Fatalf("login failed: %s", err)
+	}
+	return client
+}
+
+// provisionPhase returns the phase of the *ProvisionError @err, or "" if @err is not a *ProvisionError.
+func provisionPhase(err error) clcv2.ProvisionPhase {
+	var pe *clcv2.ProvisionError
+
+	if errors.As(err, &pe) {
+		return pe.Phase
+	}
+	return ""
+}
+
+func TestCreateServerAndWait(t *testing.T) {
+	f := clcv2test.NewFakeServer("ABCD", "WA1")
+	defer f.Close()
+
+	// The new server is not visible immediately after the queue job has completed.
+	f.FailRequests("GET", "/v2/servers/ABCD/WA1ABCDWEB01", 2, http.StatusNotFound, nil)
+	s, err := provisionClient(t, f).CreateServerAndWait(context.Background(), createServerReq(f.RootGroup("WA1")))
+	if err != nil {
+		t.Fatalf("CreateServerAndWait failed: %s", err)
+	} else if s.Name != "WA1ABCDWEB01" || s.Status != "active" || len(s.Details.IpAddresses) == 0 {
+		t.Errorf("unexpected server %+v", s)
+	}
+	if n := f.Requests("GET", "/v2/servers/ABCD/WA1ABCDWEB01"); n != 3 {
+		t.Errorf("expected 3 attempts to resolve the server, got %d", n)
+	}
+}
+
+// Each phase of provisioning fails with a *ProvisionError that names it.
+func TestProvisionPhaseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		phase clcv2.ProvisionPhase
+		clone bool
+		setup func(t *testing.T, f *clcv2test.FakeServer, req *clcv2.CreateServerReq) []clcv2.Option
+		check func(err error) bool
+	}{
+		{
+			phase: clcv2.PhaseSource,
+			clone: true,
+			setup: func(_ *testing.T, _ *clcv2test.FakeServer, req *clcv2.CreateServerReq) []clcv2.Option {
+				req.SourceServerId = "WA1ABCDNOPE01"
+				return nil
+			},
+			check: clcv2.IsNotFound,
+		},
+		{
+			phase: clcv2.PhaseSubmit,
+			setup: func(_ *testing.T, _ *clcv2test.FakeServer, req *clcv2.CreateServerReq) []clcv2.Option {
+				req.Cpu = 99
+				return nil
+			},
+			check: clcv2.IsValidation,
+		},
+		{
+			phase: clcv2.PhaseQueue,
+			setup: func(_ *testing.T, f *clcv2test.FakeServer, _ *clcv2.CreateServerReq) []clcv2.Option {
+				f.FailJobs(1)
+				return nil
+			},
+			check: func(err error) bool { return errors.Is(err, clcv2.ErrJobFailed) },
+		},
+		{
+			phase: clcv2.PhaseResolve,
+			setup: func(_ *testing.T, f *clcv2test.FakeServer, _ *clcv2.CreateServerReq) []clcv2.Option {
+				f.FailRequests("GET", "/v2/servers/ABCD/WA1ABCDWEB01", 1, http.StatusInternalServerError, nil)
+				return nil
+			},
+			check: func(err error) bool {
+				var apiErr *clcv2.APIError
+				return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusInternalServerError
+			},
+		},
+		{
+			phase: clcv2.PhaseActivate,
+			setup: func(t *testing.T, f *clcv2test.FakeServer, _ *clcv2.CreateServerReq) []clcv2.Option {
+				// The new server is archived right after its creation.
+				return []clcv2.Option{clcv2.WithHooks(&requestHook{verb: "GET", path: "/v2/servers/ABCD/WA1ABCDWEB01", fn: func() {
+					if _, err := provisionClient(t, f).ArchiveServer("WA1ABCDWEB01"); err != nil {
+						t.Errorf("ArchiveServer failed: %s", err)
+					}
+					f.CompleteJobs()
+				}})}
+			},
+			check: func(err error) bool { return strings.Contains(err.Error(), `status "archived"`) },
+		},
+	} {
+		func() {
+			var f = clcv2test.NewFakeServer("ABCD", "WA1")
+			var req = createServerReq(f.RootGroup("WA1"))
+			var err error
+			defer f.Close()
+
+			client := provisionClient(t, f, tc.setup(t, f, req)...)
+			if tc.clone {
+				_, err = client.CloneServerAndWait(context.Background(), req)
+			} else {
+				_, err = client.CreateServerAndWait(context.Background(), req)
+			}
+
+			if phase := provisionPhase(err); phase != tc.phase {
+				t.Errorf("expected a failure in %s phase, got %v", tc.phase, err)
+			} else if !tc.check(errors.Cause(err)) {
+				t.Errorf("%s: unexpected error %s", tc.phase, err)
+			} else if pe := err.(*clcv2.ProvisionError); (pe.StatusID == "") != (tc.phase == clcv2.PhaseSource || tc.phase == clcv2.PhaseSubmit) {
+				t.Errorf("%s: unexpected status ID %q", tc.phase, pe.StatusID)
+			}
+		}()
+	}
+}
+
+// A clone request that rejects the source server is not retried, even while the source boots.
+func TestCloneInvalidSource(t *testing.T) {
+	f := clcv2test.NewFakeServer("ABCD", "WA1")
+	defer f.Close()
+
+	src, err := f.AddServer(f.RootGroup("WA1"), "SRC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := provisionClient(t, f)
+	if _, err := admin.PowerOffServer(src); err != nil {
+		t.Fatalf("PowerOffServer failed: %s", err)
+	}
+	f.CompleteJobs()
+
+	// The source server disappears after it has been powered on.
+	client := provisionClient(t, f, clcv2.WithHooks(&requestHook{verb: "POST", path: "/v2/servers/ABCD", fn: func() {
+		if _, err := admin.DeleteServer(src); err != nil {
+			t.Errorf("DeleteServer failed: %s", err)
+		}
+		f.CompleteJobs()
+	}}))
+
+	req := createServerReq(f.RootGroup("WA1"))
+	req.SourceServerId = src
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.CloneServerAndWait(context.Background(), req)
+		done <- err
+	}()
+
+	select {
+	case err = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("clone request was retried")
+	}
+	if phase := provisionPhase(err); phase != clcv2.PhaseSubmit || !clcv2.IsValidation(err) {
+		t.Errorf("expected a validation error in submit phase, got %v", err)
+	} else if n := f.Requests("POST", "/v2/servers/ABCD"); n != 1 {
+		t.Errorf("expected a single clone request, got %d", n)
+	}
+	if s, _ := f.ServerState(src); s.Name != "" {
+		t.Errorf("source server %s was not deleted", src)
+	}
+}
@@ -1,6 +1,9 @@
 package clcv2
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 /*
  * Management of Public IP Addresses
@@ -24,15 +27,25 @@ type PublicIPAddress struct {
 // protocols and ports. It may also be set to restrict access based on a source IP range.
 // @serverId: ID of the server to change.
 func (c *Client) AddPublicIPAddress(serverId string, req *PublicIPAddress) (statusId string, err error) {
-	return c.getStatus("POST", fmt.Sprintf("/v2/servers/%s/%s/publicIPAddresses", c.AccountAlias, serverId), req)
+	return c.AddPublicIPAddressContext(c.ctx, serverId, req)
+}
+
+// AddPublicIPAddressContext is like AddPublicIPAddress, using @ctx for cancellation.
+func (c *Client) AddPublicIPAddressContext(ctx context.Context, serverId string, req *PublicIPAddress) (statusId string, err error) {
+	return c.getStatus(ctx, "POST", fmt.Sprintf("/v2/servers/%s/%s/publicIPAddresses", c.AccountAlias, serverId), req)
 }
 
 // Get the details for the public IP address of a server.
 // @serverId: ID of the server to query.
 // @publicIp: The specific public IP to return details about.
 func (c *Client) GetPublicIPAddress(serverId, publicIp string) (res PublicIPAddress, err error) {
-	path := fmt.Sprintf("/v2/servers/%s/%s/publicIPAddresses/%s", c.credentials.AccountAlias, serverId, publicIp)
-	err = c.getCLCResponse("GET", path, nil, &res)
+	return c.GetPublicIPAddressContext(c.ctx, serverId, publicIp)
+}
+
+// GetPublicIPAddressContext is like GetPublicIPAddress, using @ctx for cancellation.
+func (c *Client) GetPublicIPAddressContext(ctx context.Context, serverId, publicIp string) (res PublicIPAddress, err error) {
+	path := fmt.Sprintf("/v2/servers/%s/%s/publicIPAddresses/%s", c.AccountAlias, serverId, publicIp)
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
@@ -40,8 +53,13 @@ func (c *Client) GetPublicIPAddress(serverId, publicIp string) (res PublicIPAddr
 // @serverId: ID of the server to update.
 // @publicIp: The specific public IP to return details about.
 func (c *Client) UpdatePublicIPAddress(serverId, publicIp string, req *PublicIPAddress) (statusId string, err error) {
+	return c.UpdatePublicIPAddressContext(c.ctx, serverId, publicIp, req)
+}
+
+// UpdatePublicIPAddressContext is like UpdatePublicIPAddress, using @ctx for cancellation.
+func (c *Client) UpdatePublicIPAddressContext(ctx context.Context, serverId, publicIp string, req *PublicIPAddress) (statusId string, err error) {
 	path := fmt.Sprintf("/v2/servers/%s/%s/publicIPAddresses/%s", c.AccountAlias, serverId, publicIp)
-	return c.getStatus("PUT", path, req)
+	return c.getStatus(ctx, "PUT", path, req)
 }
 
 // Release the given public IP address of a server so that it is no longer associated
@@ -49,6 +67,11 @@ func (c *Client) UpdatePublicIPAddress(serverId, publicIp string, req *PublicIPA
 // @serverId: ID of the server to query.
 // @publicIp: The specific public IP to return details about.
 func (c *Client) RemovePublicIPAddress(serverId, publicIp string) (statusId string, err error) {
+	return c.RemovePublicIPAddressContext(c.ctx, serverId, publicIp)
+}
+
+// RemovePublicIPAddressContext is like RemovePublicIPAddress, using @ctx for cancellation.
+func (c *Client) RemovePublicIPAddressContext(ctx context.Context, serverId, publicIp string) (statusId string, err error) {
 	path := fmt.Sprintf("/v2/servers/%s/%s/publicIPAddresses/%s", c.AccountAlias, serverId, publicIp)
-	return c.getStatus("DELETE", path, nil)
+	return c.getStatus(ctx, "DELETE", path, nil)
 }
@@ -0,0 +1,124 @@
+package clcv2
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces secret values in log output.
+const Redacted = "REDACTED"
+
+var (
+	// JSON string fields holding secrets: login/server passwords, the current password
+	// of ServerChangePassword(), and bearer tokens.
+	secretFieldRegexp = regexp.MustCompile(`(?i)("(?:password|sourceServerPassword|current|bearerToken)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+	// The same fields in Go syntax (%+v), e.g. in errors that include a request model. Since values are
+	// not quoted, a value extends up to the next field, or to the end of the struct, map or line.
+	goSecretFieldRegexp = regexp.MustCompile(`(?i)\b((?:password|sourceServerPassword|current|bearerToken):)[^\n}\]]*?(\s+\w+:|[}\]\n]|$)`)
+
+	// Authorization header values
+	bearerRegexp = regexp.MustCompile(`\bBearer\s+[^\s"]+`)
+
+	// Paths of requests whose responses consist entirely of secrets (GetServerCredentials).
+	credentialsPathRegexp = regexp.MustCompile(`(?i)/v2/servers/[^/]+/[^/]+/credentials$`)
+)
+
+// RedactHeader returns a copy of @h in which the bearer token is redacted.
+func RedactHeader(h http.Header) http.Header {
+	var res = make(http.Header, len(h))
+
+	for k, v := range h {
+		res[k] = append([]string(nil), v...)
+	}
+	if res.Get("Authorization") != "" {
+		res.Set("Authorization", "Bearer "+Redacted)
+	}
+	return res
+}
+
+// RedactBody returns the request or response @body of an API request to @path, with the values of
+// password and bearer-token fields redacted. Credential responses are redacted as a whole.
+func RedactBody(path string, body []byte) []byte {
+	if idx := strings.IndexByte(path, '?'); idx >= 0 {
+		path = path[:idx]
+	}
+	if len(body) == 0 {
+		return body
+	} else if credentialsPathRegexp.MatchString(path) {
+		return []byte(`"` + Redacted + `"`)
+	}
+	return secretFieldRegexp.ReplaceAll(body, []byte(`${1}"`+Redacted+`"`))
+}
+
+// redactString returns @s with the values of password and bearer-token fields (in JSON or Go syntax),
+// and bearer tokens in Authorization headers redacted.
+func redactString(s string) string {
+	s = secretFieldRegexp.ReplaceAllString(s, `${1}"`+Redacted+`"`)
+	s = goSecretFieldRegexp.ReplaceAllString(s, `${1}`+Redacted+`${2}`)
+	return bearerRegexp.ReplaceAllString(s, "Bearer "+Redacted)
+}
+
+// redactFields returns a copy of @f in which the values containing secrets are replaced by their redacted
+// string representation (see redactString). Structs, maps and the like are checked by field name or key,
+// in %+v format. Other values retain their type.
+func redactFields(f Fields) Fields {
+	var res = make(Fields, len(f))
+
+	for k, v := range f {
+		res[k] = v
+		switch v.(type) {
+		case string, error, fmt.Stringer:
+			if s := fmt.Sprint(v); redactString(s) != s {
+				res[k] = redactString(s)
+			}
+		default:
+			if v == nil {
+				continue
+			}
+			switch reflect.TypeOf(v).Kind() {
+			case reflect.Struct, reflect.Map, reflect.Ptr, reflect.Slice, reflect.Array:
+				if s := fmt.Sprintf("%+v", v); redactString(s) != s {
+					res[k] = redactString(s)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// dumpRequest returns a redacted dump of @req with the given @body.
+func dumpRequest(req *http.Request, body []byte) string {
+	var r = *req
+
+	r.Header = RedactHeader(req.Header)
+	r.Body, r.ContentLength = nil, 0
+	dump, err := httputil.DumpRequest(&r, false)
+	if err != nil {
+		return err.Error()
+	}
+	return string(bytes.TrimSpace(append(dump, RedactBody(req.URL.Path, body)...)))
+}
+
+// dumpResponse returns a redacted dump of @res with the given @body.
+// Error responses are retained, since they do not carry credentials.
+func dumpResponse(res *http.Response, body []byte) string {
+	var r = *res
+	var path = res.Request.URL.Path
+
+	r.Header = RedactHeader(res.Header)
+	r.Body = nil
+	dump, err := httputil.DumpResponse(&r, false)
+	if err != nil {
+		return err.Error()
+	}
+	if res.StatusCode >= 300 {
+		path = "" // not a credentials response
+	}
+	return string(bytes.TrimSpace(append(dump, RedactBody(path, body)...)))
+}
@@ -0,0 +1,163 @@
+package clcv2
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PuerkitoBio/rehttp"
+)
+
+// RetryPolicy determines which failed requests are retried, and how long to wait in between.
+type RetryPolicy struct {
+	// HTTP status codes indicating a temporary failure that is worth retrying.
+	// Requests that fail without a response (e.g. connection errors) are always retryable.
+	RetryableStatus []int
+
+	// Whether to retry non-idempotent requests (POST, PATCH, DELETE), which may have taken
+	// effect even though the request failed. Throttled requests (429) are retried regardless,
+	// since the server did not process them. Create requests (CreateServer, CreateGroup) are
+	// only retried after checking that the failed attempt did not create the resource (to this
+	// end, CreateServer adds a correlation marker to the description of the new server).
+	RetryNonIdempotent bool
+
+	// Maximum number of attempts per request, including the first one. Values < 1 mean a single
+	// attempt, i.e. no retries (see also WithMaxRetries).
+	MaxAttempts int
+
+	// Backoff returns the delay before retry #@retry (starting at 0).
+	// A Retry-After header in the failed response takes precedence.
+	// If nil, ExpJitterBackoff(%StepDelay, <client timeout>) is used.
+	Backoff func(retry int) time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used by clients that do not set one via WithRetryPolicy.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		/* Request timeout, too many requests, server error, bad gateway, service unavailable, gateway timeout */
+		RetryableStatus:    []int{408, 429, 500, 502, 503, 504},
+		RetryNonIdempotent: true,
+		MaxAttempts:        MaxRetries + 1,
+	}
+}
+
+// ExpJitterBackoff returns an exponential backoff with random jitter, starting at @base and bounded by @max.
+func ExpJitterBackoff(base, max time.Duration) func(int) time.Duration {
+	var delay = rehttp.ExpJitterDelay(base, max)
+
+	return func(retry int) time.Duration {
+		return delay(rehttp.Attempt{Index: retry})
+	}
+}
+
+// isRetryableStatus returns true if @status is one of @p.RetryableStatus.
+func (p *RetryPolicy) isRetryableStatus(status int) bool {
+	for _, s := range p.RetryableStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdempotent returns true if repeating a @verb request has no additional side effects.
+func isIdempotent(verb string) bool {
+	switch verb {
+	case "POST", "PATCH", "DELETE":
+		return false
+	}
+	return true
+}
+
+// retryAfter returns the delay requested by the Retry-After header of @res, or 0 if none.
+func retryAfter(res *http.Response) time.Duration {
+	if res == nil {
+		return 0
+	}
+	hdr := res.Header.Get("Retry-After")
+	if hdr == "" {
+		return 0
+	} else if secs, err := strconv.Atoi(hdr); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	} else if when, err := http.ParseTime(hdr); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
+
+// attemptFields adds the log fields describing @at to @fields.
+func attemptFields(at rehttp.Attempt, fields Fields) Fields {
+	if fields == nil {
+		fields = make(Fields)
+	}
+	fields["request_id"] = requestID(at.Request.Context())
+	fields["verb"], fields["path"] = at.Request.Method, at.Request.URL.Path
+	if at.Response == nil {
+		fields["error"] = at.Error
+	} else {
+		fields["status"] = at.Response.StatusCode
+	}
+	return fields
+}
+
+// noRetryKey marks request contexts whose requests the transport must not retry (see withoutRetry).
+type noRetryKey struct{}
+
+// withoutRetry returns a copy of @ctx whose requests are not retried by the transport, except when throttled (429).
+// Used by callers that retry requests themselves.
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// retryer implements the retry decision of @c.retryPolicy.
+func (c *Client) retryer() rehttp.RetryFn {
+	return rehttp.RetryFn(func(at rehttp.Attempt) bool {
+		var p = &c.retryPolicy
+		var throttled = at.Response != nil && at.Response.StatusCode == http.StatusTooManyRequests
+
+		if at.Request.Context().Err() != nil {
+			return false
+		} else if at.Response != nil && !p.isRetryableStatus(at.Response.StatusCode) {
+			return false
+		} else if at.Request.Context().Value(noRetryKey{}) != nil && !throttled {
+			return false
+		}
+
+		if at.Index+1 >= p.MaxAttempts {
+			c.log(LevelWarn, "giving up", attemptFields(at, Fields{"attempts": at.Index + 1}))
+			return false
+		} else if !isIdempotent(at.Request.Method) && !p.RetryNonIdempotent && !throttled {
+			c.log(LevelWarn, "not retrying non-idempotent request", attemptFields(at, nil))
+			return false
+		}
+		return true
+	})
+}
+
+// backoff returns the Backoff function of @c.retryPolicy, or the default if not set.
+func (c *Client) backoff() func(int) time.Duration {
+	if c.retryPolicy.Backoff != nil {
+		return c.retryPolicy.Backoff
+	}
+	// Note: using the client timeout as upper bound for the exponential backoff.
+	//       This means the timeout has to be large enough to run MaxAttempts
+	//       requests with individual retries.
+	return ExpJitterBackoff(StepDelay, c.timeout)
+}
+
+// retryDelay computes the delay before the next retry, honouring Retry-After.
+func (c *Client) retryDelay() rehttp.DelayFn {
+	var backoff = c.backoff()
+
+	return rehttp.DelayFn(func(at rehttp.Attempt) time.Duration {
+		var delay = retryAfter(at.Response)
+		var reason = "Retry-After"
+
+		if delay <= 0 {
+			delay, reason = backoff(at.Index), "backoff"
+		}
+		c.log(LevelInfo, "retrying", attemptFields(at, Fields{"retry": at.Index + 1, "delay": delay, "reason": reason}))
+		return delay
+	})
+}
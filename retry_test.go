@@ -0,0 +1,142 @@
+package clcv2_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grrtrr/clcv2"
+	"github.com/grrtrr/clcv2/clcv2test"
+	"github.com/pkg/errors"
+)
+
+// retryPolicy returns the default policy with a fixed @backoff.
+func retryPolicy(backoff time.Duration) clcv2.RetryPolicy {
+	var p = clcv2.DefaultRetryPolicy()
+
+	p.Backoff = func(int) time.Duration { return backoff }
+	return p
+}
+
+// fakeServerWithServer returns a fake server with a server in WA1, and the name of that server.
+func fakeServerWithServer(t *testing.T) (*clcv2test.FakeServer, string) {
+	var f = clcv2test.NewFakeServer("ABCD", "WA1")
+
+	name, err := f.AddServer(f.RootGroup("WA1"), "WEB")
+	if err != nil {
+		f.Close()
+		t.Fatal(err)
+	}
+	return f, name
+}
+
+// Throttled requests are retried even if they are not idempotent.
+func TestRetryThrottled(t *testing.T) {
+	var f, name = fakeServerWithServer(t)
+	var policy = retryPolicy(time.Millisecond)
+	var path = "/v2/operations/ABCD/servers/powerOn"
+
+	defer f.Close()
+
+	policy.RetryNonIdempotent = false
+	client, err := f.Client(clcv2.WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatalf("login failed: %s", err)
+	}
+
+	f.FailRequests("POST", path, 2, http.StatusTooManyRequests, nil)
+	if _, err := client.PowerOnServer(name); err != nil {
+		t.Errorf("PowerOnServer failed: %s", err)
+	}
+	if n := f.Requests("POST", path); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+
+	// Other failures of non-idempotent requests are not retried.
+	f.FailRequests("POST", path, 1, http.StatusServiceUnavailable, nil)
+	if _, err := client.PowerOnServer(name); err == nil {
+		t.Errorf("expected PowerOnServer to fail")
+	} else if n := f.Requests("POST", path); n != 4 {
+		t.Errorf("expected the failed request not to be retried, got %d attempts", n-3)
+	}
+}
+
+// httpDate returns the HTTP-date of the current time plus @d. Since it has a resolution of seconds,
+// the delay until that date lies in (@d-1s, @d].
+func httpDate(d time.Duration) string {
+	return time.Now().Add(d).UTC().Format(http.TimeFormat)
+}
+
+// Retry-After takes precedence over the backoff, both as delta-seconds and as HTTP-date.
+func TestRetryAfter(t *testing.T) {
+	for _, tc := range []struct {
+		desc       string
+		retryAfter func() string
+		backoff    time.Duration
+		minDelay   time.Duration
+	}{
+		{"delta-seconds", func() string { return "1" }, time.Hour, time.Second},
+		{"HTTP-date", func() string { return httpDate(2 * time.Second) }, time.Hour, 900 * time.Millisecond},
+		{"invalid value", func() string { return "soon" }, time.Millisecond, 0},
+		{"date in the past", func() string { return httpDate(-time.Hour) }, time.Millisecond, 0},
+	} {
+		var f, name = fakeServerWithServer(t)
+		var path = "/v2/servers/ABCD/" + name
+
+		client, err := f.Client(clcv2.WithRetryPolicy(retryPolicy(tc.backoff)), clcv2.WithTimeout(10*time.Second))
+		if err != nil {
+			f.Close()
+			t.Fatalf("login failed: %s", err)
+		}
+
+		f.FailRequests("GET", path, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {tc.retryAfter()}})
+		start := time.Now()
+		if _, err := client.GetServer(name); err != nil {
+			t.Errorf("%s: GetServer failed: %s", tc.desc, err)
+		} else if elapsed := time.Since(start); elapsed < tc.minDelay || elapsed > 5*time.Second {
+			t.Errorf("%s: unexpected retry delay %s", tc.desc, elapsed)
+		}
+		f.Close()
+	}
+}
+
+// The number of attempts is bounded by MaxAttempts, unless overridden by WithMaxRetries (in any option order).
+func TestRetryMaxAttempts(t *testing.T) {
+	var policy = func(maxAttempts int) clcv2.RetryPolicy {
+		p := retryPolicy(time.Millisecond)
+		p.MaxAttempts = maxAttempts
+		return p
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		opts     []clcv2.Option
+		attempts int
+	}{
+		{"MaxAttempts 0", []clcv2.Option{clcv2.WithRetryPolicy(policy(0))}, 1},
+		{"MaxAttempts 1", []clcv2.Option{clcv2.WithRetryPolicy(policy(1))}, 1},
+		{"MaxAttempts 3", []clcv2.Option{clcv2.WithRetryPolicy(policy(3))}, 3},
+		{"WithMaxRetries first", []clcv2.Option{clcv2.WithMaxRetries(1), clcv2.WithRetryPolicy(policy(5))}, 2},
+		{"WithMaxRetries last", []clcv2.Option{clcv2.WithRetryPolicy(policy(5)), clcv2.WithMaxRetries(1)}, 2},
+		{"WithMaxRetries 0", []clcv2.Option{clcv2.WithRetryPolicy(policy(5)), clcv2.WithMaxRetries(0)}, 1},
+	} {
+		var f, name = fakeServerWithServer(t)
+		var path = "/v2/servers/ABCD/" + name
+		var apiErr *clcv2.APIError
+
+		client, err := f.Client(tc.opts...)
+		if err != nil {
+			f.Close()
+			t.Fatalf("login failed: %s", err)
+		}
+
+		f.FailRequests("GET", path, 10, http.StatusServiceUnavailable, nil)
+		if _, err := client.GetServer(name); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("%s: expected status 503, got %v", tc.desc, err)
+		}
+		if n := f.Requests("GET", path); n != tc.attempts {
+			t.Errorf("%s: expected %d attempts, got %d", tc.desc, tc.attempts, n)
+		}
+		f.Close()
+	}
+}
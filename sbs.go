@@ -1,6 +1,7 @@
 package clcv2
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -13,15 +14,15 @@ import (
  * Simple Backup API
  */
 const (
-	// SBS root URL
+	// SBS root URL (default value of Endpoints.SBS)
 	SBSurl = "https://api.backup.ctl.io/clc-backup-api/api/"
 )
 
-// getCLCResponse performs a CLC v2 main API request
+// getSBSResponse performs a Simple Backup API request
 // @verb: Http verb to use
-// @path: relative to %SBSurl
-func (c *Client) getSBSResponse(verb, path string, reqModel, resModel interface{}) (err error) {
-	return c.getResponse(SBSurl+path, verb, reqModel, resModel)
+// @path: relative to the SBS endpoint (%SBSurl by default)
+func (c *Client) getSBSResponse(ctx context.Context, verb, path string, reqModel, resModel interface{}) (err error) {
+	return c.getResponse(ctx, FamilySBS, c.endpoints.SBS+path, verb, reqModel, resModel)
 }
 
 // SBSregion represents SBS storage region information
@@ -38,26 +39,46 @@ type SBSregion struct {
 
 // SBSgetAllRegions retrieves a list of backup storage regions which are available in Simple Backup Service.
 func (c *Client) SBSgetAllRegions() (res []SBSregion, err error) {
-	err = c.getSBSResponse("GET", "regions", nil, &res)
+	return c.SBSgetAllRegionsContext(c.ctx)
+}
+
+// SBSgetAllRegionsContext is like SBSgetAllRegions, using @ctx for cancellation.
+func (c *Client) SBSgetAllRegionsContext(ctx context.Context) (res []SBSregion, err error) {
+	err = c.getSBSResponse(ctx, "GET", "regions", nil, &res)
 	return res, err
 }
 
 // SBSgetDatacenters returns a list of CLC data centres.
 func (c *Client) SBSgetDatacenters() (res []string, err error) {
-	err = c.getSBSResponse("GET", "datacenters", nil, &res)
+	return c.SBSgetDatacentersContext(c.ctx)
+}
+
+// SBSgetDatacentersContext is like SBSgetDatacenters, using @ctx for cancellation.
+func (c *Client) SBSgetDatacentersContext(ctx context.Context) (res []string, err error) {
+	err = c.getSBSResponse(ctx, "GET", "datacenters", nil, &res)
 	return res, err
 }
 
 // SBSgetServersByDatacenter returns a list of servers associated with data center @dc.
 func (c *Client) SBSgetServersByDatacenter(dc string) (res []string, err error) {
+	return c.SBSgetServersByDatacenterContext(c.ctx, dc)
+}
+
+// SBSgetServersByDatacenterContext is like SBSgetServersByDatacenter, using @ctx for cancellation.
+func (c *Client) SBSgetServersByDatacenterContext(ctx context.Context, dc string) (res []string, err error) {
 	var u = url.URL{Path: fmt.Sprintf("datacenters/%s/servers", dc)}
-	err = c.getSBSResponse("GET", u.EscapedPath(), nil, &res)
+	err = c.getSBSResponse(ctx, "GET", u.EscapedPath(), nil, &res)
 	return res, err
 }
 
 // SBSgetOsTypes returns the list of Operating System Types supported by the Simple Backup Service.
 func (c *Client) SBSgetOsTypes() (res []string, err error) {
-	err = c.getSBSResponse("GET", "osTypes", nil, &res)
+	return c.SBSgetOsTypesContext(c.ctx)
+}
+
+// SBSgetOsTypesContext is like SBSgetOsTypes, using @ctx for cancellation.
+func (c *Client) SBSgetOsTypesContext(ctx context.Context) (res []string, err error) {
+	err = c.getSBSResponse(ctx, "GET", "osTypes", nil, &res)
 	return res, err
 }
 
@@ -94,38 +115,63 @@ type SBSAccountPolicy struct {
 
 // SBScreatePolicy creates a new Account Policy
 func (c *Client) SBScreatePolicy(req *SBSAccountPolicy) (res SBSAccountPolicy, err error) {
-	err = c.getSBSResponse("POST", "accountPolicies", req, &res)
+	return c.SBScreatePolicyContext(c.ctx, req)
+}
+
+// SBScreatePolicyContext is like SBScreatePolicy, using @ctx for cancellation.
+func (c *Client) SBScreatePolicyContext(ctx context.Context, req *SBSAccountPolicy) (res SBSAccountPolicy, err error) {
+	err = c.getSBSResponse(ctx, "POST", "accountPolicies", req, &res)
 	return res, err
 }
 
 // SBSupdatePolicy updates an existing Account Policy
 func (c *Client) SBSupdatePolicy(policyID string, req *SBSAccountPolicy) (res SBSAccountPolicy, err error) {
-	err = c.getSBSResponse("PUT", fmt.Sprintf("accountPolicies/%s", policyID), req, &res)
+	return c.SBSupdatePolicyContext(c.ctx, policyID, req)
+}
+
+// SBSupdatePolicyContext is like SBSupdatePolicy, using @ctx for cancellation.
+func (c *Client) SBSupdatePolicyContext(ctx context.Context, policyID string, req *SBSAccountPolicy) (res SBSAccountPolicy, err error) {
+	err = c.getSBSResponse(ctx, "PUT", fmt.Sprintf("accountPolicies/%s", policyID), req, &res)
 	return res, err
 }
 
 // SBSgetPolicy returns the single Policy associated with @policyID, or an error.
 func (c *Client) SBSgetPolicy(policyID string) (res SBSAccountPolicy, err error) {
-	err = c.getSBSResponse("GET", fmt.Sprintf("accountPolicies/%s", policyID), nil, &res)
+	return c.SBSgetPolicyContext(c.ctx, policyID)
+}
+
+// SBSgetPolicyContext is like SBSgetPolicy, using @ctx for cancellation.
+func (c *Client) SBSgetPolicyContext(ctx context.Context, policyID string) (res SBSAccountPolicy, err error) {
+	err = c.getSBSResponse(ctx, "GET", fmt.Sprintf("accountPolicies/%s", policyID), nil, &res)
 	return res, err
 }
 
 // SBSgetPolicies returns the list of SBS backup policies associated with an account.
 func (c *Client) SBSgetPolicies() ([]SBSAccountPolicy, error) {
+	return c.SBSgetPoliciesContext(c.ctx)
+}
+
+// SBSgetPoliciesContext is like SBSgetPolicies, using @ctx for cancellation.
+func (c *Client) SBSgetPoliciesContext(ctx context.Context) ([]SBSAccountPolicy, error) {
 	// Note: we do not paging for this API, so just wrap it in anonymous struct.
 	var result struct {
 		Results []SBSAccountPolicy
 	}
-	err := c.getSBSResponse("GET", "accountPolicies", nil, &result)
+	err := c.getSBSResponse(ctx, "GET", "accountPolicies", nil, &result)
 	return result.Results, err
 }
 
 // SBSgetEligiblePolicies returns the list of Account Policies eligible for the specified @server.
 func (c *Client) SBSgetEligiblePolicies(server string) ([]SBSAccountPolicy, error) {
+	return c.SBSgetEligiblePoliciesContext(c.ctx, server)
+}
+
+// SBSgetEligiblePoliciesContext is like SBSgetEligiblePolicies, using @ctx for cancellation.
+func (c *Client) SBSgetEligiblePoliciesContext(ctx context.Context, server string) ([]SBSAccountPolicy, error) {
 	var result struct {
 		Results []SBSAccountPolicy
 	}
-	err := c.getSBSResponse("GET", fmt.Sprintf("accountPolicies/servers/%s", server), nil, &result)
+	err := c.getSBSResponse(ctx, "GET", fmt.Sprintf("accountPolicies/servers/%s", server), nil, &result)
 	return result.Results, err
 }
 
@@ -206,7 +252,12 @@ type SBSServerPolicy struct {
 
 // SBScreateServerPolicy creates a new Server Policy for the given @server, Account Policy ID, and @region.
 func (c *Client) SBScreateServerPolicy(acPolicyID, server, region string) (res SBSServerPolicy, err error) {
-	err = c.getSBSResponse("POST", fmt.Sprintf("accountPolicies/%s/serverPolicies", acPolicyID),
+	return c.SBScreateServerPolicyContext(c.ctx, acPolicyID, server, region)
+}
+
+// SBScreateServerPolicyContext is like SBScreateServerPolicy, using @ctx for cancellation.
+func (c *Client) SBScreateServerPolicyContext(ctx context.Context, acPolicyID, server, region string) (res SBSServerPolicy, err error) {
+	err = c.getSBSResponse(ctx, "POST", fmt.Sprintf("accountPolicies/%s/serverPolicies", acPolicyID),
 		struct {
 			Account string `json:"clcAccountAlias"`
 			Server  string `json:"serverId"`
@@ -217,37 +268,57 @@ func (c *Client) SBScreateServerPolicy(acPolicyID, server, region string) (res S
 
 // SBSdeleteServerPolicy deletes the Server Policy specified by @srvPolicyID
 func (c *Client) SBSdeleteServerPolicy(srvPolicyID string) error {
-	p, err := c.SBSgetServerPolicy(srvPolicyID)
+	return c.SBSdeleteServerPolicyContext(c.ctx, srvPolicyID)
+}
+
+// SBSdeleteServerPolicyContext is like SBSdeleteServerPolicy, using @ctx for cancellation.
+func (c *Client) SBSdeleteServerPolicyContext(ctx context.Context, srvPolicyID string) error {
+	p, err := c.SBSgetServerPolicyContext(ctx, srvPolicyID)
 	if err != nil {
 		return err
 	}
 	path := fmt.Sprintf("accountPolicies/%s/serverPolicies/%s", p.AccountPolicyID, p.ID)
-	return c.getSBSResponse("DELETE", path, nil, nil)
+	return c.getSBSResponse(ctx, "DELETE", path, nil, nil)
 }
 
 // SBSgetServerPolicies returns a list of Server Policies associated to an Account Policy
 func (c *Client) SBSgetServerPolicies(acPolicyId string) ([]SBSServerPolicy, error) {
+	return c.SBSgetServerPoliciesContext(c.ctx, acPolicyId)
+}
+
+// SBSgetServerPoliciesContext is like SBSgetServerPolicies, using @ctx for cancellation.
+func (c *Client) SBSgetServerPoliciesContext(ctx context.Context, acPolicyId string) ([]SBSServerPolicy, error) {
 	var result struct {
 		Results []SBSServerPolicy
 	}
-	err := c.getSBSResponse("GET", fmt.Sprintf("accountPolicies/%s/serverPolicies", acPolicyId), nil, &result)
+	err := c.getSBSResponse(ctx, "GET", fmt.Sprintf("accountPolicies/%s/serverPolicies", acPolicyId), nil, &result)
 	return result.Results, err
 }
 
 // SBSgetServerPolicyDetails returns SBS policy details associated with a single @server.
 func (c *Client) SBSgetServerPolicyDetails(server string) (res []SBSServerPolicy, err error) {
-	err = c.getSBSResponse("GET", fmt.Sprintf("serverPolicyDetails?serverId=%s", server), nil, &res)
+	return c.SBSgetServerPolicyDetailsContext(c.ctx, server)
+}
+
+// SBSgetServerPolicyDetailsContext is like SBSgetServerPolicyDetails, using @ctx for cancellation.
+func (c *Client) SBSgetServerPolicyDetailsContext(ctx context.Context, server string) (res []SBSServerPolicy, err error) {
+	err = c.getSBSResponse(ctx, "GET", fmt.Sprintf("serverPolicyDetails?serverId=%s", server), nil, &res)
 	return res, err
 }
 
 // SBSgetServerPolicy list SBS server policy details of the given @serverPolicyId
 func (c *Client) SBSgetServerPolicy(serverPolicyId string) (*SBSServerPolicy, error) {
-	acPolicies, err := c.SBSgetPolicies()
+	return c.SBSgetServerPolicyContext(c.ctx, serverPolicyId)
+}
+
+// SBSgetServerPolicyContext is like SBSgetServerPolicy, using @ctx for cancellation.
+func (c *Client) SBSgetServerPolicyContext(ctx context.Context, serverPolicyId string) (*SBSServerPolicy, error) {
+	acPolicies, err := c.SBSgetPoliciesContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 	for _, acPolicy := range acPolicies {
-		srvPolicies, err := c.SBSgetServerPolicies(acPolicy.PolicyID)
+		srvPolicies, err := c.SBSgetServerPoliciesContext(ctx, acPolicy.PolicyID)
 		if err != nil {
 			return nil, err
 		}
@@ -263,12 +334,17 @@ func (c *Client) SBSgetServerPolicy(serverPolicyId string) (*SBSServerPolicy, er
 // SBSpatchServerPolicyStatus sets the status of the specified Server Policy to @newValue.
 // Note: this command seems unsupported. In my tests, it always returned 200 and did nothing.
 func (c *Client) SBSpatchServerPolicyStatus(srvPolicyID, newValue string) (p *SBSServerPolicy, err error) {
-	p, err = c.SBSgetServerPolicy(srvPolicyID)
+	return c.SBSpatchServerPolicyStatusContext(c.ctx, srvPolicyID, newValue)
+}
+
+// SBSpatchServerPolicyStatusContext is like SBSpatchServerPolicyStatus, using @ctx for cancellation.
+func (c *Client) SBSpatchServerPolicyStatusContext(ctx context.Context, srvPolicyID, newValue string) (p *SBSServerPolicy, err error) {
+	p, err = c.SBSgetServerPolicyContext(ctx, srvPolicyID)
 	if err != nil {
 		return p, err
 	}
 
-	err = c.getSBSResponse("PATCH",
+	err = c.getSBSResponse(ctx, "PATCH",
 		fmt.Sprintf("accountPolicies/%s/serverPolicies/%s", p.AccountPolicyID, p.ID),
 		struct {
 			// According to v2 documentation 2016-08-22, only supported op is 'replace',
@@ -337,6 +413,11 @@ type SBSRestorePoint struct {
 // @start:     start time (date) of the backup to list
 // @end:       end time (date) of the backup to list
 func (c *Client) SBSgetRestorePointDetails(acPolicy, srvPolicy string, start, end time.Time) ([]SBSRestorePoint, error) {
+	return c.SBSgetRestorePointDetailsContext(c.ctx, acPolicy, srvPolicy, start, end)
+}
+
+// SBSgetRestorePointDetailsContext is like SBSgetRestorePointDetails, using @ctx for cancellation.
+func (c *Client) SBSgetRestorePointDetailsContext(ctx context.Context, acPolicy, srvPolicy string, start, end time.Time) ([]SBSRestorePoint, error) {
 	var path = fmt.Sprintf("accountPolicies/%s/serverPolicies/%s/restorePointDetails?"+
 		"backupFinishedStartDate=%s&backupFinishedEndDate=%s",
 		acPolicy, srvPolicy, start.Format("2006-01-02"), end.Format("2006-01-02"))
@@ -344,12 +425,17 @@ func (c *Client) SBSgetRestorePointDetails(acPolicy, srvPolicy string, start, en
 		Results []SBSRestorePoint
 	}
 
-	err := c.getSBSResponse("GET", path, nil, &result)
+	err := c.getSBSResponse(ctx, "GET", path, nil, &result)
 	return result.Results, err
 }
 
 // SBSgetServerStorageUsage returns the number of bytes used by the specified Server Policy on a given @day.
 func (c *Client) SBSgetServerStorageUsage(acPolicy, srvPolicy string, day time.Time) (uint64, error) {
+	return c.SBSgetServerStorageUsageContext(c.ctx, acPolicy, srvPolicy, day)
+}
+
+// SBSgetServerStorageUsageContext is like SBSgetServerStorageUsage, using @ctx for cancellation.
+func (c *Client) SBSgetServerStorageUsageContext(ctx context.Context, acPolicy, srvPolicy string, day time.Time) (uint64, error) {
 	var path = fmt.Sprintf("accountPolicies/%s/serverPolicies/%s/storedData?searchDate=%s",
 		acPolicy, srvPolicy, day.Format("2006-01-02"))
 	var result struct {
@@ -357,7 +443,7 @@ func (c *Client) SBSgetServerStorageUsage(acPolicy, srvPolicy string, day time.T
 		BytesStored     string // Why are they converting numeric quantities into strings?
 	}
 
-	if err := c.getSBSResponse("GET", path, nil, &result); err != nil {
+	if err := c.getSBSResponse(ctx, "GET", path, nil, &result); err != nil {
 		return 0, err
 	}
 	val, err := strconv.ParseUint(result.BytesStored, 10, 64)
@@ -0,0 +1,223 @@
+package clcv2
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// Version of the encrypted secrets file format
+	secretFileVersion = 1
+
+	// PBKDF2 iteration count used to derive the encryption key
+	secretFileIterations = 200000
+)
+
+// FileSecretStore is a SecretStore that keeps all secrets in a single file. The file is encrypted
+// via AES-256-GCM, using a key derived (PBKDF2-SHA256) from a passphrase or the content of a key file.
+type FileSecretStore struct {
+	path   string
+	secret func() ([]byte, error)
+	mu     sync.Mutex
+
+	// Cached key derived for @salt, to avoid repeating the (deliberately slow) key derivation
+	salt, key []byte
+}
+
+// secretFile is the on-disk format of a FileSecretStore.
+type secretFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"` // encrypted JSON map of key -> secret
+}
+
+// NewFileSecretStore returns a FileSecretStore that is stored at @path.
+// @secret returns the passphrase or key-file content to derive the encryption key from.
+// It is only called when the file is accessed.
+func NewFileSecretStore(path string, secret func() ([]byte, error)) *FileSecretStore {
+	return &FileSecretStore{path: path, secret: secret}
+}
+
+// Get implements SecretStore.
+func (s *FileSecretStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	return secrets[key], nil
+}
+
+// Set implements SecretStore. Concurrent updates by other processes are serialized via a lock file.
+func (s *FileSecretStore) Set(key, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := lockFile(s.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	} else if secrets[key] == secret {
+		return nil
+	} else if secret == "" {
+		delete(secrets, key)
+	} else {
+		secrets[key] = secret
+	}
+	return s.save(secrets)
+}
+
+// load decrypts the secrets stored in @s.path. A missing file yields an empty result.
+func (s *FileSecretStore) load() (map[string]string, error) {
+	var sf secretFile
+	var secrets = make(map[string]string)
+
+	content, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return secrets, nil
+	} else if err != nil {
+		return nil, errors.Errorf("failed to read secrets: %s", err)
+	} else if err = json.Unmarshal(content, &sf); err != nil {
+		return nil, errors.Errorf("failed to deserialize %s: %s", s.path, err)
+	} else if sf.Version != secretFileVersion {
+		return nil, errors.Errorf("%s: unsupported version %d", s.path, sf.Version)
+	}
+
+	aead, err := s.cipher(sf.Salt, sf.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, sf.Nonce, sf.Data, nil)
+	if err != nil {
+		return nil, errors.Errorf("failed to decrypt %s (wrong passphrase or key file?)", s.path)
+	} else if err = json.Unmarshal(plain, &secrets); err != nil {
+		return nil, errors.Errorf("failed to deserialize secrets in %s: %s", s.path, err)
+	}
+	return secrets, nil
+}
+
+// save encrypts @secrets, using a fresh nonce, and atomically replaces @s.path.
+func (s *FileSecretStore) save(secrets map[string]string) error {
+	var sf = secretFile{
+		Version:    secretFileVersion,
+		Iterations: secretFileIterations,
+		Salt:       s.salt,
+	}
+
+	if sf.Salt == nil {
+		sf.Salt = make([]byte, 16)
+		if _, err := rand.Read(sf.Salt); err != nil {
+			return err
+		}
+	}
+	aead, err := s.cipher(sf.Salt, sf.Iterations)
+	if err != nil {
+		return err
+	}
+	sf.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(sf.Nonce); err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return errors.Errorf("failed to serialize secrets: %s", err)
+	}
+	sf.Data = aead.Seal(nil, sf.Nonce, plain, nil)
+
+	enc, err := json.MarshalIndent(sf, "", "\t")
+	if err != nil {
+		return errors.Errorf("failed to serialize %s: %s", s.path, err)
+	}
+	return writeFileAtomic(s.path, append(enc, '\n'), 0600)
+}
+
+// cipher returns the AEAD for the given @salt and @iterations.
+func (s *FileSecretStore) cipher(salt []byte, iterations int) (cipher.AEAD, error) {
+	if s.key == nil || !bytes.Equal(salt, s.salt) {
+		secret, err := s.secret()
+		if err != nil {
+			return nil, err
+		} else if len(secret) == 0 {
+			return nil, errors.Errorf("empty passphrase for %s", s.path)
+		}
+		s.salt, s.key = salt, pbkdf2.Key(secret, salt, iterations, 32, sha256.New)
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// loadKeyFile returns the content of the key file at @path. If it does not exist yet,
+// a key file with a random key is created.
+func loadKeyFile(path string) ([]byte, error) {
+	key, err := ioutil.ReadFile(path)
+	if err == nil {
+		return []byte(strings.TrimSpace(string(key))), nil
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Errorf("failed to read key file: %s", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	key = []byte(hex.EncodeToString(key))
+	if err := writeFileAtomic(path, append(key, '\n'), 0600); err != nil {
+		return nil, errors.Errorf("failed to create key file: %s", err)
+	}
+	return key, nil
+}
+
+// writeFileAtomic writes @data to @fileName via a temporary file, creating the parent directory if needed.
+func writeFileAtomic(fileName string, data []byte, perm os.FileMode) error {
+	var dir = path.Dir(fileName)
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Errorf("failed to create directory %s: %s", dir, err)
+	}
+	tmp, err := ioutil.TempFile(dir, path.Base(fileName)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after successful rename
+
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perm)
+	}
+	if err != nil {
+		return errors.Errorf("failed to write %s: %s", fileName, err)
+	}
+	return os.Rename(tmp.Name(), fileName)
+}
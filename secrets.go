@@ -0,0 +1,172 @@
+package clcv2
+
+/*
+ * Pluggable storage for the secrets of command-line clients (portal password, bearer token),
+ * so that these are not kept in cleartext in CLC_HOME.
+ */
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SecretBackendFile stores secrets in a file encrypted via a passphrase or key file (default).
+	SecretBackendFile = "file"
+
+	// SecretBackendCommand retrieves/stores secrets via external commands (e.g. pass, gopass).
+	SecretBackendCommand = "command"
+
+	// Environment variable holding the passphrase of the encrypted secrets file.
+	// If not set, the key file is used instead.
+	passphraseEnv = "CLC_PASSPHRASE"
+
+	// Default names in CLC_HOME of the encrypted secrets file and its key file
+	secretsName = "secrets.enc"
+	keyFileName = "secrets.key"
+)
+
+// ErrReadOnlySecretStore is returned when storing a secret in a backend that does not support it.
+var ErrReadOnlySecretStore = errors.New("secret store is read-only")
+
+// SecretStore is a backend for secrets, which are identified by a key.
+type SecretStore interface {
+	// Get returns the secret stored under @key, or "" if there is none.
+	Get(key string) (string, error)
+
+	// Set stores @secret under @key. An empty @secret removes @key.
+	Set(key, secret string) error
+}
+
+// SecretConfig selects and configures the SecretStore of a ClientConfig.
+type SecretConfig struct {
+	// %SecretBackendFile (default if empty) or %SecretBackendCommand
+	Backend string `yaml:"Backend,omitempty"`
+
+	// File backend: encrypted file (defaults to CLC_HOME/secrets.enc)
+	File string `yaml:"File,omitempty"`
+
+	// File backend: key file used if CLC_PASSPHRASE is not set (defaults to CLC_HOME/secrets.key,
+	// which is generated on first use).
+	// NOTE: the default key file sits next to the encrypted file, so anyone who can read CLC_HOME can
+	//       decrypt the secrets. It only protects copies of the encrypted file alone (e.g. in backups).
+	//       Set CLC_PASSPHRASE, point this at a separate location (e.g. removable media), or use the
+	//       command backend to protect the secrets against other readers of CLC_HOME.
+	KeyFile string `yaml:"KeyFile,omitempty"`
+
+	// Command backend: shell command that prints the secret of key %s on stdout, e.g. "pass show clc/%s".
+	// The key is substituted shell-quoted, hence %s must not be enclosed in quotes.
+	Command string `yaml:"Command,omitempty"`
+
+	// Command backend: optional shell command that stores the secret read from stdin under key %s,
+	// e.g. "pass insert -m -f clc/%s". Without it, the command backend is read-only.
+	StoreCommand string `yaml:"StoreCommand,omitempty"`
+
+	// Command backend: optional shell command that removes the secret of key %s, e.g. "pass rm -f clc/%s".
+	RemoveCommand string `yaml:"RemoveCommand,omitempty"`
+}
+
+// Store returns the SecretStore configured by @s.
+func (s SecretConfig) Store() (SecretStore, error) {
+	switch s.Backend {
+	case "", SecretBackendFile:
+		var file, keyFile = s.File, s.KeyFile
+
+		if file == "" {
+			file = path.Join(GetClcHome(), secretsName)
+		}
+		if keyFile == "" {
+			keyFile = path.Join(GetClcHome(), keyFileName)
+		}
+		return NewFileSecretStore(file, func() ([]byte, error) {
+			if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+				return []byte(passphrase), nil
+			}
+			return loadKeyFile(keyFile)
+		}), nil
+	case SecretBackendCommand:
+		if s.Command == "" {
+			return nil, errors.Errorf("%s secret backend requires a command", s.Backend)
+		}
+		return &CommandSecretStore{GetCommand: s.Command, SetCommand: s.StoreCommand, RemoveCommand: s.RemoveCommand}, nil
+	}
+	return nil, errors.Errorf("unsupported secret backend %q", s.Backend)
+}
+
+// CommandSecretStore is a SecretStore that delegates to external commands, run via the shell.
+// In all commands, each %s is replaced by the shell-quoted key of the secret.
+type CommandSecretStore struct {
+	// Prints the secret on stdout (a trailing newline is removed). If it fails or prints nothing,
+	// there is no secret for the key.
+	GetCommand string
+
+	// Reads the secret from stdin (optional)
+	SetCommand string
+
+	// Removes the secret (optional)
+	RemoveCommand string
+}
+
+// Get implements SecretStore.
+func (s *CommandSecretStore) Get(key string) (string, error) {
+	var stdout bytes.Buffer
+
+	if err := runShell(fmtCommand(s.GetCommand, key), nil, &stdout); err != nil {
+		if _, ok := errors.Cause(err).(*exec.ExitError); ok {
+			return "", nil // password managers fail on missing entries
+		}
+		return "", err
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+// Set implements SecretStore. Removing a secret requires @s.RemoveCommand.
+func (s *CommandSecretStore) Set(key, secret string) error {
+	if secret == "" {
+		if s.RemoveCommand == "" {
+			return ErrReadOnlySecretStore
+		}
+		return runShell(fmtCommand(s.RemoveCommand, key), nil, nil)
+	} else if s.SetCommand == "" {
+		return ErrReadOnlySecretStore
+	}
+	return runShell(fmtCommand(s.SetCommand, key), strings.NewReader(secret+"\n"), nil)
+}
+
+// fmtCommand substitutes the shell-quoted @key for each %s in the command template @cmdFmt.
+// Keys contain user names, which must not be interpreted by the shell.
+func fmtCommand(cmdFmt, key string) string {
+	return strings.Replace(cmdFmt, "%s", shellQuote(key), -1)
+}
+
+// runShell runs @cmdLine via the shell, with the given @stdin and @stdout.
+func runShell(cmdLine string, stdin io.Reader, stdout io.Writer) error {
+	var stderr bytes.Buffer
+	var cmd *exec.Cmd
+
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", cmdLine)
+	} else {
+		cmd = exec.Command("/bin/sh", "-c", cmdLine)
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.Wrapf(err, "command %q failed: %s", cmdLine, msg)
+		}
+		return errors.Wrapf(err, "command %q failed", cmdLine)
+	}
+	return nil
+}
+
+// passwordKey returns the SecretStore key of the portal password of @user.
+func passwordKey(user string) string {
+	return "password/" + strings.ToLower(user)
+}
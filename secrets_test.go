@@ -0,0 +1,151 @@
+package clcv2
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Keys are passed to the commands of a CommandSecretStore literally, whatever shell syntax they contain.
+func TestCommandSecretStoreQuoting(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+	dir, err := ioutil.TempDir("", "clcv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var marker = path.Join(dir, "injected")
+	var store = &CommandSecretStore{GetCommand: "echo %s"}
+
+	for _, user := range []string{
+		"user; touch " + marker,
+		"$(touch " + marker + ")",
+		"`touch " + marker + "`",
+		"o'brien'; touch " + marker + "; echo '",
+		`"quoted" %d %v`,
+	} {
+		key := passwordKey(user)
+		if secret, err := store.Get(key); err != nil {
+			t.Errorf("Get(%q) failed: %s", key, err)
+		} else if secret != key {
+			t.Errorf("expected the command to receive %q, got %q", key, secret)
+		}
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Errorf("key was interpreted by the shell")
+	}
+}
+
+// A CommandSecretStore follows the SecretStore contract: missing secrets are "", and an empty secret removes the key.
+func TestCommandSecretStoreContract(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+	dir, err := ioutil.TempDir("", "clcv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var file = shellQuote(dir) + "/%s"
+	var store = &CommandSecretStore{
+		GetCommand:    "cat " + file,
+		SetCommand:    "cat > " + file,
+		RemoveCommand: "rm " + file,
+	}
+
+	if secret, err := store.Get("token"); err != nil || secret != "" {
+		t.Errorf("expected no secret for a failing command, got %q, %v", secret, err)
+	}
+	if err := store.Set("token", "s3cret"); err != nil {
+		t.Fatalf("Set failed: %s", err)
+	} else if secret, err := store.Get("token"); err != nil || secret != "s3cret" {
+		t.Errorf("expected the stored secret, got %q, %v", secret, err)
+	}
+	if err := store.Set("token", ""); err != nil {
+		t.Fatalf("removing the secret failed: %s", err)
+	} else if _, err := os.Stat(path.Join(dir, "token")); !os.IsNotExist(err) {
+		t.Errorf("expected the secret to be removed, got %v", err)
+	} else if secret, err := store.Get("token"); err != nil || secret != "" {
+		t.Errorf("expected no secret after removal, got %q, %v", secret, err)
+	}
+
+	// Empty output means no secret, too.
+	if secret, err := (&CommandSecretStore{GetCommand: "printf '\\n'"}).Get("token"); err != nil || secret != "" {
+		t.Errorf("expected no secret for empty output, got %q, %v", secret, err)
+	}
+
+	// Without the respective command, neither storing nor removing is possible.
+	store = &CommandSecretStore{GetCommand: "true", SetCommand: "cat >/dev/null"}
+	if err := store.Set("token", ""); err != ErrReadOnlySecretStore {
+		t.Errorf("expected removal without RemoveCommand to fail, got %v", err)
+	}
+	store = &CommandSecretStore{GetCommand: "true", RemoveCommand: "true"}
+	if err := store.Set("token", "s3cret"); err != ErrReadOnlySecretStore {
+		t.Errorf("expected storing without SetCommand to fail, got %v", err)
+	}
+}
+
+// Secrets survive a round trip through the encrypted file, which cannot be decrypted with another passphrase.
+func TestFileSecretStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clcv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var file = path.Join(dir, secretsName)
+	var passphrase = func(p string) func() ([]byte, error) {
+		return func() ([]byte, error) { return []byte(p), nil }
+	}
+
+	if err := NewFileSecretStore(file, passphrase("right")).Set("password/user", "s3cret"); err != nil {
+		t.Fatalf("Set failed: %s", err)
+	}
+	if content, err := ioutil.ReadFile(file); err != nil || strings.Contains(string(content), "s3cret") {
+		t.Errorf("expected an encrypted file, got %q, %v", content, err)
+	}
+
+	store := NewFileSecretStore(file, passphrase("right"))
+	if secret, err := store.Get("password/user"); err != nil || secret != "s3cret" {
+		t.Errorf("expected the stored secret, got %q, %v", secret, err)
+	} else if err := store.Set("password/user", ""); err != nil {
+		t.Errorf("removing the secret failed: %s", err)
+	} else if secret, err := store.Get("password/user"); err != nil || secret != "" {
+		t.Errorf("expected no secret after removal, got %q, %v", secret, err)
+	}
+	if _, err := NewFileSecretStore(file, passphrase("wrong")).Get("password/user"); err == nil {
+		t.Errorf("expected decryption with the wrong passphrase to fail")
+	}
+}
+
+// Saving a configuration does not fail because its read-only secret store cannot hold the password.
+func TestSaveReadOnlySecretStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clcv2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer os.Setenv("CLC_HOME", os.Getenv("CLC_HOME"))
+	os.Setenv("CLC_HOME", dir)
+
+	var f = &ConfigFile{
+		Secrets:  SecretConfig{Backend: SecretBackendCommand, Command: "exit 1"},
+		Profiles: map[string]*ClientConfig{DefaultProfile: {Username: "user", Password: "from-flag"}},
+	}
+	if err := f.Save(); err != nil {
+		t.Fatalf("Save failed: %s", err)
+	}
+	content, err := ioutil.ReadFile(path.Join(dir, configName))
+	if err != nil {
+		t.Fatalf("configuration was not written: %s", err)
+	} else if len(content) == 0 || strings.Contains(string(content), "from-flag") {
+		t.Errorf("expected configuration without password, got %q", content)
+	}
+}
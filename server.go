@@ -1,6 +1,7 @@
 package clcv2
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -162,24 +163,40 @@ func (s *ServerIPAddress) IsPublic() bool {
 // Query Server details by URI path.
 // @path: relative path of the server, as e.g. returned via 'self' link in CreateServer
 func (c *Client) GetServerByURI(path string) (res Server, err error) {
-	err = c.getCLCResponse("GET", path, nil, &res)
+	return c.GetServerByURIContext(c.ctx, path)
+}
+
+// GetServerByURIContext is like GetServerByURI, using @ctx for cancellation.
+func (c *Client) GetServerByURIContext(ctx context.Context, path string) (res Server, err error) {
+	err = c.getCLCResponse(ctx, "GET", path, nil, &res)
 	return res, err
 }
 
 // Get the details for a individual server.
 // @serverId: name of the server being queried (e.g. WA1DTGDFEDAD0)
 func (c *Client) GetServer(serverId string) (res Server, err error) {
+	return c.GetServerContext(c.ctx, serverId)
+}
+
+// GetServerContext is like GetServer, using @ctx for cancellation.
+func (c *Client) GetServerContext(ctx context.Context, serverId string) (res Server, err error) {
 	// Note: there exists a second way of querying a server. If @serverId is a hex UUID,
 	//       then use "/v2/servers/%s/%s?uuid=True" instead.
-	return c.GetServerByURI(fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId))
+	return c.GetServerByURIContext(ctx, fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId))
 }
 
 // GetServerNets returns the networks associated with the server @s.
+// Queries the networks of the effective @c.AccountAlias, and those of the registered (parent) account.
 func (c *Client) GetServerNets(s Server) (nets []Network, err error) {
+	return c.GetServerNetsContext(c.ctx, s)
+}
+
+// GetServerNetsContext is like GetServerNets, using @ctx for cancellation.
+func (c *Client) GetServerNetsContext(ctx context.Context, s Server) (nets []Network, err error) {
 	var seen = make(map[string]bool) /* map { networkId -> bool */
 
 	// The GetNetworks() call returns only the networks visible to the current account.
-	networks, err := c.GetNetworks(s.LocationId, c.AccountAlias)
+	networks, err := c.GetNetworksContext(ctx, s.LocationId, c.AccountAlias)
 	if err != nil {
 		return nil, errors.Errorf("failed to query %s networks in %s: %s", c.AccountAlias, s.LocationId, err)
 	}
@@ -188,7 +205,7 @@ func (c *Client) GetServerNets(s Server) (nets []Network, err error) {
 	// using a network owned by the parent's account. If that is the case,	the results will
 	// be empty, and the credentials of the parent account are needed to obtain the details.
 	if parentAcct := c.RegisteredAccountAlias(); parentAcct != c.AccountAlias {
-		if parentNetworks, err := c.GetNetworks(s.LocationId, parentAcct); err != nil {
+		if parentNetworks, err := c.GetNetworksContext(ctx, s.LocationId, parentAcct); err != nil {
 			return nil, errors.Errorf("failed to query %s networks in %s: %s", parentAcct, s.LocationId, err)
 		} else {
 			networks = append(networks, parentNetworks...)
@@ -214,7 +231,12 @@ func (c *Client) GetServerNets(s Server) (nets []Network, err error) {
 
 // GetIPs returns the (private, public) IP addresses associated with @serverID
 func (c *Client) GetServerIPs(serverId string) (ips []string, err error) {
-	srv, err := c.GetServer(serverId)
+	return c.GetServerIPsContext(c.ctx, serverId)
+}
+
+// GetServerIPsContext is like GetServerIPs, using @ctx for cancellation.
+func (c *Client) GetServerIPsContext(ctx context.Context, serverId string) (ips []string, err error) {
+	srv, err := c.GetServerContext(ctx, serverId)
 	if err != nil {
 		return nil, err
 	}
@@ -327,29 +349,95 @@ type CreateServerReq struct {
 // Create a new server.
 // @serverId: ID of the server to be deleted.
 // Returns new server @url and @statusId if successful.
+// Failed requests are only retried if the server does not exist yet (see RetryPolicy). If a server created by a failed
+// request was found instead, its @url is returned, with an empty @statusId. To recognize it, the description of the
+// new server carries a random correlation marker, e.g. "web server [clcv2:3f2a9c81d0e4]", unless
+// RetryPolicy.RetryNonIdempotent is disabled.
 func (c *Client) CreateServer(req *CreateServerReq) (url, statusId string, err error) {
+	return c.CreateServerContext(c.ctx, req)
+}
+
+// CreateServerContext is like CreateServer, using @ctx for cancellation.
+func (c *Client) CreateServerContext(ctx context.Context, req *CreateServerReq) (url, statusId string, err error) {
 	var path = fmt.Sprintf("/v2/servers/%s", c.AccountAlias)
+	var existing map[string]bool // servers in the target group before the request
+	var marker string            // correlation marker in the description of the new server
+	var body = req
+
+	if c.retryPolicy.RetryNonIdempotent {
+		group, err := c.GetGroupContext(ctx, req.GroupId)
+		if err != nil {
+			return "", "", errors.Errorf("failed to query group %s: %s", req.GroupId, err)
+		}
+		existing = linkIDs(group.Links, "server")
 
-	if status, err := c.getStatusResponse("POST", path, false, req); err != nil {
-		return "", "", err
-	} else if link, err := extractLink(status.Links, "status"); err != nil {
-		return "", "", err
-	} else {
+		marker, body = newCreateMarker(), new(CreateServerReq)
+		*body = *req
+		body.Description = withMarker(req.Description, marker)
+	}
+
+	err = c.createOnce(ctx, "server "+req.Name, func(ctx context.Context) error {
+		status, err := c.getStatusResponse(ctx, "POST", path, false, body)
+		if err != nil {
+			return err
+		} else if link, err := extractLink(status.Links, "status"); err != nil {
+			return err
+		} else {
+			statusId = link.Id
+		}
 		/* Sanity checks: err != nil only if extractLink fails for expected links. */
-		statusId = link.Id
-		if link, err = extractLink(status.Links, "self"); err == nil {
+		if link, err := extractLink(status.Links, "self"); err == nil {
 			url = link.Href
 		}
+		return nil
+	}, func(ctx context.Context) (found bool, err error) {
+		url, err = c.findCreatedServer(ctx, req.GroupId, marker, existing)
+		return url != "", err
+	})
+	if err != nil {
+		return "", "", err
 	}
 	return url, statusId, nil
 }
 
+// CreateServerJob is like CreateServer, but returns the Job of the operation (see StatusJob) along with
+// the server @url. The Job of an adopted server (see CreateServer) has already succeeded.
+func (c *Client) CreateServerJob(req *CreateServerReq) (url string, job Job, err error) {
+	return c.CreateServerJobContext(c.ctx, req)
+}
+
+// CreateServerJobContext is like CreateServerJob, using @ctx for cancellation.
+func (c *Client) CreateServerJobContext(ctx context.Context, req *CreateServerReq) (url string, job Job, err error) {
+	url, statusId, err := c.CreateServerContext(ctx, req)
+	if err != nil {
+		return "", nil, err
+	} else if statusId == "" {
+		return url, c.completedJob("server creation", url), nil
+	}
+	return url, c.StatusJob(statusId), nil
+}
+
 // Send the delete operation to a given server and add operation to queue.
 // @serverId: ID of the server to be deleted.
 func (c *Client) DeleteServer(serverId string) (statusId string, err error) {
+	return c.DeleteServerContext(c.ctx, serverId)
+}
+
+// DeleteServerContext is like DeleteServer, using @ctx for cancellation.
+func (c *Client) DeleteServerContext(ctx context.Context, serverId string) (statusId string, err error) {
 	var path = fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId)
 
-	return c.getStatusResponseId("DELETE", path, false, nil)
+	return c.getStatusResponseId(ctx, "DELETE", path, false, nil)
+}
+
+// DeleteServerJob is like DeleteServer, but returns the Job of the operation (see StatusJob).
+func (c *Client) DeleteServerJob(serverId string) (Job, error) {
+	return c.DeleteServerJobContext(c.ctx, serverId)
+}
+
+// DeleteServerJobContext is like DeleteServerJob, using @ctx for cancellation.
+func (c *Client) DeleteServerJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.DeleteServerContext(ctx, serverId))
 }
 
 /*
@@ -375,7 +463,12 @@ type ImportOVF struct {
 // Get the list of available servers that can be imported.
 // @locationId: Data center location identifier
 func (c *Client) GetServerImports(locationId string) (res []ImportOVF, err error) {
-	err = c.getCLCResponse("GET", fmt.Sprintf("/v2/vmImport/%s/%s/available", c.AccountAlias, locationId), nil, &res)
+	return c.GetServerImportsContext(c.ctx, locationId)
+}
+
+// GetServerImportsContext is like GetServerImports, using @ctx for cancellation.
+func (c *Client) GetServerImportsContext(ctx context.Context, locationId string) (res []ImportOVF, err error) {
+	err = c.getCLCResponse(ctx, "GET", fmt.Sprintf("/v2/vmImport/%s/%s/available", c.AccountAlias, locationId), nil, &res)
 	return res, err
 }
 
@@ -394,7 +487,12 @@ type ServerCredentials struct {
 // Retrieve the administrator/root password on an existing server.
 // @serverId: ID of the server with the credentials to return.
 func (c *Client) GetServerCredentials(serverId string) (res ServerCredentials, err error) {
-	err = c.getCLCResponse("GET", fmt.Sprintf("/v2/servers/%s/%s/credentials", c.AccountAlias, serverId), nil, &res)
+	return c.GetServerCredentialsContext(c.ctx, serverId)
+}
+
+// GetServerCredentialsContext is like GetServerCredentials, using @ctx for cancellation.
+func (c *Client) GetServerCredentialsContext(ctx context.Context, serverId string) (res ServerCredentials, err error) {
+	err = c.getCLCResponse(ctx, "GET", fmt.Sprintf("/v2/servers/%s/%s/credentials", c.AccountAlias, serverId), nil, &res)
 	return res, err
 }
 
@@ -403,6 +501,11 @@ func (c *Client) GetServerCredentials(serverId string) (res ServerCredentials, e
 // @curPass:  current password for @serverId
 // @newPass:  new password for @serverId
 func (c *Client) ServerChangePassword(serverId, curPass, newPass string) (statusId string, err error) {
+	return c.ServerChangePasswordContext(c.ctx, serverId, curPass, newPass)
+}
+
+// ServerChangePasswordContext is like ServerChangePassword, using @ctx for cancellation.
+func (c *Client) ServerChangePasswordContext(ctx context.Context, serverId, curPass, newPass string) (statusId string, err error) {
 	var op = PatchOperation{
 		Op:     "set",
 		Member: "password",
@@ -414,14 +517,19 @@ func (c *Client) ServerChangePassword(serverId, curPass, newPass string) (status
 			Password string `json:"password"`
 		}{curPass, newPass},
 	}
-	return c.patchStatus(fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId), &op)
+	return c.patchStatus(ctx, fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId), &op)
 }
 
 // Change the number of CPU cores on an existing server.
 // @serverId: ID of the server to change.
 // @cpus:     number of CPUs to allocate for @serverId.
 func (c *Client) ServerSetCpus(serverId, cpus string) (statusId string, err error) {
-	return c.patchStatus(fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId),
+	return c.ServerSetCpusContext(c.ctx, serverId, cpus)
+}
+
+// ServerSetCpusContext is like ServerSetCpus, using @ctx for cancellation.
+func (c *Client) ServerSetCpusContext(ctx context.Context, serverId, cpus string) (statusId string, err error) {
+	return c.patchStatus(ctx, fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId),
 		&PatchOperation{"set", "cpu", cpus})
 }
 
@@ -429,7 +537,12 @@ func (c *Client) ServerSetCpus(serverId, cpus string) (statusId string, err erro
 // @serverId: ID of the server to change.
 // @memGB:    amount of memory (in GB) to allocate.
 func (c *Client) ServerSetMemory(serverId, memGB string) (statusId string, err error) {
-	return c.patchStatus(fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId),
+	return c.ServerSetMemoryContext(c.ctx, serverId, memGB)
+}
+
+// ServerSetMemoryContext is like ServerSetMemory, using @ctx for cancellation.
+func (c *Client) ServerSetMemoryContext(ctx context.Context, serverId, memGB string) (statusId string, err error) {
+	return c.patchStatus(ctx, fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId),
 		&PatchOperation{"set", "memory", memGB})
 }
 
@@ -437,7 +550,12 @@ func (c *Client) ServerSetMemory(serverId, memGB string) (statusId string, err e
 // @serverId: ID of the server to change.
 // @desc:     new description to use for @serverId.
 func (c *Client) ServerSetDescription(serverId, desc string) error {
-	return c.patch(fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId),
+	return c.ServerSetDescriptionContext(c.ctx, serverId, desc)
+}
+
+// ServerSetDescriptionContext is like ServerSetDescription, using @ctx for cancellation.
+func (c *Client) ServerSetDescriptionContext(ctx context.Context, serverId, desc string) error {
+	return c.patch(ctx, fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId),
 		&PatchOperation{"set", "description", desc})
 }
 
@@ -445,7 +563,12 @@ func (c *Client) ServerSetDescription(serverId, desc string) error {
 // @serverId:   ID of the server to change.
 // @parentUUID: UUID of new parent group for @serverId.
 func (c *Client) ServerSetGroup(serverId, parentUUID string) error {
-	return c.patch(fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId),
+	return c.ServerSetGroupContext(c.ctx, serverId, parentUUID)
+}
+
+// ServerSetGroupContext is like ServerSetGroup, using @ctx for cancellation.
+func (c *Client) ServerSetGroupContext(ctx context.Context, serverId, parentUUID string) error {
+	return c.patch(ctx, fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId),
 		&PatchOperation{"set", "groupId", parentUUID})
 }
 
@@ -453,7 +576,12 @@ func (c *Client) ServerSetGroup(serverId, parentUUID string) error {
 // @serverId: ID of the server to change
 // @disks:    complete list of (modified) existing and (optionally) additional disks
 func (c *Client) ServerSetDisks(serverId string, disks []ServerAdditionalDisk) (statusId string, err error) {
-	return c.patchStatus(fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId),
+	return c.ServerSetDisksContext(c.ctx, serverId, disks)
+}
+
+// ServerSetDisksContext is like ServerSetDisks, using @ctx for cancellation.
+func (c *Client) ServerSetDisksContext(ctx context.Context, serverId string, disks []ServerAdditionalDisk) (statusId string, err error) {
+	return c.patchStatus(ctx, fmt.Sprintf("/v2/servers/%s/%s", c.AccountAlias, serverId),
 		&PatchOperation{"set", "disks", disks})
 }
 
@@ -473,7 +601,12 @@ type ServerSnapshot struct {
 // FIXME: current (Nov 2015) CLC policy is to keep a single snapshot.
 //        This may or may not change in the future.
 func (c *Client) GetServerSnapshot(serverId string) (sn *ServerSnapshot, err error) {
-	if server, err := c.GetServer(serverId); err != nil {
+	return c.GetServerSnapshotContext(c.ctx, serverId)
+}
+
+// GetServerSnapshotContext is like GetServerSnapshot, using @ctx for cancellation.
+func (c *Client) GetServerSnapshotContext(ctx context.Context, serverId string) (sn *ServerSnapshot, err error) {
+	if server, err := c.GetServerContext(ctx, serverId); err != nil {
 		return nil, err
 	} else if len(server.Details.Snapshots) == 0 {
 		return nil, nil
@@ -488,17 +621,22 @@ func (c *Client) GetServerSnapshot(serverId string) (sn *ServerSnapshot, err err
 // SnapshotServer wraps CreateSnapshot, using the maximum allowed expiration period.
 // If a snapshot already exists, it will be overwritten by the new one.
 func (c *Client) SnapshotServer(serverId string) (statusId string, err error) {
+	return c.SnapshotServerContext(c.ctx, serverId)
+}
+
+// SnapshotServerContext is like SnapshotServer, using @ctx for cancellation.
+func (c *Client) SnapshotServerContext(ctx context.Context, serverId string) (statusId string, err error) {
 	// CLC does not allow incremental snapshots, so delete any old ones first.
-	if statusId, err := c.DeleteSnapshot(serverId); err != nil && err != ErrNoSnapshot {
+	if statusId, err := c.DeleteSnapshotContext(ctx, serverId); err != nil && err != ErrNoSnapshot {
 		return "", err
 	} else if statusId != "" {
-		if status, err := c.AwaitCompletion(statusId); err != nil {
+		if status, err := c.AwaitCompletionContext(ctx, statusId); err != nil {
 			return "", errors.Errorf("failed to query %s snapshot status: %s", serverId, err)
 		} else if status != Succeeded {
 			return "", errors.Errorf("failed to delete %s snapshot (status: %s)", serverId, status)
 		}
 	}
-	return c.CreateSnapshot(serverId, 10)
+	return c.CreateSnapshotContext(ctx, serverId, 10)
 }
 
 // Send the create snapshot operation to a list of servers (along with the number of days
@@ -506,48 +644,93 @@ func (c *Client) SnapshotServer(serverId string) (statusId string, err error) {
 // @serverId:   Server name to perform create snapshot operation on.
 // @daysToKeep: Number of days to keep the snapshot(s) for (must be between 1 and 10).
 func (c *Client) CreateSnapshot(serverId string, daysToKeep int) (statusId string, err error) {
+	return c.CreateSnapshotContext(c.ctx, serverId, daysToKeep)
+}
+
+// CreateSnapshotContext is like CreateSnapshot, using @ctx for cancellation.
+func (c *Client) CreateSnapshotContext(ctx context.Context, serverId string, daysToKeep int) (statusId string, err error) {
 	var path = fmt.Sprintf("/v2/operations/%s/servers/createSnapshot", c.AccountAlias)
 
-	return c.getStatusResponseId("POST", path, true, &struct {
+	return c.getStatusResponseId(ctx, "POST", path, true, &struct {
 		ServerIds              []string `json:"serverIds"`
 		SnapshotExpirationDays int      `json:"snapshotExpirationDays"`
 	}{[]string{serverId}, daysToKeep})
 }
 
+// CreateSnapshotJob is like CreateSnapshot, but returns the Job of the operation (see StatusJob).
+func (c *Client) CreateSnapshotJob(serverId string, daysToKeep int) (Job, error) {
+	return c.CreateSnapshotJobContext(c.ctx, serverId, daysToKeep)
+}
+
+// CreateSnapshotJobContext is like CreateSnapshotJob, using @ctx for cancellation.
+func (c *Client) CreateSnapshotJobContext(ctx context.Context, serverId string, daysToKeep int) (Job, error) {
+	return c.statusJobOf(c.CreateSnapshotContext(ctx, serverId, daysToKeep))
+}
+
 // DeleteSnapshot deletes the server snapshot if it exists.
 // @serverId: Server name to delete snapshot of.
 func (c *Client) DeleteSnapshot(serverId string) (statusId string, err error) {
+	return c.DeleteSnapshotContext(c.ctx, serverId)
+}
+
+// DeleteSnapshotContext is like DeleteSnapshot, using @ctx for cancellation.
+func (c *Client) DeleteSnapshotContext(ctx context.Context, serverId string) (statusId string, err error) {
 	var link *Link
 	/*
 	 * FIXME: there is no way of querying the Snapshot ID. The GetServer request
 	 *        only returns the snapshot name; the ID is buried inside the URLs of
 	 *        the Links array. Hence need to run 2 API requests for 1 deletion.
 	 */
-	if sn, err := c.GetServerSnapshot(serverId); err != nil {
+	if sn, err := c.GetServerSnapshotContext(ctx, serverId); err != nil {
 		return "", err
 	} else if sn == nil {
 		return "", ErrNoSnapshot
 	} else if link, err = extractLink(sn.Links, "delete"); err != nil {
 		return "", err
 	}
-	return c.getStatus("DELETE", link.Href, nil)
+	return c.getStatus(ctx, "DELETE", link.Href, nil)
+}
+
+// DeleteSnapshotJob is like DeleteSnapshot, but returns the Job of the operation (see StatusJob).
+func (c *Client) DeleteSnapshotJob(serverId string) (Job, error) {
+	return c.DeleteSnapshotJobContext(c.ctx, serverId)
+}
+
+// DeleteSnapshotJobContext is like DeleteSnapshotJob, using @ctx for cancellation.
+func (c *Client) DeleteSnapshotJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.DeleteSnapshotContext(ctx, serverId))
 }
 
 // Revert server to snapshot.
 // @serverId: Name of server to revert.
 func (c *Client) RevertToSnapshot(serverId string) (statusId string, err error) {
+	return c.RevertToSnapshotContext(c.ctx, serverId)
+}
+
+// RevertToSnapshotContext is like RevertToSnapshot, using @ctx for cancellation.
+func (c *Client) RevertToSnapshotContext(ctx context.Context, serverId string) (statusId string, err error) {
 	var link *Link
 	/*
 	 * FIXME: see above comments why this is done in this way.
 	 */
-	if sn, err := c.GetServerSnapshot(serverId); err != nil {
+	if sn, err := c.GetServerSnapshotContext(ctx, serverId); err != nil {
 		return "", err
 	} else if sn == nil {
 		return "", ErrNoSnapshot
 	} else if link, err = extractLink(sn.Links, "restore"); err != nil {
 		return "", err
 	}
-	return c.getStatus("POST", link.Href, nil)
+	return c.getStatus(ctx, "POST", link.Href, nil)
+}
+
+// RevertToSnapshotJob is like RevertToSnapshot, but returns the Job of the operation (see StatusJob).
+func (c *Client) RevertToSnapshotJob(serverId string) (Job, error) {
+	return c.RevertToSnapshotJobContext(c.ctx, serverId)
+}
+
+// RevertToSnapshotJobContext is like RevertToSnapshotJob, using @ctx for cancellation.
+func (c *Client) RevertToSnapshotJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.RevertToSnapshotContext(ctx, serverId))
 }
 
 /*
@@ -555,73 +738,223 @@ func (c *Client) RevertToSnapshot(serverId string) (statusId string, err error)
  */
 // ArchiveServer puts @serverId into the archive
 func (c *Client) ArchiveServer(serverId string) (statusId string, err error) {
-	return c.serverPowerOperation("archive", serverId)
+	return c.ArchiveServerContext(c.ctx, serverId)
+}
+
+// ArchiveServerContext is like ArchiveServer, using @ctx for cancellation.
+func (c *Client) ArchiveServerContext(ctx context.Context, serverId string) (statusId string, err error) {
+	return c.serverPowerOperation(ctx, "archive", serverId)
+}
+
+// ArchiveServerJob is like ArchiveServer, but returns the Job of the operation (see StatusJob).
+func (c *Client) ArchiveServerJob(serverId string) (Job, error) {
+	return c.ArchiveServerJobContext(c.ctx, serverId)
+}
+
+// ArchiveServerJobContext is like ArchiveServerJob, using @ctx for cancellation.
+func (c *Client) ArchiveServerJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.ArchiveServerContext(ctx, serverId))
 }
 
 // RestoreServer restores @serverId into the HW Group identified by @groupId
 func (c *Client) RestoreServer(serverId, groupId string) (statusId string, err error) {
+	return c.RestoreServerContext(c.ctx, serverId, groupId)
+}
+
+// RestoreServerContext is like RestoreServer, using @ctx for cancellation.
+func (c *Client) RestoreServerContext(ctx context.Context, serverId, groupId string) (statusId string, err error) {
 	var path = fmt.Sprintf("/v2/servers/%s/%s/restore", c.AccountAlias, serverId)
 
-	return c.getStatus("POST", path, &struct {
+	return c.getStatus(ctx, "POST", path, &struct {
 		TargetGroupId string `json:"targetGroupId"`
 	}{groupId})
 }
 
+// RestoreServerJob is like RestoreServer, but returns the Job of the operation (see StatusJob).
+func (c *Client) RestoreServerJob(serverId, groupId string) (Job, error) {
+	return c.RestoreServerJobContext(c.ctx, serverId, groupId)
+}
+
+// RestoreServerJobContext is like RestoreServerJob, using @ctx for cancellation.
+func (c *Client) RestoreServerJobContext(ctx context.Context, serverId, groupId string) (Job, error) {
+	return c.statusJobOf(c.RestoreServerContext(ctx, serverId, groupId))
+}
+
 /*
  * Power Operations
  */
-func (c *Client) serverPowerOperation(op, serverId string) (statusId string, err error) {
+func (c *Client) serverPowerOperation(ctx context.Context, op, serverId string) (statusId string, err error) {
 	var path = fmt.Sprintf("/v2/operations/%s/servers/%s", c.AccountAlias, op)
 
-	return c.getStatusResponseId("POST", path, true, []string{serverId})
+	return c.getStatusResponseId(ctx, "POST", path, true, []string{serverId})
 }
 
 // Send the pause operation to a server and add operation to queue.
 // @serverId: Name of server to pause.
 func (c *Client) PauseServer(serverId string) (statusId string, err error) {
-	return c.serverPowerOperation("pause", serverId)
+	return c.PauseServerContext(c.ctx, serverId)
+}
+
+// PauseServerContext is like PauseServer, using @ctx for cancellation.
+func (c *Client) PauseServerContext(ctx context.Context, serverId string) (statusId string, err error) {
+	return c.serverPowerOperation(ctx, "pause", serverId)
+}
+
+// PauseServerJob is like PauseServer, but returns the Job of the operation (see StatusJob).
+func (c *Client) PauseServerJob(serverId string) (Job, error) {
+	return c.PauseServerJobContext(c.ctx, serverId)
+}
+
+// PauseServerJobContext is like PauseServerJob, using @ctx for cancellation.
+func (c *Client) PauseServerJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.PauseServerContext(ctx, serverId))
 }
 
 // Send the power-on operation to a server and add operation to queue.
 // @serverId: Name of server to power on.
 func (c *Client) PowerOnServer(serverId string) (statusId string, err error) {
-	return c.serverPowerOperation("powerOn", serverId)
+	return c.PowerOnServerContext(c.ctx, serverId)
+}
+
+// PowerOnServerContext is like PowerOnServer, using @ctx for cancellation.
+func (c *Client) PowerOnServerContext(ctx context.Context, serverId string) (statusId string, err error) {
+	return c.serverPowerOperation(ctx, "powerOn", serverId)
+}
+
+// PowerOnServerJob is like PowerOnServer, but returns the Job of the operation (see StatusJob).
+func (c *Client) PowerOnServerJob(serverId string) (Job, error) {
+	return c.PowerOnServerJobContext(c.ctx, serverId)
+}
+
+// PowerOnServerJobContext is like PowerOnServerJob, using @ctx for cancellation.
+func (c *Client) PowerOnServerJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.PowerOnServerContext(ctx, serverId))
 }
 
 // Send the (hard) power-off operation to a server and add operation to queue.
 // @serverId: Name of server to power off.
 func (c *Client) PowerOffServer(serverId string) (statusId string, err error) {
-	return c.serverPowerOperation("powerOff", serverId)
+	return c.PowerOffServerContext(c.ctx, serverId)
+}
+
+// PowerOffServerContext is like PowerOffServer, using @ctx for cancellation.
+func (c *Client) PowerOffServerContext(ctx context.Context, serverId string) (statusId string, err error) {
+	return c.serverPowerOperation(ctx, "powerOff", serverId)
+}
+
+// PowerOffServerJob is like PowerOffServer, but returns the Job of the operation (see StatusJob).
+func (c *Client) PowerOffServerJob(serverId string) (Job, error) {
+	return c.PowerOffServerJobContext(c.ctx, serverId)
+}
+
+// PowerOffServerJobContext is like PowerOffServerJob, using @ctx for cancellation.
+func (c *Client) PowerOffServerJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.PowerOffServerContext(ctx, serverId))
 }
 
 // Send the (soft) shut-down operation to a server and add operation to queue.
 // @serverId: Name of server to shut down.
 func (c *Client) ShutdownServer(serverId string) (statusId string, err error) {
-	return c.serverPowerOperation("shutDown", serverId)
+	return c.ShutdownServerContext(c.ctx, serverId)
+}
+
+// ShutdownServerContext is like ShutdownServer, using @ctx for cancellation.
+func (c *Client) ShutdownServerContext(ctx context.Context, serverId string) (statusId string, err error) {
+	return c.serverPowerOperation(ctx, "shutDown", serverId)
+}
+
+// ShutdownServerJob is like ShutdownServer, but returns the Job of the operation (see StatusJob).
+func (c *Client) ShutdownServerJob(serverId string) (Job, error) {
+	return c.ShutdownServerJobContext(c.ctx, serverId)
+}
+
+// ShutdownServerJobContext is like ShutdownServerJob, using @ctx for cancellation.
+func (c *Client) ShutdownServerJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.ShutdownServerContext(ctx, serverId))
 }
 
 // Send the reboot operation to a server and add operation to queue.
 // @serverId: Name of server to reboot.
 func (c *Client) RebootServer(serverId string) (statusId string, err error) {
-	return c.serverPowerOperation("reboot", serverId)
+	return c.RebootServerContext(c.ctx, serverId)
+}
+
+// RebootServerContext is like RebootServer, using @ctx for cancellation.
+func (c *Client) RebootServerContext(ctx context.Context, serverId string) (statusId string, err error) {
+	return c.serverPowerOperation(ctx, "reboot", serverId)
+}
+
+// RebootServerJob is like RebootServer, but returns the Job of the operation (see StatusJob).
+func (c *Client) RebootServerJob(serverId string) (Job, error) {
+	return c.RebootServerJobContext(c.ctx, serverId)
+}
+
+// RebootServerJobContext is like RebootServerJob, using @ctx for cancellation.
+func (c *Client) RebootServerJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.RebootServerContext(ctx, serverId))
 }
 
 // Send the reset operation to a server and add operation to queue.
 // @serverId: Name of server to reset.
 func (c *Client) ResetServer(serverId string) (statusId string, err error) {
-	return c.serverPowerOperation("reset", serverId)
+	return c.ResetServerContext(c.ctx, serverId)
+}
+
+// ResetServerContext is like ResetServer, using @ctx for cancellation.
+func (c *Client) ResetServerContext(ctx context.Context, serverId string) (statusId string, err error) {
+	return c.serverPowerOperation(ctx, "reset", serverId)
+}
+
+// ResetServerJob is like ResetServer, but returns the Job of the operation (see StatusJob).
+func (c *Client) ResetServerJob(serverId string) (Job, error) {
+	return c.ResetServerJobContext(c.ctx, serverId)
+}
+
+// ResetServerJobContext is like ResetServerJob, using @ctx for cancellation.
+func (c *Client) ResetServerJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.ResetServerContext(ctx, serverId))
 }
 
 // Send the start-maintenance operation to a server and add operation to queue.
 // @serverId: Name of server to change.
 func (c *Client) ServerStartMaintenance(serverId string) (statusId string, err error) {
-	return c.serverPowerOperation("startMaintenance", serverId)
+	return c.ServerStartMaintenanceContext(c.ctx, serverId)
+}
+
+// ServerStartMaintenanceContext is like ServerStartMaintenance, using @ctx for cancellation.
+func (c *Client) ServerStartMaintenanceContext(ctx context.Context, serverId string) (statusId string, err error) {
+	return c.serverPowerOperation(ctx, "startMaintenance", serverId)
+}
+
+// ServerStartMaintenanceJob is like ServerStartMaintenance, but returns the Job of the operation (see StatusJob).
+func (c *Client) ServerStartMaintenanceJob(serverId string) (Job, error) {
+	return c.ServerStartMaintenanceJobContext(c.ctx, serverId)
+}
+
+// ServerStartMaintenanceJobContext is like ServerStartMaintenanceJob, using @ctx for cancellation.
+func (c *Client) ServerStartMaintenanceJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.ServerStartMaintenanceContext(ctx, serverId))
 }
 
 // Send the stop-maintenance operation to a server and add operation to queue.
 // @serverId: Name of server to change.
 func (c *Client) ServerStopMaintenance(serverId string) (statusId string, err error) {
-	return c.serverPowerOperation("stopMaintenance", serverId)
+	return c.ServerStopMaintenanceContext(c.ctx, serverId)
+}
+
+// ServerStopMaintenanceContext is like ServerStopMaintenance, using @ctx for cancellation.
+func (c *Client) ServerStopMaintenanceContext(ctx context.Context, serverId string) (statusId string, err error) {
+	return c.serverPowerOperation(ctx, "stopMaintenance", serverId)
+}
+
+// ServerStopMaintenanceJob is like ServerStopMaintenance, but returns the Job of the operation (see StatusJob).
+func (c *Client) ServerStopMaintenanceJob(serverId string) (Job, error) {
+	return c.ServerStopMaintenanceJobContext(c.ctx, serverId)
+}
+
+// ServerStopMaintenanceJobContext is like ServerStopMaintenanceJob, using @ctx for cancellation.
+func (c *Client) ServerStopMaintenanceJobContext(ctx context.Context, serverId string) (Job, error) {
+	return c.statusJobOf(c.ServerStopMaintenanceContext(ctx, serverId))
 }
 
 type MaintenanceMode struct {
@@ -636,26 +969,33 @@ type MaintenanceMode struct {
 // @serverId: Name of server to change.
 // @enable:   Whether to enable (true) or disable (false) Maintenance Mode on @serverId.
 func (c *Client) ServerSetMaintenance(serverId string, enable bool) (statusId string, err error) {
+	return c.ServerSetMaintenanceContext(c.ctx, serverId, enable)
+}
+
+// ServerSetMaintenanceContext is like ServerSetMaintenance, using @ctx for cancellation.
+func (c *Client) ServerSetMaintenanceContext(ctx context.Context, serverId string, enable bool) (statusId string, err error) {
 	var path = fmt.Sprintf("/v2/operations/%s/servers/setMaintenance", c.AccountAlias)
 
-	return c.getStatusResponseId("POST", path, true, &struct {
+	return c.getStatusResponseId(ctx, "POST", path, true, &struct {
 		Servers []MaintenanceMode `json:"servers"`
 	}{[]MaintenanceMode{{serverId, enable}}})
 }
 
+// ServerSetMaintenanceJob is like ServerSetMaintenance, but returns the Job of the operation (see StatusJob).
+func (c *Client) ServerSetMaintenanceJob(serverId string, enable bool) (Job, error) {
+	return c.ServerSetMaintenanceJobContext(c.ctx, serverId, enable)
+}
+
+// ServerSetMaintenanceJobContext is like ServerSetMaintenanceJob, using @ctx for cancellation.
+func (c *Client) ServerSetMaintenanceJobContext(ctx context.Context, serverId string, enable bool) (Job, error) {
+	return c.statusJobOf(c.ServerSetMaintenanceContext(ctx, serverId, enable))
+}
+
 /*
  * Adding/removing secondary network adapters.
  * FIXME: the status response returns a different object than the regular queue status
  *        operation, which requires patching up here (experimental API).
  */
-const (
-	/* Poll interval in seconds for adding/removing a secondary network adapter */
-	change_nic_poll = 1 * time.Second
-
-	/* Maximum acceptable time in seconds to poll for secondary NIC change in minutes */
-	change_nic_timeout = 3 * time.Minute
-)
-
 type ChangeNicResponse struct {
 	// GUID for the item in the queue for completion
 	OperationId string
@@ -676,25 +1016,24 @@ type ChangeNicStatus struct {
 	Summary, Source map[string]string
 }
 
-// Helper function to poll the queue used for adding/removing secondary network interfaces.
-// Since this uses a diffrent API, the standard Queue -> Get Status can not be used.
-func (c *Client) changeNic(verb, path string, reqModel interface{}) (err error) {
+// Helper function to start adding/removing secondary network interfaces.
+// Since this uses a diffrent API, the standard Queue -> Get Status can not be used (see nicJob).
+func (c *Client) changeNicJob(ctx context.Context, verb, path string, reqModel interface{}) (Job, error) {
 	var res ChangeNicResponse
-	var s ChangeNicStatus
-
-	if err = c.getCLCResponse(verb, path, reqModel, &res); err == nil {
-		for start := time.Now(); s.Status != Succeeded; time.Sleep(change_nic_poll) {
-			if err = c.getCLCResponse("GET", res.Uri, nil, &s); err != nil {
-				break
-			} else if s.Status == Failed {
-				return errors.Errorf("request %s %s failed", verb, path)
-			} else if time.Since(start) > change_nic_timeout {
-				return errors.Errorf("request %s %s timed out after %s", verb,
-					path, time.Since(start))
-			}
-		}
+
+	if err := c.getCLCResponse(ctx, verb, path, reqModel, &res); err != nil {
+		return nil, err
+	}
+	return c.nicJob(res), nil
+}
+
+// Helper function to add/remove secondary network interfaces and wait for completion.
+func (c *Client) changeNic(ctx context.Context, verb, path string, reqModel interface{}) error {
+	j, err := c.changeNicJob(ctx, verb, path, reqModel)
+	if err != nil {
+		return err
 	}
-	return err
+	return j.Wait(ctx)
 }
 
 // Add secondary network adapter to server.
@@ -702,7 +1041,26 @@ func (c *Client) changeNic(verb, path string, reqModel interface{}) (err error)
 // @netId:    (Hex) ID of the network to connect to (must be different from server's existing ones)
 // @ip:       Optional IP address to claim on the network @netId
 func (c *Client) ServerAddNic(serverId, netId, ip string) (err error) {
-	return c.changeNic("POST", fmt.Sprintf("/v2/servers/%s/%s/networks", c.AccountAlias, serverId), struct {
+	return c.ServerAddNicContext(c.ctx, serverId, netId, ip)
+}
+
+// ServerAddNicContext is like ServerAddNic, using @ctx for cancellation.
+func (c *Client) ServerAddNicContext(ctx context.Context, serverId, netId, ip string) (err error) {
+	j, err := c.ServerAddNicJobContext(ctx, serverId, netId, ip)
+	if err != nil {
+		return err
+	}
+	return j.Wait(ctx)
+}
+
+// ServerAddNicJob is like ServerAddNic, but returns without waiting for the change to complete.
+func (c *Client) ServerAddNicJob(serverId, netId, ip string) (Job, error) {
+	return c.ServerAddNicJobContext(c.ctx, serverId, netId, ip)
+}
+
+// ServerAddNicJobContext is like ServerAddNicJob, using @ctx for cancellation.
+func (c *Client) ServerAddNicJobContext(ctx context.Context, serverId, netId, ip string) (Job, error) {
+	return c.changeNicJob(ctx, "POST", fmt.Sprintf("/v2/servers/%s/%s/networks", c.AccountAlias, serverId), struct {
 		// (Hex) ID of the network.
 		NetworkId string `json:"networkId"`
 
@@ -715,5 +1073,20 @@ func (c *Client) ServerAddNic(serverId, netId, ip string) (err error) {
 // @serverId: ID of the server to change
 // @netId:    ID of the network
 func (c *Client) ServerDelNic(serverId, netId string) (err error) {
-	return c.changeNic("DELETE", fmt.Sprintf("/v2/servers/%s/%s/networks/%s", c.AccountAlias, serverId, netId), nil)
+	return c.ServerDelNicContext(c.ctx, serverId, netId)
+}
+
+// ServerDelNicContext is like ServerDelNic, using @ctx for cancellation.
+func (c *Client) ServerDelNicContext(ctx context.Context, serverId, netId string) (err error) {
+	return c.changeNic(ctx, "DELETE", fmt.Sprintf("/v2/servers/%s/%s/networks/%s", c.AccountAlias, serverId, netId), nil)
+}
+
+// ServerDelNicJob is like ServerDelNic, but returns without waiting for the change to complete.
+func (c *Client) ServerDelNicJob(serverId, netId string) (Job, error) {
+	return c.ServerDelNicJobContext(c.ctx, serverId, netId)
+}
+
+// ServerDelNicJobContext is like ServerDelNicJob, using @ctx for cancellation.
+func (c *Client) ServerDelNicJobContext(ctx context.Context, serverId, netId string) (Job, error) {
+	return c.changeNicJob(ctx, "DELETE", fmt.Sprintf("/v2/servers/%s/%s/networks/%s", c.AccountAlias, serverId, netId), nil)
 }
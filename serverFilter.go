@@ -0,0 +1,501 @@
+package clcv2
+
+/*
+ * Filter expressions that select servers by their properties, e.g.
+ *
+ *   powerState=stopped and cpu>=4 and os~"ubuntu" and customField.owner=ops
+ */
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ServerFilter is a parsed filter expression (see ParseServerFilter).
+type ServerFilter struct {
+	expr  string
+	match filterFunc
+}
+
+// filterFunc evaluates (part of) a filter expression against @s.
+type filterFunc func(s *filterSubject) bool
+
+// filterSubject is a server, along with the path of its group.
+type filterSubject struct {
+	*Server
+	groupPath string
+}
+
+// filterKind is the type of the values of a filter field.
+type filterKind int
+
+const (
+	filterString filterKind = iota
+	filterNumber
+	filterBool
+	filterTime
+)
+
+// filterField is a property of a server that filter expressions can refer to.
+// Exactly one of the value functions is set, depending on @kind.
+type filterField struct {
+	kind filterKind
+
+	// The values of a string field; a comparison is true if it holds for any of them
+	strings func(s *filterSubject) []string
+	number  func(s *filterSubject) int64
+	boolean func(s *filterSubject) bool
+	time    func(s *filterSubject) time.Time
+}
+
+// Fields of filter expressions, by lower-case name. Custom fields are referred to as customField.<name>.
+var filterFields = map[string]filterField{
+	"name":        stringField(func(s *filterSubject) string { return s.Name }),
+	"description": stringField(func(s *filterSubject) string { return s.Description }),
+	"status":      stringField(func(s *filterSubject) string { return s.Status }),
+	"powerstate":  stringField(func(s *filterSubject) string { return s.Details.PowerState }),
+	"os":          stringField(func(s *filterSubject) string { return s.OsType }),
+	"type":        stringField(func(s *filterSubject) string { return s.Type }),
+	"storagetype": stringField(func(s *filterSubject) string { return s.StorageType }),
+	"hostname":    stringField(func(s *filterSubject) string { return s.Details.Hostname }),
+	"location":    stringField(func(s *filterSubject) string { return s.LocationId }),
+	"createdby":   stringField(func(s *filterSubject) string { return s.ChangeInfo.CreatedBy }),
+	"modifiedby":  stringField(func(s *filterSubject) string { return s.ChangeInfo.ModifiedBy }),
+
+	// Both the full path (starting at the data centre root group), and the name of the group of the server
+	"group": {kind: filterString, strings: func(s *filterSubject) []string {
+		if i := strings.LastIndex(s.groupPath, "/"); i >= 0 {
+			return []string{s.groupPath, s.groupPath[i+1:]}
+		}
+		return []string{s.groupPath}
+	}},
+	// Internal and public addresses
+	"ip": {kind: filterString, strings: func(s *filterSubject) (res []string) {
+		for _, ip := range s.Details.IpAddresses {
+			for _, addr := range []string{ip.Internal, ip.Public} {
+				if addr != "" {
+					res = append(res, addr)
+				}
+			}
+		}
+		return res
+	}},
+	"publicip": {kind: filterString, strings: func(s *filterSubject) (res []string) {
+		for _, ip := range s.Details.IpAddresses {
+			if ip.Public != "" {
+				res = append(res, ip.Public)
+			}
+		}
+		return res
+	}},
+
+	"cpu":     {kind: filterNumber, number: func(s *filterSubject) int64 { return int64(s.Details.Cpu) }},
+	"memory":  {kind: filterNumber, number: func(s *filterSubject) int64 { return int64(s.Details.MemoryMb / 1024) }},
+	"storage": {kind: filterNumber, number: func(s *filterSubject) int64 { return int64(s.Details.StorageGb) }},
+	"disks":   {kind: filterNumber, number: func(s *filterSubject) int64 { return int64(s.Details.DiskCount) }},
+
+	"maintenance": {kind: filterBool, boolean: func(s *filterSubject) bool { return s.Details.InMaintenanceMode }},
+	"snapshot":    {kind: filterBool, boolean: func(s *filterSubject) bool { return len(s.Details.Snapshots) > 0 }},
+	"template":    {kind: filterBool, boolean: func(s *filterSubject) bool { return s.IsTemplate }},
+
+	"created":  {kind: filterTime, time: func(s *filterSubject) time.Time { return s.ChangeInfo.CreatedDate }},
+	"modified": {kind: filterTime, time: func(s *filterSubject) time.Time { return s.ChangeInfo.ModifiedDate }},
+}
+
+// stringField returns a single-valued string field.
+func stringField(value func(s *filterSubject) string) filterField {
+	return filterField{kind: filterString, strings: func(s *filterSubject) []string { return []string{value(s)} }}
+}
+
+// customField returns the field for the custom field @name. A server that does not have the field
+// has the empty string as value.
+func customField(name string) filterField {
+	return filterField{kind: filterString, strings: func(s *filterSubject) []string {
+		for _, cf := range s.Details.CustomFields {
+			if strings.EqualFold(cf.Name, name) {
+				return []string{cf.Value, cf.DisplayValue}
+			}
+		}
+		return []string{""}
+	}}
+}
+
+// ParseServerFilter parses the filter expression @expr, which consists of comparisons
+//
+//	<field> <operator> <value>
+//
+// combined by 'and', 'or', 'not', and parentheses; 'and' binds more tightly than 'or'.
+// Values that contain whitespace, parentheses, or operator characters need to be double-quoted; within quotes,
+// \" stands for a double quote, and all other characters (including backslashes) are taken literally.
+//
+// Operators are '=' (or '=='), '!=', '<', '<=', '>', '>=', and '~' / '!~' (case-insensitive regular
+// expression match, e.g. a substring). String comparisons ignore case, and only support '=', '!=', '~', and '!~'.
+//
+// Fields (names are case-insensitive):
+//   - strings:  name, description, status, powerState, os, type, storageType, hostname, location,
+//     createdBy, modifiedBy, group (group name or path, e.g. "VA1 Hardware/Web"),
+//     ip (any internal or public address), publicIp, customField.<name>
+//   - numbers:  cpu, memory (GB), storage (GB), disks
+//   - booleans: maintenance, snapshot (whether the server has a snapshot), template;
+//     a boolean field on its own is short for <field>=true
+//   - dates:    created, modified; values are RFC3339 times, or UTC dates (2006-01-02) that stand for the whole day
+//
+// Multi-valued fields (ip, publicIp, group) satisfy a comparison if any of their values does, and '!=' / '!~'
+// are the negation of '=' / '~'.
+func ParseServerFilter(expr string) (*ServerFilter, error) {
+	var p = &filterParser{expr: expr}
+
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	} else if len(p.tokens) == 0 {
+		return nil, errors.New("empty filter expression")
+	}
+
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	} else if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected %s", p.tokens[p.pos])
+	}
+	return &ServerFilter{expr: expr, match: match}, nil
+}
+
+// String returns the expression that @f was parsed from.
+func (f *ServerFilter) String() string {
+	return f.expr
+}
+
+// Match returns true if server @s, whose group has the slash-separated path @groupPath, satisfies @f.
+// A nil filter matches all servers.
+func (f *ServerFilter) Match(s *Server, groupPath string) bool {
+	return f == nil || f.match(&filterSubject{Server: s, groupPath: groupPath})
+}
+
+// filterToken is a lexical element of a filter expression.
+type filterToken struct {
+	text   string
+	quoted bool // whether @text was a quoted string
+	op     bool // whether @text is an operator
+	offset int  // position in the expression
+}
+
+func (t filterToken) String() string {
+	return fmt.Sprintf("%q at offset %d", t.text, t.offset)
+}
+
+// filterParser is a recursive-descent parser of filter expressions.
+type filterParser struct {
+	expr   string
+	tokens []filterToken
+	pos    int // index of the next token
+}
+
+func (p *filterParser) errorf(format string, a ...interface{}) error {
+	return errors.Errorf("invalid filter %q: %s", p.expr, fmt.Sprintf(format, a...))
+}
+
+// Characters that make up comparison operators
+const filterOpChars = "=!<>~"
+
+// tokenize splits @p.expr into words, quoted strings, operators, and parentheses.
+func (p *filterParser) tokenize() error {
+	for i := 0; i < len(p.expr); {
+		switch c := p.expr[i]; {
+		case isFilterSpace(c):
+			i++
+		case c == '(' || c == ')':
+			p.tokens = append(p.tokens, filterToken{text: string(c), offset: i})
+			i++
+		case c == '"': // the only escape sequence is \", so that regular expressions need no extra escaping
+			var text strings.Builder
+			j := i + 1
+			for ; j < len(p.expr) && p.expr[j] != '"'; j++ {
+				if p.expr[j] == '\\' && j+1 < len(p.expr) && p.expr[j+1] == '"' {
+					j++
+				}
+				text.WriteByte(p.expr[j])
+			}
+			if j >= len(p.expr) {
+				return p.errorf("unterminated string at offset %d", i)
+			}
+			p.tokens = append(p.tokens, filterToken{text: text.String(), quoted: true, offset: i})
+			i = j + 1
+		case strings.IndexByte(filterOpChars, c) >= 0:
+			j := i
+			for j < len(p.expr) && strings.IndexByte(filterOpChars, p.expr[j]) >= 0 {
+				j++
+			}
+			p.tokens = append(p.tokens, filterToken{text: p.expr[i:j], op: true, offset: i})
+			i = j
+		default:
+			j := i
+			for j < len(p.expr) && !isFilterSpace(p.expr[j]) && strings.IndexByte(`()"`+filterOpChars, p.expr[j]) < 0 {
+				j++
+			}
+			p.tokens = append(p.tokens, filterToken{text: p.expr[i:j], offset: i})
+			i = j
+		}
+	}
+	return nil
+}
+
+// isFilterSpace returns true if @c is ASCII whitespace. Other bytes, including those of multi-byte
+// UTF-8 characters, are part of words.
+func isFilterSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+// keyword returns true and consumes the next token if it is the (unquoted) keyword @kw.
+func (p *filterParser) keyword(kw string) bool {
+	if p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// parseOr parses a disjunction of conjunctions.
+func (p *filterParser) parseOr() (filterFunc, error) {
+	var terms []filterFunc
+
+	for {
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if terms = append(terms, term); !p.keyword("or") {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return func(s *filterSubject) bool {
+		for _, t := range terms {
+			if t(s) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// parseAnd parses a conjunction of (negated) comparisons and parenthesized expressions.
+func (p *filterParser) parseAnd() (filterFunc, error) {
+	var terms []filterFunc
+
+	for {
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if terms = append(terms, term); !p.keyword("and") {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return func(s *filterSubject) bool {
+		for _, t := range terms {
+			if !t(s) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// parseUnary parses a negation, a parenthesized expression, or a comparison.
+func (p *filterParser) parseUnary() (filterFunc, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("unexpected end of expression")
+	} else if p.keyword("not") {
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(s *filterSubject) bool { return !term(s) }, nil
+	} else if p.keyword("(") {
+		term, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		} else if !p.keyword(")") {
+			return nil, p.errorf("missing closing parenthesis")
+		}
+		return term, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses '<field> <operator> <value>', or a boolean field on its own.
+func (p *filterParser) parseComparison() (filterFunc, error) {
+	var tok = p.tokens[p.pos]
+	var field filterField
+	var ok bool
+
+	if tok.quoted || tok.op || tok.text == ")" {
+		return nil, p.errorf("expected a field name instead of %s", tok)
+	}
+	p.pos++
+
+	if lower := strings.ToLower(tok.text); strings.HasPrefix(lower, "customfield.") {
+		if len(lower) == len("customfield.") {
+			return nil, p.errorf("missing custom field name at offset %d", tok.offset)
+		}
+		field = customField(tok.text[len("customfield."):])
+	} else if field, ok = filterFields[lower]; !ok {
+		return nil, p.errorf("unknown field %q", tok.text)
+	}
+
+	// A boolean field on its own
+	if p.pos >= len(p.tokens) || !p.tokens[p.pos].op {
+		if field.kind != filterBool {
+			return nil, p.errorf("missing comparison after %q", tok.text)
+		}
+		return field.boolean, nil
+	}
+
+	var op = p.tokens[p.pos]
+	if p.pos++; p.pos >= len(p.tokens) || p.tokens[p.pos].op || (!p.tokens[p.pos].quoted && strings.Contains("()", p.tokens[p.pos].text)) {
+		return nil, p.errorf("missing value after %s", op)
+	}
+	var val = p.tokens[p.pos]
+	p.pos++
+
+	match, err := compareField(field, op.text, val.text)
+	if err != nil {
+		return nil, p.errorf("%s%s%q: %s", tok.text, op.text, val.text, err)
+	}
+	return match, nil
+}
+
+// compareField returns the comparison of @field with the value @val using operator @op.
+func compareField(field filterField, op, val string) (filterFunc, error) {
+	switch op {
+	case "==":
+		op = "="
+	case "=", "!=", "<", "<=", ">", ">=", "~", "!~":
+	default:
+		return nil, errors.Errorf("unknown operator %q", op)
+	}
+
+	switch field.kind {
+	case filterString:
+		return compareString(field.strings, op, val)
+	case filterNumber:
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("%q is not an integer", val)
+		}
+		return compareNumber(field.number, op, n)
+	case filterBool:
+		b, err := strconv.ParseBool(strings.ToLower(val))
+		if err != nil {
+			return nil, errors.Errorf("%q is not a boolean", val)
+		}
+		switch op {
+		case "=":
+			return func(s *filterSubject) bool { return field.boolean(s) == b }, nil
+		case "!=":
+			return func(s *filterSubject) bool { return field.boolean(s) != b }, nil
+		}
+		return nil, errors.Errorf("operator %s does not apply to booleans", op)
+	default:
+		return compareTime(field.time, op, val)
+	}
+}
+
+// compareString returns the comparison of the values of a string field with @val.
+func compareString(values func(*filterSubject) []string, op, val string) (filterFunc, error) {
+	var anyValue = func(pred func(string) bool) filterFunc {
+		return func(s *filterSubject) bool {
+			for _, v := range values(s) {
+				if pred(v) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	switch op {
+	case "=", "!=":
+		eq := anyValue(func(v string) bool { return strings.EqualFold(v, val) })
+		if op == "=" {
+			return eq, nil
+		}
+		return func(s *filterSubject) bool { return !eq(s) }, nil
+	case "~", "!~":
+		re, err := regexp.Compile("(?i)" + val)
+		if err != nil {
+			return nil, errors.Errorf("invalid regular expression: %s", err)
+		}
+		match := anyValue(re.MatchString)
+		if op == "~" {
+			return match, nil
+		}
+		return func(s *filterSubject) bool { return !match(s) }, nil
+	}
+	return nil, errors.Errorf("operator %s does not apply to strings", op)
+}
+
+// compareNumber returns the comparison of the value of a number field with @n.
+func compareNumber(value func(*filterSubject) int64, op string, n int64) (filterFunc, error) {
+	var cmp func(v int64) bool
+
+	switch op {
+	case "=":
+		cmp = func(v int64) bool { return v == n }
+	case "!=":
+		cmp = func(v int64) bool { return v != n }
+	case "<":
+		cmp = func(v int64) bool { return v < n }
+	case "<=":
+		cmp = func(v int64) bool { return v <= n }
+	case ">":
+		cmp = func(v int64) bool { return v > n }
+	case ">=":
+		cmp = func(v int64) bool { return v >= n }
+	default:
+		return nil, errors.Errorf("operator %s does not apply to numbers", op)
+	}
+	return func(s *filterSubject) bool { return cmp(value(s)) }, nil
+}
+
+// compareTime returns the comparison of the value of a date field with @val.
+// A date without time of day stands for the interval of that day, and a time for an instant.
+func compareTime(value func(*filterSubject) time.Time, op, val string) (filterFunc, error) {
+	var from, until time.Time // [from, until)
+
+	if t, err := time.Parse(time.RFC3339, val); err == nil {
+		from, until = t, t.Add(time.Nanosecond)
+	} else if t, err = time.Parse("2006-01-02", val); err == nil {
+		from, until = t, t.AddDate(0, 0, 1)
+	} else {
+		return nil, errors.Errorf("%q is neither a date (2006-01-02) nor a RFC3339 time", val)
+	}
+
+	var cmp func(t time.Time) bool
+	switch op {
+	case "=":
+		cmp = func(t time.Time) bool { return !t.Before(from) && t.Before(until) }
+	case "!=":
+		cmp = func(t time.Time) bool { return t.Before(from) || !t.Before(until) }
+	case "<":
+		cmp = func(t time.Time) bool { return t.Before(from) }
+	case "<=":
+		cmp = func(t time.Time) bool { return t.Before(until) }
+	case ">":
+		cmp = func(t time.Time) bool { return !t.Before(until) }
+	case ">=":
+		cmp = func(t time.Time) bool { return !t.Before(from) }
+	default:
+		return nil, errors.Errorf("operator %s does not apply to dates", op)
+	}
+	return func(s *filterSubject) bool { return cmp(value(s)) }, nil
+}
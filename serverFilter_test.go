@@ -0,0 +1,126 @@
+package clcv2
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// filterServers returns the servers that the filter tests are run against, along with their group paths.
+func filterServers() (servers []*Server, groupPaths []string) {
+	var web, db = new(Server), new(Server)
+
+	web.Name = "WA1ABCDWEB01"
+	web.Description = `say "hi"`
+	web.Details.PowerState = "started"
+	web.Details.Cpu = 4
+	web.Details.IpAddresses = []ServerIPAddress{{Internal: "10.0.0.1", Public: "1.2.3.4"}}
+	web.Details.CustomFields = []CustomField{{Name: "Owner", Value: "dà", DisplayValue: "dà"}}
+	web.ChangeInfo.CreatedDate = time.Date(2017, 3, 1, 10, 15, 22, 0, time.UTC)
+
+	db.Name = "WA1ABCDDB01"
+	db.Description = "2 cores"
+	db.Details.PowerState = "stopped"
+	db.Details.Cpu = 2
+	db.Details.IpAddresses = []ServerIPAddress{{Internal: "10.0.0.2"}}
+	db.Details.CustomFields = []CustomField{{Name: "Owner", Value: "dba", DisplayValue: "dba"}}
+	db.Details.Snapshots = []ServerSnapshot{{Name: "2017-03-02.00:00:00"}}
+	db.ChangeInfo.CreatedDate = time.Date(2017, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	return []*Server{web, db}, []string{"WA1 Hardware/Web", "WA1 Hardware/DB"}
+}
+
+func TestServerFilter(t *testing.T) {
+	var servers, groupPaths = filterServers()
+
+	for _, tc := range []struct {
+		expr    string
+		matches []string
+	}{
+		// Precedence: not > and > or
+		{`powerState=started or cpu=2 and snapshot`, []string{"WA1ABCDWEB01", "WA1ABCDDB01"}},
+		{`(powerState=started or cpu=2) and snapshot`, []string{"WA1ABCDDB01"}},
+		{`not powerState=started and cpu=2`, []string{"WA1ABCDDB01"}},
+		{`not (name~web or name~db)`, nil},
+		{`NOT snapshot`, []string{"WA1ABCDWEB01"}},
+
+		// Quoting
+		{`group="WA1 Hardware/Web"`, []string{"WA1ABCDWEB01"}},
+		{`description="say \"hi\""`, []string{"WA1ABCDWEB01"}},
+		{`description~"\d+ cores"`, []string{"WA1ABCDDB01"}},
+		{`name~"^wa1abcd(web|db)01$"`, []string{"WA1ABCDWEB01", "WA1ABCDDB01"}},
+		{`name="and"`, nil},
+		{`customField.owner=dà`, []string{"WA1ABCDWEB01"}},
+		{`customField.owner=DBA`, []string{"WA1ABCDDB01"}},
+		{`customField.nope=""`, []string{"WA1ABCDWEB01", "WA1ABCDDB01"}},
+
+		// Negation of multi-valued fields is the negation of 'any value matches'
+		{`ip=1.2.3.4`, []string{"WA1ABCDWEB01"}},
+		{`ip!=1.2.3.4`, []string{"WA1ABCDDB01"}},
+		{`ip!~^10\.`, nil},
+		{`publicIp!~.`, []string{"WA1ABCDDB01"}},
+		{`group!=Web`, []string{"WA1ABCDDB01"}},
+		{`group!="WA1 Hardware/DB"`, []string{"WA1ABCDWEB01"}},
+
+		// Dates stand for the whole (UTC) day, times for an instant
+		{`created=2017-03-01`, []string{"WA1ABCDWEB01"}},
+		{`created!=2017-03-01`, []string{"WA1ABCDDB01"}},
+		{`created>2017-03-01`, []string{"WA1ABCDDB01"}},
+		{`created<=2017-03-01`, []string{"WA1ABCDWEB01"}},
+		{`created>=2017-03-01 and created<2017-03-02`, []string{"WA1ABCDWEB01"}},
+		{`created<2017-03-01T10:15:22Z`, nil},
+		{`created=2017-03-01T10:15:22Z`, []string{"WA1ABCDWEB01"}},
+		{`created>2017-03-01T10:15:22Z`, []string{"WA1ABCDDB01"}},
+
+		// Numbers and booleans
+		{`cpu>2`, []string{"WA1ABCDWEB01"}},
+		{`cpu==2`, []string{"WA1ABCDDB01"}},
+		{`snapshot=false`, []string{"WA1ABCDWEB01"}},
+	} {
+		f, err := ParseServerFilter(tc.expr)
+		if err != nil {
+			t.Errorf("%s: %s", tc.expr, err)
+			continue
+		}
+		var matches []string
+		for i, s := range servers {
+			if f.Match(s, groupPaths[i]) {
+				matches = append(matches, s.Name)
+			}
+		}
+		if !reflect.DeepEqual(matches, tc.matches) {
+			t.Errorf("%s: expected %v, got %v", tc.expr, tc.matches, matches)
+		}
+	}
+}
+
+func TestServerFilterErrors(t *testing.T) {
+	for _, tc := range []struct {
+		expr, err string
+	}{
+		{``, "empty filter expression"},
+		{`   `, "empty filter expression"},
+		{`cpu>=four`, "not an integer"},
+		{`name<web`, "does not apply to strings"},
+		{`snapshot>true`, "does not apply to booleans"},
+		{`created~2017-03-01`, "does not apply to dates"},
+		{`created=yesterday`, "neither a date"},
+		{`name~"("`, "invalid regular expression"},
+		{`name="web`, "unterminated string"},
+		{`(cpu=1`, "missing closing parenthesis"},
+		{`cpu=1)`, "unexpected"},
+		{`nope=1`, "unknown field"},
+		{`customField.=x`, "missing custom field name"},
+		{`cpu`, "missing comparison"},
+		{`name=`, "missing value"},
+		{`name=>web`, "unknown operator"},
+		{`cpu=1 and`, "unexpected end of expression"},
+	} {
+		if _, err := ParseServerFilter(tc.expr); err == nil {
+			t.Errorf("%s: expected an error", tc.expr)
+		} else if !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected error %q, got %q", tc.expr, tc.err, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package clcv2
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -29,19 +30,29 @@ const (
 // to get called until a "succeeded" or "failed" response is returned.
 // @statusID: queue ID to query (contains location ID in the format of "wa1-<number>")
 func (c *Client) GetStatus(statusID string) (status QueueStatus, err error) {
+	return c.GetStatusContext(c.ctx, statusID)
+}
+
+// GetStatusContext is like GetStatus, using @ctx for cancellation.
+func (c *Client) GetStatusContext(ctx context.Context, statusID string) (status QueueStatus, err error) {
 	var path = fmt.Sprintf("/v2/operations/%s/status/%s", c.AccountAlias, statusID)
 
 	if statusID == "" {
 		return Unknown, errors.Errorf("invalid status ID %q", statusID)
 	}
-	err = c.getCLCResponse("GET", path, nil, &struct{ Status *QueueStatus }{&status})
+	err = c.getCLCResponse(ctx, "GET", path, nil, &struct{ Status *QueueStatus }{&status})
 	return status, err
 }
 
 // PollStatus polls the queue status of @ID and logs progress to stdout.
 // NOTE: since this logs to stdout, it is suitable only for terminal-based applications!
 func (c *Client) PollStatus(statusID string, intvl time.Duration) (QueueStatus, error) {
-	return c.PollStatusFn(statusID, intvl, // periodically log to stdout
+	return c.PollStatusContext(c.ctx, statusID, intvl)
+}
+
+// PollStatusContext is like PollStatus, using @ctx for cancellation.
+func (c *Client) PollStatusContext(ctx context.Context, statusID string, intvl time.Duration) (QueueStatus, error) {
+	return c.PollStatusFnContext(ctx, statusID, intvl, // periodically log to stdout
 		func(s QueueStatus) { log.Printf("%s: %s", statusID, s) })
 }
 
@@ -50,51 +61,55 @@ func (c *Client) PollStatus(statusID string, intvl time.Duration) (QueueStatus,
 // @intvl:    wait interval between poll attemps, use 0 for one-shot operation
 // @cb:       callback to call whenever status changes during polling
 func (c *Client) PollStatusFn(statusID string, intvl time.Duration, cb func(QueueStatus)) (QueueStatus, error) {
-	for prevStatus := Unknown; ; {
-		status, err := c.GetStatus(statusID)
-		if err != nil {
-			return Unknown, errors.Errorf("failed to query queue status of %s: %s", statusID, err)
-		}
-		if status != prevStatus {
-			if cb != nil {
-				cb(status)
-			}
-			prevStatus = status
-		}
-		if intvl == 0 || status == Succeeded || status == Failed {
-			return status, nil
-		}
-		time.Sleep(intvl)
+	return c.PollStatusFnContext(c.ctx, statusID, intvl, cb)
+}
+
+// PollStatusFnContext is like PollStatusFn, using @ctx for cancellation.
+func (c *Client) PollStatusFnContext(ctx context.Context, statusID string, intvl time.Duration, cb func(QueueStatus)) (QueueStatus, error) {
+	var j = c.statusJob(statusID)
+
+	if cb != nil {
+		j.OnProgress(func(_ Job, status QueueStatus) { cb(status) })
+	}
+	if intvl == 0 {
+		return j.Poll(ctx)
 	}
+	j.interval = intvl
+	if err := j.Wait(ctx); err != nil && !errors.Is(err, ErrJobFailed) {
+		return Unknown, err
+	}
+	return j.Status(), nil
 }
 
 // AwaitCompletion waits until @statusID completes. It is meant for automated (non-interactive)
 // monitoring and thus also continually checks whether the context has been canceled (unlike PollStatus).
 // @statusID: queue ID to query
 func (c *Client) AwaitCompletion(statusID string) (QueueStatus, error) {
-	const waitIntvl = 1 * time.Second
-	var done <-chan struct{}
+	return c.AwaitCompletionContext(c.ctx, statusID)
+}
+
+// AwaitCompletionContext is like AwaitCompletion, using @ctx for cancellation.
+func (c *Client) AwaitCompletionContext(ctx context.Context, statusID string) (QueueStatus, error) {
+	var j = c.StatusJob(statusID)
 
-	if c.ctx != nil {
-		done = c.ctx.Done()
+	if err := j.Wait(ctx); errors.Is(err, ErrJobFailed) {
+		return Failed, nil
+	} else if err != nil {
+		return Unknown, err
 	}
+	return Succeeded, nil
+}
 
-	timer := time.NewTimer(waitIntvl)
+// sleepContext waits for @d to elapse, returning early with the context error if @ctx is canceled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
 	defer timer.Stop()
 
-	for {
-		select {
-		case <-done:
-			return Unknown, c.ctx.Err()
-		case <-timer.C:
-			timer.Stop()
-			if status, err := c.GetStatus(statusID); err != nil {
-				return Unknown, errors.Errorf("unable to query status of %s: %s", statusID, err)
-			} else if status == Succeeded || status == Failed {
-				return status, nil
-			}
-			timer.Reset(waitIntvl)
-		}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
 }
 
@@ -113,14 +128,15 @@ type StatusLink struct {
 
 // Like getCLCResponse, but extract the Status Id from the Links array contained in the response.
 // Accordingly, since only the status Id is returned, this function does not take a @resModel.
-func (c *Client) getStatus(verb, path string, reqModel interface{}) (statusID string, err error) {
+func (c *Client) getStatus(ctx context.Context, verb, path string, reqModel interface{}) (statusID string, err error) {
 	var sl StatusLink
 
-	if err = c.getCLCResponse(verb, path, reqModel, &sl); err == nil {
+	if err = c.getCLCResponse(ctx, verb, path, reqModel, &sl); err == nil {
 		if sl.Rel != "status" {
 			err = errors.Errorf("Link information Rel-type not set to 'status' in %+v", sl)
 		} else {
 			statusID = sl.Id
+			c.journalSubmit(verb, path, "", statusID)
 		}
 	}
 	return statusID, err
@@ -147,11 +163,11 @@ type StatusResponse struct {
 // Run an Http request and evaluate the returned %StatusResponse, return links
 // @verb, @path, @reqModel: as in getCLCResponse()
 // @useArray:               whether to expect a singleton StatusResponse, or an array with one such element
-func (c *Client) getStatusResponse(verb, path string, useArray bool, reqModel interface{}) (res StatusResponse, err error) {
+func (c *Client) getStatusResponse(ctx context.Context, verb, path string, useArray bool, reqModel interface{}) (res StatusResponse, err error) {
 	if useArray {
 		var status []StatusResponse
 
-		if err = c.getCLCResponse(verb, path, reqModel, &status); err != nil {
+		if err = c.getCLCResponse(ctx, verb, path, reqModel, &status); err != nil {
 			return res, err
 		} else if len(status) == 0 {
 			err = errors.Errorf("empty status response from server")
@@ -161,26 +177,37 @@ func (c *Client) getStatusResponse(verb, path string, useArray bool, reqModel in
 			res = status[0]
 		}
 	} else {
-		err = c.getCLCResponse(verb, path, reqModel, &res)
+		err = c.getCLCResponse(ctx, verb, path, reqModel, &res)
 	}
 
 	if err == nil {
-		if res.ErrorMessage != "" {
-			err = errors.Errorf("request on %s failed - %s", res.Server, res.ErrorMessage)
-		} else if !res.IsQueued {
-			err = errors.Errorf("request on %s was not queued", res.Server)
+		err = res.err()
+	}
+	if err == nil {
+		if link, lerr := extractLink(res.Links, "status"); lerr == nil {
+			c.journalSubmit(verb, path, res.Server, link.Id)
 		}
 	}
 	return res, err
 }
 
+// err returns an error if @r indicates that the request was not queued.
+func (r StatusResponse) err() error {
+	if r.ErrorMessage != "" {
+		return errors.Errorf("request on %s failed - %s", r.Server, r.ErrorMessage)
+	} else if !r.IsQueued {
+		return errors.Errorf("request on %s was not queued", r.Server)
+	}
+	return nil
+}
+
 // Wrap getStatusResponse() to only extract the statusID contained in the 'status' link
 // @verb, @path, @useArray, @reqModel: as in getStatusResponse
-func (c *Client) getStatusResponseId(verb, path string, useArray bool, reqModel interface{}) (statusID string, err error) {
+func (c *Client) getStatusResponseId(ctx context.Context, verb, path string, useArray bool, reqModel interface{}) (statusID string, err error) {
 	var status StatusResponse
 	var link *Link
 
-	status, err = c.getStatusResponse(verb, path, useArray, reqModel)
+	status, err = c.getStatusResponse(ctx, verb, path, useArray, reqModel)
 	if err != nil {
 		return statusID, err
 	}
@@ -0,0 +1,218 @@
+package clcv2
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EndpointFamily identifies one of the API endpoints (see Endpoints) for throttling purposes.
+type EndpointFamily string
+
+const (
+	FamilyAPI   EndpointFamily = "api"   // main CLC v2 API
+	FamilyLBaaS EndpointFamily = "lbaas" // Load Balancer as a Service API
+	FamilySBS   EndpointFamily = "sbs"   // Simple Backup Service API
+)
+
+// Limits configures client-side throttling of the requests to one endpoint family.
+// The zero value means no limits. Each retry of a failed request (see RetryPolicy) takes a token
+// from the bucket, too, but it reuses the in-flight slot of the request.
+type Limits struct {
+	// Sustained request rate (token-bucket refill rate) in requests/second; 0 means unlimited.
+	Rate float64
+
+	// Size of the token bucket, i.e. the maximum burst of requests above @Rate (at least 1).
+	Burst int
+
+	// Maximum number of requests in flight at any one time; 0 means unlimited.
+	MaxInFlight int
+}
+
+// ThrottleStats reports the queueing delay that client-side throttling imposed on requests.
+type ThrottleStats struct {
+	// Total number of requests that passed through the throttle
+	Requests uint64
+
+	// Number of requests that had to wait for a token or an in-flight slot
+	Queued uint64
+
+	// Number of requests currently in flight
+	InFlight int
+
+	// Accumulated and maximum queueing delay
+	TotalWait, MaxWait time.Duration
+}
+
+// AvgWait returns the average queueing delay per request.
+func (s ThrottleStats) AvgWait() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.TotalWait / time.Duration(s.Requests)
+}
+
+// throttle implements Limits for a single endpoint family.
+type throttle struct {
+	limits Limits
+	slots  chan struct{} // semaphore for @limits.MaxInFlight, nil if unlimited
+
+	mu     sync.Mutex
+	tokens float64   // tokens currently available in the bucket
+	last   time.Time // time of the last token update
+	stats  ThrottleStats
+}
+
+func newThrottle(l Limits) *throttle {
+	var t = &throttle{limits: l, last: time.Now()}
+
+	if t.limits.Burst < 1 {
+		t.limits.Burst = 1
+	}
+	t.tokens = float64(t.limits.Burst)
+	if l.MaxInFlight > 0 {
+		t.slots = make(chan struct{}, l.MaxInFlight)
+	}
+	return t
+}
+
+// acquire waits until a request may be sent, honouring cancellation of @ctx.
+// On success, the returned function must be called once the request has completed.
+func (t *throttle) acquire(ctx context.Context) (release func(), err error) {
+	var start = time.Now()
+
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if err = t.wait(ctx); err != nil {
+		if t.slots != nil {
+			<-t.slots
+		}
+		return nil, err
+	}
+
+	t.mu.Lock()
+	wait := time.Since(start)
+	t.stats.Requests++
+	t.stats.InFlight++
+	t.stats.TotalWait += wait
+	if wait > time.Millisecond {
+		t.stats.Queued++
+	}
+	if wait > t.stats.MaxWait {
+		t.stats.MaxWait = wait
+	}
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			t.stats.InFlight--
+			t.mu.Unlock()
+			if t.slots != nil {
+				<-t.slots
+			}
+		})
+	}, nil
+}
+
+// wait waits until a token is available, and takes it.
+func (t *throttle) wait(ctx context.Context) error {
+	for t.limits.Rate > 0 {
+		delay := t.reserve()
+		if delay == 0 {
+			break
+		} else if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reserve takes a token from the bucket if one is available, and returns 0.
+// Otherwise it returns the time until the next token becomes available.
+func (t *throttle) reserve() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.tokens += now.Sub(t.last).Seconds() * t.limits.Rate
+	if max := float64(t.limits.Burst); t.tokens > max {
+		t.tokens = max
+	}
+	t.last = now
+
+	if t.tokens >= 1 {
+		t.tokens--
+		return 0
+	}
+	return time.Duration((1 - t.tokens) / t.limits.Rate * float64(time.Second))
+}
+
+// snapshot returns a copy of the current statistics of @t.
+func (t *throttle) snapshot() ThrottleStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
+
+// throttledRequest tracks the attempts of a request that has passed through @throttle.
+type throttledRequest struct {
+	throttle *throttle
+	attempts int32
+}
+
+// throttledRequestKey is the context key of the *throttledRequest of a request.
+type throttledRequestKey struct{}
+
+// retryThrottle makes the retries of throttled requests wait for a token, since they bypass acquire().
+// It sits underneath the retrying transport, so that it sees each attempt.
+type retryThrottle struct {
+	http.RoundTripper
+}
+
+func (t retryThrottle) RoundTrip(req *http.Request) (*http.Response, error) {
+	if tr, ok := req.Context().Value(throttledRequestKey{}).(*throttledRequest); ok && atomic.AddInt32(&tr.attempts, 1) > 1 {
+		if err := tr.throttle.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+// throttleFor returns the throttle of @family, creating it on first use.
+func (c *Client) throttleFor(family EndpointFamily) *throttle {
+	c.throttleMu.Lock()
+	defer c.throttleMu.Unlock()
+
+	if c.throttles == nil {
+		c.throttles = make(map[EndpointFamily]*throttle)
+	}
+	t, ok := c.throttles[family]
+	if !ok {
+		t = newThrottle(c.limits[family])
+		c.throttles[family] = t
+	}
+	return t
+}
+
+// ThrottleStats returns the queueing statistics of each endpoint family used so far.
+func (c *Client) ThrottleStats() map[EndpointFamily]ThrottleStats {
+	var res = make(map[EndpointFamily]ThrottleStats)
+
+	c.throttleMu.Lock()
+	defer c.throttleMu.Unlock()
+
+	for family, t := range c.throttles {
+		res[family] = t.snapshot()
+	}
+	return res
+}
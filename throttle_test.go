@@ -0,0 +1,188 @@
+package clcv2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// The bucket admits @Burst requests at once, and refills at @Rate.
+func TestThrottleTokenBucket(t *testing.T) {
+	var th = newThrottle(Limits{Rate: 20, Burst: 2})
+	var start = time.Now()
+
+	for i := 0; i < 6; i++ {
+		release, err := th.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("acquire %d failed: %s", i, err)
+		}
+		release()
+
+		if elapsed := time.Since(start); i < 2 && elapsed > 25*time.Millisecond {
+			t.Errorf("request %d of the burst was delayed by %s", i, elapsed)
+		}
+	}
+	// The 4 requests beyond the burst need 4 tokens at 50ms each.
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("expected 6 requests to take about 200ms, took %s", elapsed)
+	}
+	if s := th.snapshot(); s.Requests != 6 || s.Queued != 4 || s.InFlight != 0 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+
+	// A canceled caller gives up waiting for a token.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	th = newThrottle(Limits{Rate: 0.1})
+	if release, err := th.acquire(ctx); err != nil {
+		t.Fatalf("first acquire failed: %s", err)
+	} else {
+		release()
+	}
+	if _, err := th.acquire(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected the deadline to expire, got %v", err)
+	}
+}
+
+// holdServer is a stand-in API server which holds each request to /hold until it is released.
+type holdServer struct {
+	*httptest.Server
+
+	started chan string   // receives the path of each request as it arrives
+	release chan struct{} // each value releases one held request
+	retries int32         // number of requests to /retry
+}
+
+func newHoldServer() *holdServer {
+	var hs = &holdServer{started: make(chan string, 16), release: make(chan struct{})}
+
+	hs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/authentication/login":
+			json.NewEncoder(w).Encode(LoginRes{User: "user", AccountAlias: "ABCD", LocationAlias: "WA1", BearerToken: "token"})
+			return
+		case "/hold":
+			hs.started <- r.URL.Path
+			select {
+			case <-hs.release:
+			case <-time.After(5 * time.Second):
+			}
+		case "/retry":
+			// Fail the first 2 attempts.
+			if atomic.AddInt32(&hs.retries, 1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		default:
+			hs.started <- r.URL.Path
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	return hs
+}
+
+// arrived returns the path of the next request that arrives at @hs within @d, or "" if none does.
+func (hs *holdServer) arrived(d time.Duration) string {
+	select {
+	case path := <-hs.started:
+		return path
+	case <-time.After(d):
+		return ""
+	}
+}
+
+// Request N+1 to a family with MaxInFlight N waits until one of the N requests in flight completes,
+// while requests to other families are not affected.
+func TestThrottleMaxInFlight(t *testing.T) {
+	const maxInFlight = 3
+	var hs = newHoldServer()
+	defer hs.Close()
+
+	c, err := NewClientWithOptions(
+		WithCredentials("user", "pass"),
+		WithEndpoints(Endpoints{API: hs.URL}),
+		WithLimits(FamilyAPI, Limits{MaxInFlight: maxInFlight}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	var done = make(chan error, maxInFlight+1)
+	var get = func(family EndpointFamily, path string) {
+		done <- c.getResponse(context.Background(), family, hs.URL+path, "GET", nil, new(struct{}))
+	}
+
+	for i := 0; i < maxInFlight; i++ {
+		go get(FamilyAPI, "/hold")
+		if hs.arrived(5*time.Second) == "" {
+			t.Fatalf("request %d did not arrive", i+1)
+		}
+	}
+	go get(FamilyAPI, "/hold")
+	if path := hs.arrived(100 * time.Millisecond); path != "" {
+		t.Fatalf("request %d was not held back", maxInFlight+1)
+	}
+	if s := c.ThrottleStats()[FamilyAPI]; s.InFlight != maxInFlight {
+		t.Errorf("expected %d requests in flight, got %d", maxInFlight, s.InFlight)
+	}
+
+	// Other families have their own limits.
+	go get(FamilyLBaaS, "/lbaas")
+	if path := hs.arrived(5 * time.Second); path != "/lbaas" {
+		t.Fatalf("LBaaS request was held back by the API limit")
+	} else if err := <-done; err != nil {
+		t.Errorf("LBaaS request failed: %s", err)
+	}
+
+	// Releasing one of the requests admits the waiting one.
+	hs.release <- struct{}{}
+	if hs.arrived(5*time.Second) == "" {
+		t.Fatalf("request %d was not admitted after a request completed", maxInFlight+1)
+	}
+	for i := 0; i < maxInFlight; i++ {
+		hs.release <- struct{}{}
+	}
+	for i := 0; i < maxInFlight+1; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("request failed: %s", err)
+		}
+	}
+
+	if s := c.ThrottleStats()[FamilyAPI]; s.InFlight != 0 || s.Queued != 1 || s.MaxWait < 100*time.Millisecond {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+// Retries of a request take a token each.
+func TestThrottleRetries(t *testing.T) {
+	var hs = newHoldServer()
+	var policy = DefaultRetryPolicy()
+	defer hs.Close()
+
+	policy.Backoff = func(int) time.Duration { return time.Millisecond }
+	c, err := NewClientWithOptions(
+		WithCredentials("user", "pass"),
+		WithEndpoints(Endpoints{API: hs.URL}),
+		WithRetryPolicy(policy),
+		WithLimits(FamilyAPI, Limits{Rate: 10}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+
+	// The login has used up the only token; each of the 3 attempts needs a new one, at 100ms each.
+	start := time.Now()
+	if err := c.getResponse(context.Background(), FamilyAPI, hs.URL+"/retry", "GET", nil, new(struct{})); err != nil {
+		t.Fatalf("request failed: %s", err)
+	} else if n := atomic.LoadInt32(&hs.retries); n != 3 {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("expected the retries to be throttled, took only %s", elapsed)
+	}
+}
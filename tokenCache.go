@@ -0,0 +1,208 @@
+package clcv2
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Directory in CLC_HOME that holds the cached bearer tokens
+	tokensDir = "tokens"
+
+	// File in CLC_HOME in which earlier versions kept the (cleartext) credentials of the last login
+	legacyCredentialsName = "credentials.json"
+
+	// Cached tokens that expire within this margin are not used
+	tokenExpiryMargin = 5 * time.Minute
+)
+
+// DefaultTokenLifetime is the assumed validity of bearer tokens whose expiry can not be determined.
+var DefaultTokenLifetime = 14 * 24 * time.Hour
+
+// Characters not allowed in token cache file names
+var tokenKeyRegexp = regexp.MustCompile(`[^A-Za-z0-9@._-]`)
+
+// cachedToken is the on-disk format of a bearer token cache entry.
+type cachedToken struct {
+	// Login response, the BearerToken is empty if kept in the SecretStore
+	Credentials LoginRes `json:"credentials"`
+
+	// API endpoint the token belongs to
+	Endpoint string `json:"endpoint"`
+
+	// Expiry time of the token
+	Expires time.Time `json:"expires"`
+}
+
+// valid returns true if @t holds a token that does not expire soon.
+func (t *cachedToken) valid() bool {
+	return t != nil && t.Credentials.BearerToken != "" && time.Until(t.Expires) > tokenExpiryMargin
+}
+
+// tokenCache caches bearer tokens in CLC_HOME, keyed by user and API endpoint (see tokenKey), so that
+// concurrent processes can share a token. Entries are replaced atomically, and updates are serialized
+// by an advisory lock per entry. The tokens themselves are kept in @secrets, unless it is read-only.
+type tokenCache struct {
+	dir     string
+	secrets SecretStore
+
+	// Path of the legacy credentials file (see importLegacy)
+	legacyPath string
+}
+
+// newTokenCache returns the token cache in CLC_HOME that uses @secrets.
+func newTokenCache(secrets SecretStore) *tokenCache {
+	return &tokenCache{
+		dir:        path.Join(GetClcHome(), tokensDir),
+		secrets:    secrets,
+		legacyPath: path.Join(GetClcHome(), legacyCredentialsName),
+	}
+}
+
+// update calls @fn with the token cached under @key (nil if none), while holding the lock of @key.
+// If @fn returns a non-nil token, it replaces the cached entry.
+func (tc *tokenCache) update(key string, fn func(cached *cachedToken) (*cachedToken, error)) error {
+	unlock, err := lockFile(tc.path(key) + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cached, err := tc.load(key)
+	if err != nil {
+		return err
+	}
+	fresh, err := fn(cached)
+	if err != nil || fresh == nil {
+		return err
+	}
+	return tc.store(key, fresh)
+}
+
+// remove removes the entry @key from the cache.
+func (tc *tokenCache) remove(key string) error {
+	unlock, err := lockFile(tc.path(key) + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.Remove(tc.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	} else if err = tc.secrets.Set("token/"+key, ""); err != nil && err != ErrReadOnlySecretStore {
+		return err
+	}
+	return nil
+}
+
+// load returns the token cached under @key, or nil if there is none.
+func (tc *tokenCache) load(key string) (*cachedToken, error) {
+	var t = new(cachedToken)
+
+	content, err := ioutil.ReadFile(tc.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Errorf("failed to load cached token: %s", err)
+	} else if err = json.Unmarshal(content, t); err != nil {
+		return nil, errors.Errorf("failed to deserialize %s: %s", tc.path(key), err)
+	}
+
+	if t.Credentials.BearerToken == "" { // kept in the secret store
+		if t.Credentials.BearerToken, err = tc.secrets.Get("token/" + key); err != nil {
+			return nil, errors.Errorf("failed to retrieve bearer token: %s", err)
+		}
+	}
+	return t, nil
+}
+
+// importLegacy returns the token of @user in the credentials file of earlier versions, for use at @apiURL
+// (the file does not record the endpoint; if the token is rejected, the client logs in again).
+// Since the file holds the token in cleartext, it is removed once read. The file is left alone,
+// and nil is returned, if it does not exist or belongs to another user.
+func (tc *tokenCache) importLegacy(user, apiURL string) (*cachedToken, error) {
+	var creds LoginRes
+
+	content, err := ioutil.ReadFile(tc.legacyPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Errorf("failed to load legacy credentials: %s", err)
+	} else if err = json.Unmarshal(content, &creds); err != nil {
+		return nil, errors.Errorf("failed to deserialize %s: %s", tc.legacyPath, err)
+	} else if !strings.EqualFold(creds.User, user) {
+		return nil, nil
+	} else if err = os.Remove(tc.legacyPath); err != nil {
+		return nil, errors.Errorf("failed to remove legacy credentials: %s", err)
+	}
+	return newCachedToken(creds, apiURL), nil
+}
+
+// store atomically replaces the entry @key with @t.
+func (tc *tokenCache) store(key string, t *cachedToken) error {
+	var saved = *t
+
+	if err := tc.secrets.Set("token/"+key, t.Credentials.BearerToken); err == nil {
+		saved.Credentials.BearerToken = ""
+	} else if err != ErrReadOnlySecretStore {
+		return errors.Errorf("failed to store bearer token: %s", err)
+	}
+
+	enc, err := json.MarshalIndent(&saved, "", "\t")
+	if err != nil {
+		return errors.Errorf("failed to serialize bearer credentials: %s", err)
+	}
+	return writeFileAtomic(tc.path(key), append(enc, '\n'), 0600)
+}
+
+// path returns the path of the cache file of @key.
+func (tc *tokenCache) path(key string) string {
+	return path.Join(tc.dir, key+".json")
+}
+
+// tokenKey returns the token cache key of @user at the API endpoint @apiURL.
+// The account is deliberately not part of the key: a bearer token authenticates the user, and is
+// valid for all accounts the user has access to. Hence profiles of the same user share a token.
+func tokenKey(user, apiURL string) string {
+	var host = apiURL
+
+	if u, err := url.Parse(apiURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return tokenKeyRegexp.ReplaceAllString(strings.ToLower(user)+"@"+strings.ToLower(host), "_")
+}
+
+// newCachedToken returns a cache entry for @creds, obtained from @apiURL.
+func newCachedToken(creds LoginRes, apiURL string) *cachedToken {
+	return &cachedToken{
+		Credentials: creds,
+		Endpoint:    apiURL,
+		Expires:     tokenExpiry(creds.BearerToken),
+	}
+}
+
+// tokenExpiry returns the expiry time of @token. CLC bearer tokens are JWTs, which carry their
+// expiry in the 'exp' claim. If that can not be determined, %DefaultTokenLifetime is assumed.
+func tokenExpiry(token string) time.Time {
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+
+	if parts := strings.Split(token, "."); len(parts) == 3 {
+		if payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "=")); err == nil {
+			if json.Unmarshal(payload, &claims) == nil && claims.Exp > 0 {
+				return time.Unix(claims.Exp, 0)
+			}
+		}
+	}
+	return time.Now().Add(DefaultTokenLifetime)
+}
@@ -0,0 +1,175 @@
+package clcv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memSecretStore is a SecretStore in memory.
+type memSecretStore struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+func newMemSecretStore() *memSecretStore {
+	return &memSecretStore{secrets: make(map[string]string)}
+}
+
+func (s *memSecretStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.secrets[key], nil
+}
+
+func (s *memSecretStore) Set(key, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if secret == "" {
+		delete(s.secrets, key)
+	} else {
+		s.secrets[key] = secret
+	}
+	return nil
+}
+
+// Concurrent updates of an entry are serialized, and readers never see a partially written entry.
+func TestTokenCacheConcurrentWrites(t *testing.T) {
+	withClcHome(t, func(dir string) {
+		const writers = 20
+		var tc = newTokenCache(newMemSecretStore())
+		var key = tokenKey("user", DefaultEndpoints.API)
+		var inside, updates int32
+		var wg sync.WaitGroup
+
+		for i := 0; i < writers; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				err := tc.update(key, func(cached *cachedToken) (*cachedToken, error) {
+					if atomic.AddInt32(&inside, 1) != 1 {
+						t.Errorf("concurrent update of the same entry")
+					}
+					defer atomic.AddInt32(&inside, -1)
+
+					atomic.AddInt32(&updates, 1)
+					time.Sleep(time.Millisecond)
+					return newCachedToken(LoginRes{User: "user", BearerToken: fmt.Sprintf("token-%d", i)}, DefaultEndpoints.API), nil
+				})
+				if err != nil {
+					t.Errorf("update %d failed: %s", i, err)
+				}
+			}(i)
+			go func() {
+				defer wg.Done()
+				if _, err := tc.load(key); err != nil {
+					t.Errorf("load failed: %s", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if updates != writers {
+			t.Errorf("expected %d updates, got %d", writers, updates)
+		}
+		if cached, err := tc.load(key); err != nil || !cached.valid() {
+			t.Errorf("expected a valid entry, got %+v, %v", cached, err)
+		}
+		// The token is kept in the secret store, not in the cache file.
+		if content, err := ioutil.ReadFile(tc.path(key)); err != nil {
+			t.Fatal(err)
+		} else if !json.Valid(content) || containsToken(content) {
+			t.Errorf("unexpected cache file content %s", content)
+		}
+	})
+}
+
+// containsToken returns true if the cache file @content holds a bearer token.
+func containsToken(content []byte) bool {
+	var t cachedToken
+
+	return json.Unmarshal(content, &t) != nil || t.Credentials.BearerToken != ""
+}
+
+// The cleartext credentials file of earlier versions is moved into the cache, if it belongs to the user.
+func TestTokenCacheImportLegacy(t *testing.T) {
+	withClcHome(t, func(dir string) {
+		var tc = newTokenCache(newMemSecretStore())
+		var token = testToken("legacy", time.Now().Add(time.Hour))
+		var legacy = path.Join(dir, legacyCredentialsName)
+
+		enc, _ := json.Marshal(LoginRes{User: "User", AccountAlias: "ABCD", BearerToken: token})
+		if err := ioutil.WriteFile(legacy, enc, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if cached, err := tc.importLegacy("other", DefaultEndpoints.API); err != nil || cached != nil {
+			t.Errorf("expected no token for another user, got %+v, %v", cached, err)
+		} else if _, err := os.Stat(legacy); err != nil {
+			t.Errorf("legacy file of another user was removed: %s", err)
+		}
+
+		cached, err := tc.importLegacy("user", DefaultEndpoints.API)
+		if err != nil {
+			t.Fatalf("import failed: %s", err)
+		} else if !cached.valid() || cached.Credentials.BearerToken != token || cached.Credentials.AccountAlias != "ABCD" {
+			t.Errorf("unexpected imported token %+v", cached)
+		}
+		if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+			t.Errorf("expected the legacy file to be removed, got %v", err)
+		}
+	})
+}
+
+// The cache is not locked during the login, and a token cached by another process in the meantime is adopted.
+func TestRefreshCredentialsUnlocked(t *testing.T) {
+	withClcHome(t, func(dir string) {
+		var other = testToken("other", time.Now().Add(time.Hour))
+		var key string
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var tc = newTokenCache(&CommandSecretStore{GetCommand: "exit 1"}) // read-only, as below
+			var done = make(chan error, 1)
+
+			// Another process, which logs in at the same time.
+			go func() {
+				done <- tc.update(key, func(*cachedToken) (*cachedToken, error) {
+					return newCachedToken(LoginRes{User: "user", BearerToken: other}, "https://elsewhere"), nil
+				})
+			}()
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Errorf("concurrent update failed: %s", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Errorf("token cache is locked during the login")
+			}
+			json.NewEncoder(w).Encode(LoginRes{User: "user", BearerToken: testToken("mine", time.Now().Add(time.Hour))})
+		}))
+		defer ts.Close()
+		key = tokenKey("user", ts.URL)
+
+		c, err := NewCLIClient(&ClientConfig{
+			Username: "user",
+			Password: "pass",
+			Account:  "ABCD",
+			Location: "WA1",
+			Secrets:  SecretConfig{Backend: SecretBackendCommand, Command: "exit 1"},
+		}, WithEndpoints(Endpoints{API: ts.URL}))
+		if err != nil {
+			t.Fatalf("NewCLIClient failed: %s", err)
+		}
+		if token := c.loginCredentials().BearerToken; token != other {
+			t.Errorf("expected the token of the other process, got %q", token)
+		}
+	})
+}
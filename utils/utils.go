@@ -36,16 +36,24 @@ func ExtractLocationFromServerName(serverName string) string {
 	return ""
 }
 
+// EnvUsername returns the username set in the environment: CLC_USER, or else CLC_USERNAME.
+func EnvUsername() string {
+	if username := os.Getenv("CLC_USER"); username != "" {
+		return username
+	}
+	return os.Getenv("CLC_USERNAME")
+}
+
 // ResolveUserAndPass supports multiple ways of resolving the username and password
 // 1. directly (pass-through),
 // 2. command-line flags (g_user, g_pass),
-// 3. environment variables (CLC_USERNAME, CLC_PASSWORD),
+// 3. environment variables (see EnvUsername, and CLC_PASSWORD),
 // 4. prompt for values
 func ResolveUserAndPass(userDefault, passDefault string) (username, password string) {
 	var promptStr string = "Username"
 
 	if username = userDefault; username == "" {
-		username = os.Getenv("CLC_USERNAME")
+		username = EnvUsername()
 	}
 	if username == "" {
 		username = prompt.StringRequired(promptStr)
@@ -3,7 +3,10 @@ package clcv2
 /*
  * Site-to-Site VPNs
  */
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // SiteToSiteVPN represents CLCv2 Site-to-Site VPN information.
 type SiteToSiteVPN struct {
@@ -117,12 +120,22 @@ type IPsecEntity struct {
 
 // GetVPNs returns the list of site-to-site VPNs associated with the given client AccountAlias.
 func (c *Client) GetVPNs() (res []SiteToSiteVPN, err error) {
-	err = c.getCLCResponse("GET", fmt.Sprintf("/v2/siteToSiteVpn?account=%s", c.AccountAlias), nil, &res)
+	return c.GetVPNsContext(c.ctx)
+}
+
+// GetVPNsContext is like GetVPNs, using @ctx for cancellation.
+func (c *Client) GetVPNsContext(ctx context.Context) (res []SiteToSiteVPN, err error) {
+	err = c.getCLCResponse(ctx, "GET", fmt.Sprintf("/v2/siteToSiteVpn?account=%s", c.AccountAlias), nil, &res)
 	return res, err
 }
 
 // GetVPN returns details of the specified Site-to-Site VPN.
 func (c *Client) GetVPN(vpnID string) (res SiteToSiteVPN, err error) {
-	err = c.getCLCResponse("GET", fmt.Sprintf("/v2/siteToSiteVpn/%s?account=%s", vpnID, c.credentials.AccountAlias), nil, &res)
+	return c.GetVPNContext(c.ctx, vpnID)
+}
+
+// GetVPNContext is like GetVPN, using @ctx for cancellation.
+func (c *Client) GetVPNContext(ctx context.Context, vpnID string) (res SiteToSiteVPN, err error) {
+	err = c.getCLCResponse(ctx, "GET", fmt.Sprintf("/v2/siteToSiteVpn/%s?account=%s", vpnID, c.AccountAlias), nil, &res)
 	return res, err
 }